@@ -0,0 +1,41 @@
+import React from 'react';
+
+// Only thumbnails served by dispatch are shown, that way the browser never
+// connects to the sites the links point to
+const LinkPreview = ({ meta }) => {
+  const style = {};
+  if (meta.color) {
+    style.borderColor = meta.color;
+  }
+
+  return (
+    <a
+      className="message-preview"
+      href={meta.URL}
+      target="_blank"
+      rel="noopener noreferrer"
+      style={style}
+    >
+      {meta.thumbnailURL && (
+        <img
+          className="message-preview-image"
+          src={meta.thumbnailURL}
+          alt=""
+        />
+      )}
+      <span className="message-preview-text">
+        {meta.siteName && (
+          <span className="message-preview-site">{meta.siteName}</span>
+        )}
+        <span className="message-preview-title">{meta.title || meta.URL}</span>
+        {meta.description && (
+          <span className="message-preview-description">
+            {meta.description}
+          </span>
+        )}
+      </span>
+    </a>
+  );
+};
+
+export default LinkPreview;
@@ -2,6 +2,7 @@ import React, { memo } from 'react';
 import classnames from 'classnames';
 import Text from 'components/Text';
 import stringToRGB from 'utils/color';
+import LinkPreview from './LinkPreview';
 
 const Message = ({ message, coloredNick, onNickClick }) => {
   const className = classnames('message', {
@@ -43,6 +44,9 @@ const Message = ({ message, coloredNick, onNickClick }) => {
         {' '}
         <Text coloredNick={coloredNick}>{message.content}</Text>
       </span>
+      {message.linkMeta?.map(meta => (
+        <LinkPreview key={meta.URL} meta={meta} />
+      ))}
     </p>
   );
 };
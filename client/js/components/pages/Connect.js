@@ -7,6 +7,7 @@ import Button from 'components/ui/Button';
 import Checkbox from 'components/ui/formik/Checkbox';
 import TextInput from 'components/ui/TextInput';
 import Error from 'components/ui/formik/Error';
+import { getNetworkID } from 'state/networks';
 import { isValidNick, isValidChannel, isValidUsername, isInt } from 'utils';
 
 const getSortedDefaultChannels = createSelector(
@@ -34,6 +35,18 @@ const transformChannels = channels => {
   return comma ? `${channels},` : channels;
 };
 
+const findPreset = (presets, host) => presets.find(p => p.host === host);
+
+const presetValues = preset => ({
+  name: preset.name || preset.host,
+  host: preset.host,
+  port: preset.port || (preset.tls ? '6697' : '6667'),
+  tls: preset.tls || false,
+  channels: (preset.channels || []).join(','),
+  // The server fills in the password of the preset
+  serverPassword: ''
+});
+
 class Connect extends Component {
   state = {
     showOptionals: false
@@ -48,20 +61,54 @@ class Connect extends Component {
     }
   };
 
+  handlePresetChange = e => {
+    const { presets, values, setValues } = this.props;
+    const preset = presets[e.target.value];
+
+    if (preset) {
+      setValues({ ...values, ...presetValues(preset) });
+    } else {
+      setValues({ ...values, name: '', host: '', serverPassword: '' });
+    }
+  };
+
   handleShowClick = () => {
     this.setState(prevState => ({ showOptionals: !prevState.showOptionals }));
   };
 
-  renderOptionals = () => {
+  renderSASL = () => (
+    <div className="connect-section">
+      <h2>SASL</h2>
+      <TextInput name="account" />
+      <TextInput name="password" type="password" noTrim />
+    </div>
+  );
+
+  renderPresets = preset => {
+    const { presets, networkAllowlist } = this.props;
+
+    return (
+      <select
+        className="connect-form-preset"
+        value={preset ? presets.indexOf(preset) : ''}
+        onChange={this.handlePresetChange}
+      >
+        {presets.map((p, i) => (
+          <option key={p.host} value={i}>
+            {p.name || p.host}
+          </option>
+        ))}
+        {!networkAllowlist && <option value="">Other network</option>}
+      </select>
+    );
+  };
+
+  renderOptionals = requireSASL => {
     const { hexIP } = this.props;
 
     return (
       <>
-        <div className="connect-section">
-          <h2>SASL</h2>
-          <TextInput name="account" />
-          <TextInput name="password" type="password" noTrim />
-        </div>
+        {!requireSASL && this.renderSASL()}
         {!hexIP && <TextInput name="username" />}
         <TextInput
           name="serverPassword"
@@ -82,8 +129,9 @@ class Connect extends Component {
   };
 
   render() {
-    const { defaults, values } = this.props;
+    const { defaults, presets, networkAllowlist, values } = this.props;
     const { readOnly, showDetails } = defaults;
+    const preset = findPreset(presets, values.host);
     let form;
 
     if (readOnly) {
@@ -108,28 +156,33 @@ class Connect extends Component {
       form = (
         <Form className="connect-form">
           <h1>Connect</h1>
+          {presets.length > 0 && this.renderPresets(preset)}
           <TextInput name="name" autoCapitalize="words" noTrim />
-          <div className="connect-form-address">
-            <TextInput name="host" noError />
-            <TextInput
-              name="port"
-              type="number"
-              blurTransform={this.transformPort}
-              noError
-            />
-            <Checkbox
-              classNameLabel="connect-form-ssl"
-              name="tls"
-              label="SSL"
-              topLabel
-              onChange={this.handleSSLChange}
-            />
-          </div>
+          {!networkAllowlist && !(preset && preset.readOnly) && (
+            <div className="connect-form-address">
+              <TextInput name="host" noError />
+              <TextInput
+                name="port"
+                type="number"
+                blurTransform={this.transformPort}
+                noError
+              />
+              <Checkbox
+                classNameLabel="connect-form-ssl"
+                name="tls"
+                label="SSL"
+                topLabel
+                onChange={this.handleSSLChange}
+              />
+            </div>
+          )}
           <Error name="host" />
           <Error name="port" />
           <TextInput name="nick" />
           <TextInput name="channels" transform={transformChannels} />
-          {this.state.showOptionals && this.renderOptionals()}
+          {preset && preset.requireSASL && this.renderSASL()}
+          {this.state.showOptionals &&
+            this.renderOptionals(preset && preset.requireSASL)}
           <Button
             className="connect-form-button-optionals"
             icon={FiMoreHorizontal}
@@ -152,7 +205,24 @@ class Connect extends Component {
 
 export default withFormik({
   enableReinitialize: true,
-  mapPropsToValues: ({ defaults, query }) => {
+  mapPropsToValues: ({ defaults, presets, networkAllowlist, query }) => {
+    // Only the presets can be connected to when the allowlist is on
+    if (networkAllowlist && presets.length > 0) {
+      const preset =
+        findPreset(presets, query.host) ||
+        findPreset(presets, defaults.host) ||
+        presets[0];
+
+      return {
+        ...presetValues(preset),
+        nick: query.nick || localStorage.lastNick || '',
+        account: '',
+        password: '',
+        username: query.username || '',
+        realname: query.realname || localStorage.lastRealname || ''
+      };
+    }
+
     let port = '6667';
     if (query.port || defaults.port) {
       port = query.port || defaults.port;
@@ -227,16 +297,17 @@ export default withFormik({
     return errors;
   },
   handleSubmit: (values, { props }) => {
-    const { connect, select, join } = props;
+    const { connect, select, join, networks } = props;
     const channels = values.channels ? values.channels.split(',') : [];
     delete values.channels;
 
     values.port = `${values.port}`;
+    values.id = getNetworkID(networks);
     connect(values);
-    select(values.host);
+    select(values.id);
 
     if (channels.length > 0) {
-      join(channels, values.host, false);
+      join(channels, values.id, false);
     }
 
     localStorage.lastNick = values.nick;
@@ -8,7 +8,11 @@ const Settings = ({
   settings,
   installable,
   version,
+  pushAvailable,
+  pushEnabled,
+  pushError,
   setSetting,
+  onPushChange,
   onCertChange,
   onKeyChange,
   onInstall,
@@ -44,7 +48,25 @@ const Settings = ({
             checked={!!settings.coloredNicks}
             onChange={e => setSetting('coloredNicks', e.target.checked)}
           />
+          <Checkbox
+            name="linkPreviews"
+            label="Link previews"
+            checked={!!settings.linkPreviews}
+            onChange={e => setSetting('linkPreviews', e.target.checked)}
+          />
         </div>
+        {pushAvailable && (
+          <div className="settings-section">
+            <h2>Notifications</h2>
+            <Checkbox
+              name="push"
+              label="Push notifications"
+              checked={pushEnabled}
+              onChange={e => onPushChange(e.target.checked)}
+            />
+            {pushError && <p className="error">{pushError}</p>}
+          </div>
+        )}
         <div className="settings-section">
           <h2>Client Certificate</h2>
           <div className="settings-cert">
@@ -1,14 +1,17 @@
 import { createStructuredSelector } from 'reselect';
 import Connect from 'components/pages/Connect';
-import { getConnectDefaults, getApp } from 'state/app';
+import { getConnectDefaults, getConnectPresets, getApp } from 'state/app';
 import { join } from 'state/channels';
-import { connect as connectNetwork } from 'state/networks';
+import { connect as connectNetwork, getNetworks } from 'state/networks';
 import { select } from 'state/tab';
 import connect from 'utils/connect';
 
 const mapState = createStructuredSelector({
   defaults: getConnectDefaults,
+  presets: getConnectPresets,
+  networkAllowlist: state => getApp(state).networkAllowlist,
   hexIP: state => getApp(state).hexIP,
+  networks: getNetworks,
   query: state => state.router.query
 });
 
@@ -1,6 +1,11 @@
 import { createStructuredSelector } from 'reselect';
 import Settings from 'components/pages/Settings';
-import { appSet } from 'state/app';
+import {
+  appSet,
+  disablePush,
+  enablePush,
+  getVAPIDPublicKey
+} from 'state/app';
 import {
   getSettings,
   setSetting,
@@ -9,11 +14,15 @@ import {
   uploadCert
 } from 'state/settings';
 import connect from 'utils/connect';
+import { pushSupported } from 'utils/push';
 
 const mapState = createStructuredSelector({
   settings: getSettings,
   installable: state => state.app.installable,
-  version: state => state.app.version
+  version: state => state.app.version,
+  pushAvailable: state => pushSupported() && !!getVAPIDPublicKey(state),
+  pushEnabled: state => state.app.pushEnabled,
+  pushError: state => state.app.pushError
 });
 
 const mapDispatch = {
@@ -21,6 +30,7 @@ const mapDispatch = {
   onKeyChange: setKey,
   uploadCert,
   setSetting,
+  onPushChange: enabled => (enabled ? enablePush() : disablePush()),
   onInstall: () => appSet('installable', null)
 };
 
@@ -1,6 +1,7 @@
 import documentTitle from './documentTitle';
 import fonts from './fonts';
 import initialState from './initialState';
+import push from './push';
 import route from './route';
 import socket from './socket';
 import storage from './storage';
@@ -12,6 +13,7 @@ export default function runModules(ctx) {
   route(ctx);
 
   documentTitle(ctx);
+  push(ctx);
   socket(ctx);
   storage(ctx);
   widthUpdates(ctx);
@@ -1,7 +1,7 @@
 import { INIT } from 'state/actions';
 import { getConnected, getWrapWidth } from 'state/app';
 import { searchChannels } from 'state/channelSearch';
-import { addMessages } from 'state/messages';
+import { addMessages, withLinkMeta } from 'state/messages';
 import { when } from 'utils/observe';
 
 function loadState({ store }, env) {
@@ -14,9 +14,12 @@ function loadState({ store }, env) {
     users: env.users,
     app: {
       connectDefaults: env.defaults,
+      connectPresets: env.presets || [],
+      networkAllowlist: env.networkAllowlist || false,
       initialized: true,
       hexIP: env.hexIP,
-      version: env.version
+      version: env.version,
+      vapidPublicKey: env.vapidPublicKey || ''
     }
   });
 
@@ -24,16 +27,18 @@ function loadState({ store }, env) {
     // Wait until wrapWidth gets initialized so that height calculations
     // only happen once for these messages
     when(store, getWrapWidth, () => {
-      const { messages, network, to, next } = env.messages;
-      store.dispatch(addMessages(messages, network, to, false, next));
+      const { messages, network, to, next, linkMeta } = env.messages;
+      store.dispatch(
+        addMessages(withLinkMeta(messages, linkMeta), network, to, false, next)
+      );
     });
   }
 
   if (env.networks) {
     when(store, getConnected, () =>
       // Cache top channels for each network
-      env.networks.forEach(({ host }) =>
-        store.dispatch(searchChannels(host, ''))
+      env.networks.forEach(({ host, id = host }) =>
+        store.dispatch(searchChannels(id, ''))
       )
     );
   }
@@ -0,0 +1,29 @@
+import { enablePush, getVAPIDPublicKey } from 'state/app';
+import { getPushSubscription, pushSupported } from 'utils/push';
+import { when } from 'utils/observe';
+
+// Browsers that subscribed before keep getting pushes, the subscription gets
+// sent again in case the server lost it or its key changed
+export default function push({ store }) {
+  if (!pushSupported()) {
+    return;
+  }
+
+  when(
+    store,
+    state => state.app.initialized && state.app.connected,
+    async () => {
+      if (
+        !getVAPIDPublicKey(store.getState()) ||
+        window.Notification.permission !== 'granted'
+      ) {
+        return;
+      }
+
+      const sub = await getPushSubscription();
+      if (sub) {
+        store.dispatch(enablePush());
+      }
+    }
+  );
+}
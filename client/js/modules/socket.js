@@ -5,7 +5,9 @@ import {
   addMessage,
   addMessages,
   addEvent,
-  broadcastEvent
+  addLinkMeta,
+  broadcastEvent,
+  withLinkMeta
 } from 'state/messages';
 import { openModal } from 'state/modals';
 import { reconnect } from 'state/networks';
@@ -39,22 +41,49 @@ export default function handleSocket({
       return false;
     },
 
-    messages({ messages, network, to, prepend, next }) {
-      dispatch(addMessages(messages, network, to, prepend, next));
+    messages({ messages, network, to, prepend, next, linkMeta }) {
+      dispatch(
+        addMessages(
+          withLinkMeta(messages, linkMeta),
+          network,
+          to,
+          prepend,
+          next
+        )
+      );
       return false;
     },
 
-    join({ user, network, channels }) {
-      dispatch(addEvent(network, channels[0], 'join', user));
+    link_meta({ network, to, id, meta }) {
+      dispatch(addLinkMeta(network, to, id, meta));
+      return false;
     },
 
-    part({ user, network, channel, reason }) {
-      dispatch(addEvent(network, channel, 'part', user, reason));
+    // Ignored joins, parts and quits only update the userlists
+    join({ user, network, channels, ignored }) {
+      if (!ignored) {
+        dispatch(addEvent(network, channels[0], 'join', user));
+      }
     },
 
-    quit({ user, network, reason }) {
-      const channels = findChannels(getState(), network, user);
-      dispatch(broadcastEvent(network, channels, 'quit', user, reason));
+    part({ user, network, channel, reason, ignored }) {
+      if (!ignored) {
+        dispatch(addEvent(network, channel, 'part', user, reason));
+      }
+    },
+
+    quit({ user, network, reason, channels, ignored }) {
+      if (ignored) {
+        return;
+      }
+
+      let userChannels = findChannels(getState(), network, user);
+      if (channels) {
+        userChannels = userChannels.filter(channel =>
+          channels.includes(channel)
+        );
+      }
+      dispatch(broadcastEvent(network, userChannels, 'quit', user, reason));
     },
 
     kick({ network, channel, sender, user, reason }) {
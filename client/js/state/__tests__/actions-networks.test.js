@@ -1,4 +1,4 @@
-import { connect, setNetworkName } from '../networks';
+import { connect, getNetworkID, setNetworkName } from '../networks';
 
 describe('setNetworkName()', () => {
   it('passes valid names to the network', () => {
@@ -18,3 +18,15 @@ describe('setNetworkName()', () => {
     expect(setNetworkName('   ', 'srv').socket).toBeUndefined();
   });
 });
+
+describe('getNetworkID()', () => {
+  it('does not use the host', () => {
+    const id = getNetworkID({});
+    expect(id).toMatch(/^[0-9A-Za-z]{16}$/);
+  });
+
+  it('returns IDs that are not in use', () => {
+    const id = getNetworkID({});
+    expect(getNetworkID({ [id]: {} })).not.toBe(id);
+  });
+});
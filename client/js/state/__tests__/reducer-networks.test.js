@@ -57,6 +57,28 @@ describe('network reducer', () => {
     });
   });
 
+  it('keys the network by its ID on CONNECT', () => {
+    let state = reducer(
+      undefined,
+      connect({ host: '127.0.0.1', nick: 'nick' })
+    );
+    state = reducer(
+      state,
+      connect({ id: '127.0.0.1-2', host: '127.0.0.1', nick: 'bot' })
+    );
+
+    expect(state).toMatchObject({
+      '127.0.0.1': {
+        name: '127.0.0.1',
+        nick: 'nick'
+      },
+      '127.0.0.1-2': {
+        name: '127.0.0.1',
+        nick: 'bot'
+      }
+    });
+  });
+
   it('removes the network on DISCONNECT', () => {
     let state = {
       srv: {},
@@ -18,6 +18,7 @@ export const INPUT_HISTORY_RESET = 'INPUT_HISTORY_RESET';
 export const ADD_FETCHED_MESSAGES = 'ADD_FETCHED_MESSAGES';
 export const ADD_MESSAGE = 'ADD_MESSAGE';
 export const ADD_MESSAGES = 'ADD_MESSAGES';
+export const ADD_LINK_META = 'ADD_LINK_META';
 export const COMMAND = 'COMMAND';
 export const FETCH_MESSAGES = 'FETCH_MESSAGES';
 export const RAW = 'RAW';
@@ -46,6 +47,9 @@ export const SET_KEY = 'SET_KEY';
 export const UPLOAD_CERT = 'UPLOAD_CERT';
 export const SETTINGS_SET = 'SETTINGS_SET';
 
+export const PUSH_SUBSCRIBE = 'PUSH_SUBSCRIBE';
+export const PUSH_UNSUBSCRIBE = 'PUSH_UNSUBSCRIBE';
+
 export const SELECT_TAB = 'SELECT_TAB';
 
 export const HIDE_MENU = 'HIDE_MENU';
@@ -81,6 +85,7 @@ export const socket = createSocketActions([
   'part',
   'kick',
   'pm',
+  'push_subscribe_fail',
   'quit',
   'search',
   'topic',
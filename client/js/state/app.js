@@ -1,4 +1,5 @@
 import createReducer from 'utils/createReducer';
+import { subscribePush, unsubscribePush } from 'utils/push';
 import * as actions from './actions';
 
 export const getApp = state => state.app;
@@ -7,6 +8,8 @@ export const getWrapWidth = state => state.app.wrapWidth;
 export const getCharWidth = state => state.app.charWidth;
 export const getWindowWidth = state => state.app.windowWidth;
 export const getConnectDefaults = state => state.app.connectDefaults;
+export const getConnectPresets = state => state.app.connectPresets;
+export const getVAPIDPublicKey = state => state.app.vapidPublicKey;
 
 const initialState = {
   connected: false,
@@ -23,9 +26,14 @@ const initialState = {
     readonly: false,
     showDetails: false
   },
+  connectPresets: [],
+  networkAllowlist: false,
   hexIP: false,
   newVersionAvailable: false,
-  installable: null
+  installable: null,
+  vapidPublicKey: '',
+  pushEnabled: false,
+  pushError: null
 };
 
 export default createReducer(initialState, {
@@ -41,6 +49,21 @@ export default createReducer(initialState, {
     state.connected = connected;
   },
 
+  [actions.PUSH_SUBSCRIBE](state) {
+    state.pushEnabled = true;
+    state.pushError = null;
+  },
+
+  [actions.PUSH_UNSUBSCRIBE](state) {
+    state.pushEnabled = false;
+    state.pushError = null;
+  },
+
+  [actions.socket.PUSH_SUBSCRIBE_FAIL](state, { message }) {
+    state.pushEnabled = false;
+    state.pushError = message;
+  },
+
   [actions.UPDATE_MESSAGE_HEIGHT](state, action) {
     state.wrapWidth = action.wrapWidth;
     state.charWidth = action.charWidth;
@@ -63,3 +86,44 @@ export function appSet(key, value) {
 export function setCharWidth(width) {
   return appSet('charWidth', width);
 }
+
+export function enablePush() {
+  return async (dispatch, getState) => {
+    try {
+      const permission = await window.Notification.requestPermission();
+      if (permission !== 'granted') {
+        dispatch(appSet('pushError', 'Notifications are blocked'));
+        return;
+      }
+
+      const sub = await subscribePush(getVAPIDPublicKey(getState()));
+      dispatch({
+        type: actions.PUSH_SUBSCRIBE,
+        socket: {
+          type: 'push_subscribe',
+          data: sub
+        }
+      });
+    } catch (e) {
+      dispatch(appSet('pushError', e.message));
+    }
+  };
+}
+
+export function disablePush() {
+  return async dispatch => {
+    try {
+      const endpoint = await unsubscribePush();
+      const action = { type: actions.PUSH_UNSUBSCRIBE };
+      if (endpoint) {
+        action.socket = {
+          type: 'push_unsubscribe',
+          data: { endpoint }
+        };
+      }
+      dispatch(action);
+    } catch (e) {
+      dispatch(appSet('pushError', e.message));
+    }
+  };
+}
@@ -211,7 +211,7 @@ export default createReducer(
 
     [actions.INIT](state, { networks, channels, users }) {
       if (networks) {
-        networks.forEach(({ host }) => init(state, host));
+        networks.forEach(({ host, id = host }) => init(state, id));
       }
 
       if (channels) {
@@ -229,8 +229,8 @@ export default createReducer(
       }
     },
 
-    [actions.CONNECT](state, { host }) {
-      init(state, host);
+    [actions.CONNECT](state, { host, id = host }) {
+      init(state, id);
     },
 
     [actions.DISCONNECT](state, { network }) {
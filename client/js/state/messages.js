@@ -47,8 +47,8 @@ function init(state, network, tab) {
 }
 
 function initNetworks(state, networks = []) {
-  networks.forEach(({ host }) => {
-    state[host] = {};
+  networks.forEach(({ host, id = host }) => {
+    state[id] = {};
   });
 }
 
@@ -399,6 +399,23 @@ export default createReducer(
       }
     },
 
+    [actions.ADD_LINK_META](
+      state,
+      { network, tab, id, meta, wrapWidth, charWidth, windowWidth }
+    ) {
+      const message = state[network]?.[tab]?.find(m => m.id === id);
+      if (message) {
+        message.linkMeta = meta;
+        message.height = messageHeight(
+          message,
+          wrapWidth,
+          charWidth,
+          6 * charWidth,
+          windowWidth
+        );
+      }
+    },
+
     [actions.DISCONNECT](state, { network }) {
       delete state[network];
     },
@@ -568,6 +585,38 @@ export function addMessages(messages, network, to, prepend, next) {
   };
 }
 
+// withLinkMeta adds the link previews the server sent along with messages,
+// linkMeta is keyed by message ID
+export function withLinkMeta(messages, linkMeta) {
+  if (linkMeta) {
+    messages.forEach(message => {
+      if (linkMeta[message.id]) {
+        message.linkMeta = linkMeta[message.id];
+      }
+    });
+  }
+  return messages;
+}
+
+export function addLinkMeta(network, to, id, meta) {
+  const tab = getMessageTab(network, to);
+
+  return (dispatch, getState) => {
+    const { wrapWidth, charWidth, windowWidth } = getApp(getState());
+
+    dispatch({
+      type: actions.ADD_LINK_META,
+      network,
+      tab,
+      id,
+      meta,
+      wrapWidth,
+      charWidth,
+      windowWidth
+    });
+  };
+}
+
 export function addEvent(network, tab, type, ...params) {
   return addMessage(
     {
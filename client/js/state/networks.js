@@ -36,9 +36,9 @@ export const getCurrentNetworkError = createSelector(
 export default createReducer(
   {},
   {
-    [actions.CONNECT](state, { host, nick, name }) {
-      if (!state[host]) {
-        state[host] = {
+    [actions.CONNECT](state, { host, id = host, nick, name }) {
+      if (!state[id]) {
+        state[id] = {
           nick,
           editedNick: null,
           name: name || host,
@@ -79,8 +79,16 @@ export default createReducer(
     [actions.INIT](state, { networks }) {
       if (networks) {
         networks.forEach(
-          ({ host, name = host, nick, connected, error, features = {} }) => {
-            state[host] = {
+          ({
+            host,
+            id = host,
+            name = host,
+            nick,
+            connected,
+            error,
+            features = {}
+          }) => {
+            state[id] = {
               name,
               nick,
               connected,
@@ -113,6 +121,22 @@ export default createReducer(
   }
 );
 
+const idChars =
+  '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+
+// Networks get keyed by a random ID, it says nothing about the host so it
+// stays the same when the network moves to a different one
+export function getNetworkID(networks) {
+  let id;
+  do {
+    id = '';
+    for (let i = 0; i < 16; i++) {
+      id += idChars[Math.floor(Math.random() * idChars.length)];
+    }
+  } while (networks[id]);
+  return id;
+}
+
 export function connect(config) {
   return {
     type: actions.CONNECT,
@@ -15,3 +15,38 @@ registerRoute(
     denylist: [new RegExp('/downloads/')]
   })
 );
+
+self.addEventListener('push', event => {
+  if (!event.data) {
+    return;
+  }
+
+  const { type, id, network, to, from, content } = event.data.json();
+  const title = type === 'pm' ? from : `${from} in ${to}`;
+  // Private chats are opened by the nick of the other user
+  const tab = type === 'pm' ? from : to;
+
+  event.waitUntil(
+    self.registration.showNotification(title, {
+      body: content,
+      tag: id,
+      data: {
+        url: `/${network}/${encodeURIComponent(tab)}`
+      }
+    })
+  );
+});
+
+self.addEventListener('notificationclick', event => {
+  event.notification.close();
+
+  const { url } = event.notification.data;
+  event.waitUntil(
+    self.clients.matchAll({ type: 'window' }).then(windows => {
+      if (windows.length > 0) {
+        return windows[0].focus().then(client => client.navigate(url));
+      }
+      return self.clients.openWindow(url);
+    })
+  );
+});
@@ -1,6 +1,9 @@
 const lineHeight = 24;
 const userListWidth = 200;
 const smallScreen = 600;
+// Link previews have a fixed height so the height of messages with them can
+// still be calculated
+export const linkPreviewHeight = 88;
 
 export function findBreakpoints(blocks) {
   const breakpoints = [];
@@ -35,6 +38,10 @@ export function messageHeight(
   let pad = (6 + (message.from ? message.from.length + 1 : 0)) * charWidth;
   let height = lineHeight + 8;
 
+  if (message.linkMeta) {
+    height += message.linkMeta.length * linkPreviewHeight;
+  }
+
   if (message.channel && windowWidth > smallScreen) {
     wrapWidth -= userListWidth;
   }
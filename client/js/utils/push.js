@@ -0,0 +1,64 @@
+export function pushSupported() {
+  return (
+    'serviceWorker' in navigator &&
+    'PushManager' in window &&
+    'Notification' in window
+  );
+}
+
+function base64ToBytes(base64) {
+  const padding = '='.repeat((4 - (base64.length % 4)) % 4);
+  const raw = window.atob(
+    (base64 + padding).replace(/-/g, '+').replace(/_/g, '/')
+  );
+  return Uint8Array.from(raw, c => c.charCodeAt(0));
+}
+
+function sameKey(a, b) {
+  if (!a || a.byteLength !== b.length) {
+    return false;
+  }
+  const bytes = new Uint8Array(a);
+  return bytes.every((v, i) => v === b[i]);
+}
+
+export async function getPushSubscription() {
+  if (!pushSupported()) {
+    return null;
+  }
+  const registration = await navigator.serviceWorker.ready;
+  return registration.pushManager.getSubscription();
+}
+
+// subscribePush returns the subscription of this browser, subscriptions made
+// with another key get replaced since the server can not push to them
+export async function subscribePush(publicKey) {
+  const key = base64ToBytes(publicKey);
+  const registration = await navigator.serviceWorker.ready;
+
+  let sub = await registration.pushManager.getSubscription();
+  if (sub && !sameKey(sub.options.applicationServerKey, key)) {
+    await sub.unsubscribe();
+    sub = null;
+  }
+
+  if (!sub) {
+    sub = await registration.pushManager.subscribe({
+      userVisibleOnly: true,
+      applicationServerKey: key
+    });
+  }
+
+  return sub.toJSON();
+}
+
+// unsubscribePush returns the endpoint that is no longer subscribed, or null
+// if there was no subscription
+export async function unsubscribePush() {
+  const sub = await getPushSubscription();
+  if (!sub) {
+    return null;
+  }
+  await sub.unsubscribe();
+  return sub.endpoint;
+}
@@ -13,6 +13,8 @@ var clearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all user data",
 	Run: func(cmd *cobra.Command, args []string) {
+		lockDataDir()
+
 		err := os.Remove(storage.Path.Database())
 		if err == nil || os.IsNotExist(err) {
 			log.Println("Database cleared")
@@ -11,8 +11,6 @@ import (
 	"github.com/khlieng/dispatch/config"
 	"github.com/khlieng/dispatch/server"
 	"github.com/khlieng/dispatch/storage"
-	"github.com/khlieng/dispatch/storage/bleve"
-	"github.com/khlieng/dispatch/storage/boltdb"
 	"github.com/khlieng/dispatch/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -60,21 +58,27 @@ var rootCmd = &cobra.Command{
 		}
 		log.Println("Storing data at", storage.Path.DataRoot())
 
-		db, err := boltdb.New(storage.Path.Database())
+		err := storage.LockDataDir()
 		if err != nil {
-			log.Fatal(err)
+			log.Fatal("Could not lock the data directory, dispatch might already be running: ", err)
 		}
-		defer db.Close()
 
-		storage.GetMessageStore = func(user *storage.User) (storage.MessageStore, error) {
-			return boltdb.New(storage.Path.Log(user.Username))
+		cfg, cfgUpdated := config.LoadConfig()
+
+		db, err := openBackend(cfg.Storage.Backend, cfg)
+		if err != nil {
+			log.Fatal(err)
 		}
+		defer db.close()
 
-		storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
-			return bleve.New(storage.Path.Index(user.Username))
+		if cfg.Storage.Backend == backendMemory {
+			log.Println("Keeping all data in memory, it will be gone when dispatch stops")
 		}
 
-		cfg, cfgUpdated := config.LoadConfig()
+		storage.GetMessageStore = db.messageStore
+		storage.GetMessageSearchProvider = db.searchProvider
+		storage.InMemory = cfg.Storage.Backend == backendMemory
+
 		dispatch := server.New(cfg)
 
 		go func() {
@@ -86,6 +90,7 @@ var rootCmd = &cobra.Command{
 
 		dispatch.Store = db
 		dispatch.SessionStore = db
+		dispatch.LinkMetaStore = db
 
 		dispatch.Run()
 	},
@@ -98,6 +103,11 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(clearCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(reindexCmd)
+	rootCmd.AddCommand(rotateKeyCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	rootCmd.PersistentFlags().String("data", storage.DefaultDirectory(), "directory to store data in")
@@ -129,6 +139,8 @@ func init() {
 	viper.SetDefault("proxy.protocol", "socks5")
 	viper.SetDefault("proxy.host", "127.0.0.1")
 	viper.SetDefault("proxy.port", 1080)
+
+	viper.BindEnv("storage.encryption_key", "DISPATCH_ENCRYPTION_KEY")
 }
 
 func initConfig(configPath string, overwrite bool) error {
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/export"
+	"github.com/khlieng/dispatch/storage"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the message logs of a user",
+	Long: `Export the message logs of a user as text, JSON lines or HTML.
+
+The times passed to --from and --to are either dates (YYYY-MM-DD) in the
+local time zone or RFC 3339 timestamps. Dispatch has to be stopped while
+this is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		userID, _ := flags.GetUint64("user")
+		output, _ := flags.GetString("output")
+		from, _ := flags.GetString("from")
+		to, _ := flags.GetString("to")
+
+		opts := export.Options{
+			Location: time.Local,
+		}
+		opts.Format, _ = flags.GetString("format")
+		opts.Network, _ = flags.GetString("network")
+		opts.Channel, _ = flags.GetString("channel")
+
+		var err error
+		opts.Start, err = export.ParseTime(from, false, time.Local)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.End, err = export.ParseTime(to, true, time.Local)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		lockDataDir()
+
+		db, err := openConfiguredBackend()
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer db.close()
+
+		users, err := db.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		user := selectUser(users, userID)
+
+		messageLog, err := db.messageStore(user)
+		if err != nil {
+			log.Fatal("Could not open the message log, make sure dispatch is not running: ", err)
+		}
+		defer messageLog.Close()
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		err = export.Export(w, messageLog.ForEachMessage, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	flags := exportCmd.Flags()
+	flags.Uint64("user", 0, "the user to export, can be left out when there is only one")
+	flags.StringP("format", "f", export.FormatText, "text, json or html")
+	flags.StringP("output", "o", "", "the file to write to, defaults to stdout")
+	flags.String("network", "", "only export this network")
+	flags.String("channel", "", "only export this channel")
+	flags.String("from", "", "only export messages logged from this time")
+	flags.String("to", "", "only export messages logged until this time")
+}
+
+// selectUser returns the user with userID, 0 picks the only user there is
+func selectUser(users []*storage.User, userID uint64) *storage.User {
+	for _, user := range users {
+		if user.ID == userID || (userID == 0 && len(users) == 1) {
+			return user
+		}
+	}
+
+	if userID == 0 {
+		log.Fatal("There is more than one user, pick one with --user")
+	}
+	log.Fatalf("User %d does not exist", userID)
+	return nil
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/importer"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+)
+
+const importBatchSize = 1000
+
+var importCmd = &cobra.Command{
+	Use:   "import [files]",
+	Short: "Import logs from other IRC clients",
+	Long: `Import logs from other IRC clients into the message log of a user.
+
+The supported formats are irssi, weechat, znc and hexchat. The channel and,
+for ZNC, the date are taken from the default log paths of each client when
+they are not passed. Importing the same log again does not duplicate the
+messages. Dispatch has to be stopped while this is running.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		userID, _ := flags.GetUint64("user")
+		date, _ := flags.GetString("date")
+		timezone, _ := flags.GetString("timezone")
+
+		opts := importer.Options{}
+		opts.Format, _ = flags.GetString("format")
+		opts.Network, _ = flags.GetString("network")
+		opts.Channel, _ = flags.GetString("channel")
+
+		if opts.Network == "" {
+			log.Fatal("The network to import into has to be passed with --network")
+		}
+
+		var err error
+		opts.Location, err = time.LoadLocation(timezone)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if date != "" {
+			opts.Date, err = time.ParseInLocation("2006-01-02", date, opts.Location)
+			if err != nil {
+				log.Fatal("Invalid date, use YYYY-MM-DD: ", err)
+			}
+		}
+
+		lockDataDir()
+
+		db, err := openConfiguredBackend()
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer db.close()
+
+		users, err := db.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+		user := selectUser(users, userID)
+
+		networks, err := db.Networks(user)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !hasNetwork(networks, opts.Network) {
+			ids := make([]string, len(networks))
+			for i, network := range networks {
+				ids[i] = network.ID
+			}
+			log.Fatalf("Network %s does not exist, the networks of user %d are: %s",
+				opts.Network, user.ID, strings.Join(ids, ", "))
+		}
+
+		messageLog, err := db.messageStore(user)
+		if err != nil {
+			log.Fatal("Could not open the message log, make sure dispatch is not running: ", err)
+		}
+		defer messageLog.Close()
+
+		index, err := bleve.NewWithOptions(storage.Path.Index(user.Username), db.indexOptions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer index.Close()
+
+		for _, path := range args {
+			fileOpts := opts
+			channel, date := importer.PathInfo(opts.Format, path, opts.Location)
+			if fileOpts.Channel == "" {
+				fileOpts.Channel = channel
+			}
+			if !date.IsZero() {
+				fileOpts.Date = date
+			}
+
+			count, err := importLog(messageLog, index, path, fileOpts)
+			if err != nil {
+				log.Printf("Importing %s failed: %v", path, err)
+				continue
+			}
+
+			fmt.Printf("%s: imported %d messages into %s on %s\n", path, count, fileOpts.Channel, fileOpts.Network)
+		}
+	},
+}
+
+func init() {
+	flags := importCmd.Flags()
+	flags.Uint64("user", 0, "the user to import into, can be left out when there is only one")
+	flags.StringP("format", "f", "", "the format of the logs: "+strings.Join(importer.Formats, ", "))
+	flags.String("network", "", "the ID of the network the logs are from")
+	flags.String("channel", "", "the channel the logs are from, defaults to the one in the log paths")
+	flags.String("date", "", "the day the logs start on (YYYY-MM-DD) when they do not include it")
+	flags.String("timezone", "Local", "the time zone of the timestamps in the logs")
+}
+
+func hasNetwork(networks []*storage.Network, id string) bool {
+	for _, network := range networks {
+		if network.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func importLog(messageLog storage.MessageStore, index *bleve.Bleve, path string, opts importer.Options) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	count := 0
+	batch := make([]*storage.Message, 0, importBatchSize)
+
+	flush := func() error {
+		err := messageLog.LogMessages(batch)
+		if err != nil {
+			return err
+		}
+
+		err = index.IndexBatch(batch)
+		if err != nil {
+			return err
+		}
+
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err = importer.Import(f, opts, func(message *storage.Message) error {
+		batch = append(batch, message)
+		if len(batch) == importBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+
+	return count, err
+}
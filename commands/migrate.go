@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+)
+
+const migrateBatchSize = 1000
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move all data to another storage backend",
+	Long: `Move all users, sessions and messages to another storage backend.
+
+The backends are bolt and sqlite. The data in the old backend is left as it
+is, set the backend in the config to the new one when this is done. Dispatch
+has to be stopped while this is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		if from == to {
+			log.Fatal("The backends have to be different")
+		}
+		if from == backendMemory || to == backendMemory {
+			log.Fatal("The memory backend does not store anything")
+		}
+
+		lockDataDir()
+
+		cfg := config.Load()
+
+		src, err := openBackend(from, cfg)
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer src.close()
+
+		dst, err := openBackend(to, cfg)
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer dst.close()
+
+		existing, err := dst.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(existing) > 0 {
+			log.Fatalf("The %s backend already has users, migrating into it would mix them up", to)
+		}
+
+		users, err := src.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, user := range users {
+			err = migrateUser(src, dst, user)
+			if err != nil {
+				log.Fatalf("Migrating user %d failed: %v", user.ID, err)
+			}
+		}
+
+		sessions, err := src.Sessions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, session := range sessions {
+			err = dst.SaveSession(session)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		fmt.Printf("Migrated %d users and %d sessions, set backend = \"%s\" in the [storage] section of the config to use them\n",
+			len(users), len(sessions), to)
+	},
+}
+
+func init() {
+	migrateCmd.Flags().String("from", backendBolt, "the backend to move the data from")
+	migrateCmd.Flags().String("to", backendSQLite, "the backend to move the data to")
+}
+
+func migrateUser(src, dst *backend, user *storage.User) error {
+	err := dst.SaveUser(user)
+	if err != nil {
+		return err
+	}
+
+	networks, err := src.Networks(user)
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		err = dst.SaveNetwork(user, network)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Networks that have not been moved to their new ID yet get moved
+	// when dispatch starts with the new backend
+	renames, err := src.NetworkRenames(user)
+	if err != nil {
+		return err
+	}
+	for from, to := range renames {
+		err = dst.SaveNetworkRename(user, from, to)
+		if err != nil {
+			return err
+		}
+	}
+
+	channels, err := src.Channels(user)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		err = dst.SaveChannel(user, channel)
+		if err != nil {
+			return err
+		}
+	}
+
+	openDMs, err := src.OpenDMs(user)
+	if err != nil {
+		return err
+	}
+	for _, tab := range openDMs {
+		err = dst.AddOpenDM(user, tab.Network, tab.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	rules, err := src.HighlightRules(user)
+	if err == nil {
+		err = dst.SaveHighlightRules(user, rules)
+	}
+	if err != nil && err != storage.ErrNotFound {
+		return err
+	}
+
+	mentions, err := src.Mentions(user)
+	if err != nil {
+		return err
+	}
+	for _, mention := range mentions {
+		err = dst.SaveMention(user, mention)
+		if err != nil {
+			return err
+		}
+	}
+
+	subs, err := src.PushSubscriptions(user)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		err = dst.SavePushSubscription(user, sub)
+		if err != nil {
+			return err
+		}
+	}
+
+	ignores, err := src.IgnoreRules(user)
+	if err != nil {
+		return err
+	}
+	for _, rule := range ignores {
+		err = dst.SaveIgnoreRule(user, rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	markers, err := src.ReadMarkers(user)
+	if err != nil {
+		return err
+	}
+	for _, marker := range markers {
+		err = dst.SaveReadMarker(user, marker)
+		if err != nil {
+			return err
+		}
+	}
+
+	tokens, err := src.APITokens(user)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		err = dst.SaveAPIToken(user, token)
+		if err != nil {
+			return err
+		}
+	}
+
+	srcLog, err := src.messageStore(user)
+	if err != nil {
+		return err
+	}
+	defer srcLog.Close()
+
+	dstLog, err := dst.messageStore(user)
+	if err != nil {
+		return err
+	}
+	defer dstLog.Close()
+
+	count := 0
+	batch := make([]*storage.Message, 0, migrateBatchSize)
+
+	flush := func() error {
+		err := dstLog.LogMessages(batch)
+		if err != nil {
+			return err
+		}
+
+		count += len(batch)
+		batch = batch[:0]
+		fmt.Printf("\rUser %d: %d messages migrated", user.ID, count)
+		return nil
+	}
+
+	err = srcLog.ForEachMessage(func(message *storage.Message) error {
+		batch = append(batch, message)
+		if len(batch) == migrateBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	fmt.Println()
+
+	return err
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+)
+
+const reindexBatchSize = 1000
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the message search index from the message logs",
+	Long: `Rebuild the message search index from the message logs.
+
+Dispatch has to be stopped while this is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		userID, _ := cmd.Flags().GetUint64("user")
+
+		lockDataDir()
+
+		db, err := openConfiguredBackend()
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer db.close()
+
+		users, err := db.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		found := false
+		for _, user := range users {
+			if userID != 0 && user.ID != userID {
+				continue
+			}
+			found = true
+
+			err = reindex(db, user)
+			if err != nil {
+				log.Fatalf("Reindexing user %d failed: %v", user.ID, err)
+			}
+		}
+
+		if userID != 0 && !found {
+			log.Fatalf("User %d does not exist", userID)
+		}
+	},
+}
+
+func init() {
+	reindexCmd.Flags().Uint64("user", 0, "only reindex the messages of this user")
+}
+
+// messageCounter is implemented by the message stores that can count
+// the messages they hold
+type messageCounter interface {
+	MessageCount() (int, error)
+}
+
+func reindex(db *backend, user *storage.User) error {
+	messageLog, err := db.messageStore(user)
+	if err != nil {
+		return fmt.Errorf("could not open the message log, make sure dispatch is not running: %v", err)
+	}
+	defer messageLog.Close()
+
+	total := 0
+	if counter, ok := messageLog.(messageCounter); ok {
+		total, err = counter.MessageCount()
+		if err != nil {
+			return err
+		}
+	}
+
+	// The new index gets built next to the current one and swapped in
+	// when it is done, a failed rebuild leaves the current one as it was
+	indexPath := storage.Path.Index(user.Username)
+	newPath := indexPath + "." + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	index, err := bleve.NewWithOptions(newPath, db.indexOptions)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	batch := make([]*storage.Message, 0, reindexBatchSize)
+
+	flush := func() error {
+		err := index.IndexBatch(batch)
+		if err != nil {
+			return err
+		}
+
+		count += len(batch)
+		batch = batch[:0]
+		fmt.Printf("\rUser %d: %d/%d messages indexed", user.ID, count, total)
+		return nil
+	}
+
+	err = messageLog.ForEachMessage(func(message *storage.Message) error {
+		batch = append(batch, message)
+		if len(batch) == reindexBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	fmt.Println()
+
+	index.Close()
+	if err != nil {
+		os.RemoveAll(newPath)
+		return err
+	}
+
+	return swapIndex(indexPath, newPath)
+}
+
+// swapIndex points path at the index in newPath, path is a symlink to the
+// current index so swapping them is a single rename of a new symlink over
+// it. Indexes from before this are a directory at path that has to be moved
+// out of the way first.
+func swapIndex(path, newPath string) error {
+	oldTarget, _ := os.Readlink(path)
+
+	link := path + ".link"
+	err := os.Remove(link)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = os.Symlink(filepath.Base(newPath), link)
+	if err != nil {
+		return err
+	}
+
+	oldPath := ""
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		oldPath = path + ".old"
+
+		err = os.RemoveAll(oldPath)
+		if err == nil {
+			err = os.Rename(path, oldPath)
+		}
+		if err != nil {
+			os.Remove(link)
+			return err
+		}
+	} else if oldTarget != "" {
+		oldPath = filepath.Join(filepath.Dir(path), oldTarget)
+	}
+
+	err = os.Rename(link, path)
+	if err != nil {
+		os.Remove(link)
+		if oldTarget == "" && oldPath != "" {
+			if rerr := os.Rename(oldPath, path); rerr != nil {
+				return fmt.Errorf("%v, the old index could not be moved back from %s: %v", err, oldPath, rerr)
+			}
+		}
+		return err
+	}
+
+	if oldPath != "" {
+		return os.RemoveAll(oldPath)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/encrypt"
+)
+
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Encrypt the stored network passwords and message logs with the current encryption key",
+	Long: `Encrypt the stored network passwords and message logs with the current
+encryption key.
+
+To change the key, move the current one to old_encryption_keys in the
+[storage] section of the config, set encryption_key to the new one and run
+this command. The old key can be removed from the config when it is done.
+Without an encryption key the passwords and messages get decrypted and
+stored as plaintext.
+
+Dispatch has to be stopped while this is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if generate, _ := cmd.Flags().GetBool("generate"); generate {
+			key, err := encrypt.GenerateKey()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(key)
+			return
+		}
+
+		lockDataDir()
+
+		db, err := openConfiguredBackend()
+		if err != nil {
+			log.Fatal("Could not open the database, make sure dispatch is not running: ", err)
+		}
+		defer db.close()
+
+		store := db.Store.(*encrypt.Store)
+
+		users, err := db.Users()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		networks, messages := 0, 0
+		for _, user := range users {
+			n, err := store.Rotate(user)
+			if err != nil {
+				log.Fatalf("Rotating the networks of user %d failed: %v", user.ID, err)
+			}
+			networks += n
+
+			n, err = rotateMessages(db, user)
+			if err != nil {
+				log.Fatalf("Rotating the messages of user %d failed: %v", user.ID, err)
+			}
+			messages += n
+		}
+
+		fmt.Printf("Rotated the passwords of %d networks and %d messages\n", networks, messages)
+	},
+}
+
+func init() {
+	rotateKeyCmd.Flags().Bool("generate", false, "print a new encryption key and exit")
+}
+
+func rotateMessages(db *backend, user *storage.User) (int, error) {
+	messageLog, err := db.messageStore(user)
+	if err != nil {
+		return 0, err
+	}
+	defer messageLog.Close()
+
+	return messageLog.(*encrypt.MessageStore).Rotate()
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/encrypt"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/khlieng/dispatch/storage/sqlite"
+)
+
+const (
+	backendBolt   = "bolt"
+	backendSQLite = "sqlite"
+	backendMemory = "memory"
+)
+
+// backend holds the stores of a storage backend
+type backend struct {
+	storage.Store
+	storage.SessionStore
+	storage.LinkMetaStore
+	messageStore   storage.MessageStoreCreator
+	searchProvider storage.MessageSearchProviderCreator
+	// indexOptions are what the search indexes get created with
+	indexOptions bleve.Options
+	close        func()
+}
+
+// lockDataDir exits if dispatch or one of the commands that work on the
+// stored data is already using the data directory
+func lockDataDir() {
+	err := storage.LockDataDir()
+	if err != nil {
+		log.Fatal("Could not lock the data directory, make sure dispatch is not running: ", err)
+	}
+}
+
+// openConfiguredBackend opens the backend picked in the config for the
+// commands that work on stored data
+func openConfiguredBackend() (*backend, error) {
+	cfg := config.Load()
+	if cfg.Storage.Backend == backendMemory {
+		return nil, fmt.Errorf("the memory backend does not store anything")
+	}
+	return openBackend(cfg.Storage.Backend, cfg)
+}
+
+func openIndex(opts bleve.Options) storage.MessageSearchProviderCreator {
+	return func(user *storage.User) (storage.MessageSearchProvider, error) {
+		index, err := bleve.NewWithOptions(storage.Path.Index(user.Username), opts)
+		if err != nil {
+			return nil, err
+		}
+		if index.Outdated() {
+			log.Printf("[Search] The index of %s is from an older version, run dispatch reindex to be able to search it", user.Username)
+		}
+		if opts.NoContent && index.StoresContent() {
+			log.Printf("[Search] The index of %s has the content of messages unencrypted, run dispatch reindex to remove it", user.Username)
+		}
+		return index, nil
+	}
+}
+
+// openKeyring returns the keys from the [storage] section of the config,
+// the DISPATCH_ENCRYPTION_KEY environment variable overrides the current key
+func openKeyring(cfg *config.Config) (*encrypt.Keyring, error) {
+	var current *encrypt.Key
+	if cfg.Storage.EncryptionKey != "" {
+		key, err := encrypt.ParseKey(cfg.Storage.EncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("encryption_key: %v", err)
+		}
+		current = key
+	}
+
+	var old []*encrypt.Key
+	for _, s := range cfg.Storage.OldEncryptionKeys {
+		key, err := encrypt.ParseKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("old_encryption_keys: %v", err)
+		}
+		old = append(old, key)
+	}
+
+	return encrypt.NewKeyring(current, old...), nil
+}
+
+// openBackend opens the storage backend with the given name, it defaults
+// to bolt. The passwords of networks and the message logs get encrypted
+// with the keys in cfg, and the search indexes leave out the content of
+// messages when there is a key.
+func openBackend(name string, cfg *config.Config) (*backend, error) {
+	keys, err := openKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openStores(name, bleve.Options{NoContent: keys.Enabled()})
+	if err != nil {
+		return nil, err
+	}
+	db.Store = encrypt.NewStore(db.Store, keys)
+
+	messageStore := db.messageStore
+	db.messageStore = func(user *storage.User) (storage.MessageStore, error) {
+		store, err := messageStore(user)
+		if err != nil {
+			return nil, err
+		}
+		return encrypt.NewMessageStore(store, keys), nil
+	}
+
+	return db, nil
+}
+
+func openStores(name string, indexOptions bleve.Options) (*backend, error) {
+	switch name {
+	case backendBolt, "":
+		db, err := boltdb.New(storage.Path.Database())
+		if err != nil {
+			return nil, err
+		}
+
+		return &backend{
+			Store:         db,
+			SessionStore:  db,
+			LinkMetaStore: db,
+			messageStore: func(user *storage.User) (storage.MessageStore, error) {
+				return boltdb.New(storage.Path.Log(user.Username))
+			},
+			searchProvider: openIndex(indexOptions),
+			indexOptions:   indexOptions,
+			close:          db.Close,
+		}, nil
+
+	case backendSQLite:
+		db, err := sqlite.New(storage.Path.SQLite())
+		if err != nil {
+			return nil, err
+		}
+
+		return &backend{
+			Store:         db,
+			SessionStore:  db,
+			LinkMetaStore: db,
+			messageStore: func(user *storage.User) (storage.MessageStore, error) {
+				return db.MessageStore(user), nil
+			},
+			searchProvider: openIndex(indexOptions),
+			indexOptions:   indexOptions,
+			close:          db.Close,
+		}, nil
+
+	case backendMemory:
+		db := memory.New()
+
+		return &backend{
+			Store:         db,
+			SessionStore:  db,
+			LinkMetaStore: db,
+			messageStore: func(user *storage.User) (storage.MessageStore, error) {
+				return db.MessageStore(user), nil
+			},
+			searchProvider: func(user *storage.User) (storage.MessageSearchProvider, error) {
+				return db.MessageSearchProvider(user), nil
+			},
+			close: db.Close,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown storage backend: %s", name)
+}
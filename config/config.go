@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -18,11 +19,18 @@ type Config struct {
 	VerifyCertificates bool `mapstructure:"verify_certificates"`
 	Headers            map[string]string
 	Defaults           Defaults
+	Networks           []Preset
+	NetworkAllowlist   bool `mapstructure:"network_allowlist"`
+	WebIRC             []WebIRC
 	HTTPS              HTTPS
 	LetsEncrypt        LetsEncrypt
 	Auth               Auth
 	DCC                DCC
 	Proxy              Proxy
+	LinkPreviews       LinkPreviews `mapstructure:"link_previews"`
+	Push               Push
+	Retention          Retention
+	Storage            Storage
 }
 
 type Defaults struct {
@@ -36,6 +44,52 @@ type Defaults struct {
 	ShowDetails    bool `mapstructure:"show_details"`
 }
 
+type Preset struct {
+	Name           string
+	Host           string
+	Port           string
+	TLS            bool
+	ServerPassword string `mapstructure:"server_password"`
+	Channels       []string
+	RequireSASL    bool `mapstructure:"require_sasl"`
+	ReadOnly       bool
+}
+
+// Presets returns the networks users can choose from in the connect form,
+// the network in Defaults gets used if no networks are configured
+func (c *Config) Presets() []Preset {
+	if len(c.Networks) > 0 || c.Defaults.Host == "" {
+		return c.Networks
+	}
+
+	return []Preset{{
+		Name:           c.Defaults.Name,
+		Host:           c.Defaults.Host,
+		Port:           c.Defaults.Port,
+		TLS:            c.Defaults.SSL,
+		ServerPassword: c.Defaults.ServerPassword,
+		Channels:       c.Defaults.Channels,
+		ReadOnly:       c.Defaults.ReadOnly,
+	}}
+}
+
+// Preset returns the preset for host, or nil if there is none
+func (c *Config) Preset(host string) *Preset {
+	presets := c.Presets()
+	for i := range presets {
+		if strings.EqualFold(presets[i].Host, host) {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+type WebIRC struct {
+	Host     string
+	Gateway  string
+	Password string
+}
+
 type HTTPS struct {
 	Enabled bool
 	Port    string
@@ -88,13 +142,104 @@ type Proxy struct {
 	Password string
 }
 
-func LoadConfig() (*Config, chan *Config) {
+type LinkPreviews struct {
+	// Thumbnails makes the server fetch and scale down images in link
+	// previews so that browsers only ever load them from dispatch
+	Thumbnails    bool
+	ThumbnailSize int `mapstructure:"thumbnail_size"`
+	// OEmbed providers get asked before the built in ones
+	OEmbed []OEmbedProvider
+}
+
+type OEmbedProvider struct {
+	Name     string
+	Endpoint string
+	Schemes  []string
+}
+
+type Push struct {
+	Enabled bool
+	// Subject is a mailto: or https: URL push services can contact the
+	// operator at
+	Subject string
+	// VAPIDKey signs the pushes, one gets generated and kept in the data
+	// directory when it is empty
+	VAPIDKey string `mapstructure:"vapid_key"`
+}
+
+type Retention struct {
+	// Days and Messages limit how long messages get kept and how many get
+	// kept per channel, 0 disables a limit
+	Days      int
+	Messages  int
+	Overrides []RetentionOverride
+}
+
+// RetentionOverride replaces both limits for a network, or a channel on it
+type RetentionOverride struct {
+	Network  string
+	Channel  string
+	Days     int
+	Messages int
+}
+
+// Enabled reports whether any messages can get pruned
+func (r *Retention) Enabled() bool {
+	if r.Days > 0 || r.Messages > 0 {
+		return true
+	}
+	for _, o := range r.Overrides {
+		if o.Days > 0 || o.Messages > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits returns the limits for a channel on the network with the given host,
+// channel overrides take precedence over network overrides
+func (r *Retention) Limits(host, channel string) (days, messages int) {
+	days, messages = r.Days, r.Messages
+
+	for _, o := range r.Overrides {
+		if !strings.EqualFold(o.Network, host) {
+			continue
+		}
+
+		if o.Channel == "" {
+			days, messages = o.Days, o.Messages
+		} else if strings.EqualFold(o.Channel, channel) {
+			return o.Days, o.Messages
+		}
+	}
+
+	return
+}
+
+type Storage struct {
+	// Backend is where users, sessions and messages get stored, bolt, sqlite
+	// or memory
+	Backend string
+	// EncryptionKey encrypts the passwords of networks, a base64 encoded
+	// 32 byte key. OldEncryptionKeys are only used to decrypt, they are
+	// needed until the passwords have been rotated to a new key.
+	EncryptionKey     string   `mapstructure:"encryption_key"`
+	OldEncryptionKeys []string `mapstructure:"old_encryption_keys"`
+}
+
+// Load reads the config once, LoadConfig also watches it for changes
+func Load() *Config {
 	viper.SetConfigName("config")
 	viper.AddConfigPath(storage.Path.ConfigRoot())
 	viper.ReadInConfig()
 
 	config := &Config{}
 	viper.Unmarshal(config)
+	return config
+}
+
+func LoadConfig() (*Config, chan *Config) {
+	config := Load()
 
 	viper.WatchConfig()
 
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+type Options struct {
+	Format string
+	// Network and Channel limit the export to a single network or channel
+	Network string
+	Channel string
+	// Start and End limit the export to messages logged between them,
+	// they are unix timestamps and 0 leaves them open
+	Start int64
+	End   int64
+	// Location is the time zone timestamps are written in, it defaults to UTC
+	Location *time.Location
+}
+
+func (o *Options) match(message *storage.Message) bool {
+	if o.Network != "" && message.Network != o.Network {
+		return false
+	}
+	if o.Channel != "" && message.To != o.Channel {
+		return false
+	}
+	if o.Start != 0 && message.Time < o.Start {
+		return false
+	}
+	if o.End != 0 && message.Time > o.End {
+		return false
+	}
+	return true
+}
+
+// Writer writes messages in one of the export formats, the messages of a
+// channel have to be written in a row
+type Writer interface {
+	WriteMessage(message *storage.Message) error
+	// Close finishes the export, it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter returns a Writer for the format in opts
+func NewWriter(w io.Writer, opts Options) (Writer, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch opts.Format {
+	case FormatText, "":
+		return newTextWriter(w, loc), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatHTML:
+		return newHTMLWriter(w, loc), nil
+	}
+
+	return nil, fmt.Errorf("unknown export format: %s", opts.Format)
+}
+
+// Export writes the messages that forEach passes to its callback and
+// that match opts to w
+func Export(w io.Writer, forEach func(func(*storage.Message) error) error, opts Options) error {
+	writer, err := NewWriter(w, opts)
+	if err != nil {
+		return err
+	}
+
+	err = forEach(func(message *storage.Message) error {
+		if !opts.match(message) {
+			return nil
+		}
+		return writer.WriteMessage(message)
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func ValidFormat(format string) bool {
+	return format == FormatText || format == FormatJSON || format == FormatHTML
+}
+
+// Extension returns the file extension used for format
+func Extension(format string) string {
+	switch format {
+	case FormatJSON:
+		return "jsonl"
+	case FormatHTML:
+		return "html"
+	}
+	return "log"
+}
+
+// ContentType returns the MIME type of format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/x-ndjson; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// ParseTime parses a date or a RFC 3339 timestamp into a unix timestamp,
+// a date that ends a range includes the whole day
+func ParseTime(s string, end bool, loc *time.Location) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		return t.Unix(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use YYYY-MM-DD or RFC 3339", s)
+	}
+	return t.Unix(), nil
+}
+
+// describeEvent returns the nick an event is about and a description of it
+// in the style of irssi
+func describeEvent(event storage.Event, channel string) (string, string) {
+	param := func(i int) string {
+		if i < len(event.Params) {
+			return event.Params[i]
+		}
+		return ""
+	}
+	reason := func(i int) string {
+		if r := param(i); r != "" {
+			return " [" + r + "]"
+		}
+		return ""
+	}
+	nick := param(0)
+
+	switch event.Type {
+	case "join":
+		return nick, nick + " has joined " + channel
+	case "part":
+		return nick, nick + " has left " + channel + reason(1)
+	case "quit":
+		return nick, nick + " has quit" + reason(1)
+	case "nick":
+		return nick, nick + " is now known as " + param(1)
+	case "kick":
+		return nick, nick + " was kicked from " + channel + " by " + param(1) + reason(2)
+	case "topic":
+		return nick, nick + " changed the topic of " + channel + " to: " + param(1)
+	}
+
+	return nick, fmt.Sprintf("%s %s", event.Type, event.Params)
+}
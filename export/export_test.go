@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+var testMessages = []*storage.Message{
+	{ID: "1", Network: "freenode", To: "#go", From: "alice", Content: "hello <b>", Time: 3600},
+	{ID: "2", Network: "freenode", To: "#go", Time: 3700, Events: []storage.Event{
+		{Type: "join", Params: []string{"bob"}, Time: 3700},
+		{Type: "part", Params: []string{"carol", "bye"}, Time: 3710},
+		{Type: "kick", Params: []string{"dave", "alice", "spam"}, Time: 3720},
+	}},
+	{ID: "3", Network: "freenode", To: "#go", From: "bob", Content: "tomorrow", Time: 90000},
+	{ID: "4", Network: "oftc", To: "#rust", From: "eve", Content: "hi", Time: 4000},
+}
+
+func forEach(fn func(*storage.Message) error) error {
+	for _, msg := range testMessages {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := Export(buf, forEach, Options{Format: FormatText})
+	require.Nil(t, err)
+
+	assert.Equal(t, `--- Log for #go on freenode
+--- Day changed Thu Jan 01 1970
+01:00:00 <alice> hello <b>
+01:01:40 -!- bob has joined #go
+01:01:50 -!- carol has left #go [bye]
+01:02:00 -!- dave was kicked from #go by alice [spam]
+--- Day changed Fri Jan 02 1970
+01:00:00 <bob> tomorrow
+
+--- Log for #rust on oftc
+--- Day changed Thu Jan 01 1970
+01:06:40 <eve> hi
+`, buf.String())
+}
+
+func TestExportJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := Export(buf, forEach, Options{Format: FormatJSON, Network: "freenode", End: 3700})
+	require.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var msg jsonMessage
+	require.Nil(t, json.Unmarshal([]byte(lines[1]), &msg))
+	assert.Equal(t, "2", msg.ID)
+	assert.Equal(t, "#go", msg.Channel)
+	assert.Len(t, msg.Events, 3)
+	assert.Equal(t, []string{"carol", "bye"}, msg.Events[1].Params)
+}
+
+func TestExportHTML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := Export(buf, forEach, Options{Format: FormatHTML, Start: 4000})
+	require.Nil(t, err)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.True(t, strings.HasSuffix(out, "</html>\n"))
+	assert.NotContains(t, out, "alice")
+	assert.Contains(t, out, "<h2>#rust on oftc</h2>")
+	assert.Equal(t, 2, strings.Count(out, "<section>"))
+	assert.Equal(t, 2, strings.Count(out, "</section>"))
+
+	buf.Reset()
+	err = Export(buf, forEach, Options{Format: FormatHTML, Channel: "#go", End: 3600})
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), "hello &lt;b&gt;")
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	assert.NotNil(t, Export(&bytes.Buffer{}, forEach, Options{Format: "pdf"}))
+}
+
+func TestParseTime(t *testing.T) {
+	start, err := ParseTime("2020-01-02", false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1577923200), start)
+
+	end, err := ParseTime("2020-01-02", true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1577923200+86399), end)
+
+	ts, err := ParseTime("2020-01-02T10:00:00+02:00", false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1577923200+8*3600), ts)
+
+	ts, err = ParseTime("", true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), ts)
+
+	_, err = ParseTime("yesterday", false, nil)
+	assert.NotNil(t, err)
+}
+
+func TestExportLocation(t *testing.T) {
+	loc := time.FixedZone("test", 2*3600)
+	buf := &bytes.Buffer{}
+	err := Export(buf, forEach, Options{Network: "oftc", Location: loc})
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), "03:06:40 <eve> hi")
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Dispatch log export</title>
+<style>
+body { font-family: monospace; background: #fff; color: #222; margin: 20px; }
+h2 { font-size: 1.1em; margin: 30px 0 10px; border-bottom: 1px solid #ddd; }
+.day { color: #999; margin: 10px 0 5px; }
+.line { white-space: pre-wrap; word-wrap: break-word; }
+.time { color: #999; }
+.from { color: #6bb758; font-weight: bold; }
+.event { color: #999; font-style: italic; }
+</style>
+</head>
+<body>
+`
+
+const htmlFooter = `</section>
+</body>
+</html>
+`
+
+// htmlWriter writes a single HTML file with no external resources
+type htmlWriter struct {
+	w       *bufio.Writer
+	loc     *time.Location
+	started bool
+	network string
+	channel string
+	day     time.Time
+}
+
+func newHTMLWriter(w io.Writer, loc *time.Location) *htmlWriter {
+	return &htmlWriter{
+		w:   bufio.NewWriter(w),
+		loc: loc,
+	}
+}
+
+func (h *htmlWriter) WriteMessage(message *storage.Message) error {
+	if !h.started {
+		h.w.WriteString(htmlHeader)
+		h.started = true
+	} else if message.Network != h.network || message.To != h.channel {
+		h.w.WriteString("</section>\n")
+	}
+
+	if message.Network != h.network || message.To != h.channel {
+		fmt.Fprintf(h.w, "<section>\n<h2>%s on %s</h2>\n",
+			html.EscapeString(message.To), html.EscapeString(message.Network))
+
+		h.network = message.Network
+		h.channel = message.To
+		h.day = time.Time{}
+	}
+
+	if len(message.Events) > 0 {
+		for _, event := range message.Events {
+			_, text := describeEvent(event, message.To)
+			h.writeLine(eventTime(event, message),
+				`<span class="event">`+html.EscapeString(text)+`</span>`)
+		}
+	} else {
+		h.writeLine(message.Time, fmt.Sprintf(`<span class="from">%s</span> %s`,
+			html.EscapeString(message.From), html.EscapeString(message.Content)))
+	}
+
+	return nil
+}
+
+func (h *htmlWriter) writeLine(unix int64, line string) {
+	ts := time.Unix(unix, 0).In(h.loc)
+
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, h.loc)
+	if !day.Equal(h.day) {
+		fmt.Fprintf(h.w, "<div class=\"day\">%s</div>\n", day.Format("Monday, January 2, 2006"))
+		h.day = day
+	}
+
+	fmt.Fprintf(h.w, "<div class=\"line\"><span class=\"time\">%s</span> %s</div>\n",
+		ts.Format("15:04:05"), line)
+}
+
+func (h *htmlWriter) Close() error {
+	if !h.started {
+		h.w.WriteString(htmlHeader)
+		h.w.WriteString("<section>\n")
+	}
+	h.w.WriteString(htmlFooter)
+
+	return h.w.Flush()
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+type jsonMessage struct {
+	ID      string      `json:"id"`
+	Network string      `json:"network"`
+	Channel string      `json:"channel"`
+	From    string      `json:"from,omitempty"`
+	Content string      `json:"content,omitempty"`
+	Time    int64       `json:"time"`
+	Events  []jsonEvent `json:"events,omitempty"`
+}
+
+type jsonEvent struct {
+	Type   string   `json:"type"`
+	Params []string `json:"params"`
+	Time   int64    `json:"time"`
+}
+
+// jsonWriter writes a JSON object per line for each message
+type jsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	return &jsonWriter{
+		w:   buf,
+		enc: enc,
+	}
+}
+
+func (j *jsonWriter) WriteMessage(message *storage.Message) error {
+	msg := jsonMessage{
+		ID:      message.ID,
+		Network: message.Network,
+		Channel: message.To,
+		From:    message.From,
+		Content: message.Content,
+		Time:    message.Time,
+	}
+
+	for _, event := range message.Events {
+		msg.Events = append(msg.Events, jsonEvent{
+			Type:   event.Type,
+			Params: event.Params,
+			Time:   eventTime(event, message),
+		})
+	}
+
+	return j.enc.Encode(msg)
+}
+
+func (j *jsonWriter) Close() error {
+	return j.w.Flush()
+}
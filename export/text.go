@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+type textWriter struct {
+	w       *bufio.Writer
+	loc     *time.Location
+	network string
+	channel string
+	day     time.Time
+}
+
+func newTextWriter(w io.Writer, loc *time.Location) *textWriter {
+	return &textWriter{
+		w:   bufio.NewWriter(w),
+		loc: loc,
+	}
+}
+
+func (t *textWriter) WriteMessage(message *storage.Message) error {
+	if message.Network != t.network || message.To != t.channel {
+		if t.network != "" {
+			t.w.WriteString("\n")
+		}
+		fmt.Fprintf(t.w, "--- Log for %s on %s\n", message.To, message.Network)
+
+		t.network = message.Network
+		t.channel = message.To
+		t.day = time.Time{}
+	}
+
+	if len(message.Events) > 0 {
+		for _, event := range message.Events {
+			_, text := describeEvent(event, message.To)
+			t.writeLine(eventTime(event, message), "-!- "+text)
+		}
+	} else {
+		t.writeLine(message.Time, fmt.Sprintf("<%s> %s", message.From, message.Content))
+	}
+
+	return nil
+}
+
+func (t *textWriter) writeLine(unix int64, line string) {
+	ts := time.Unix(unix, 0).In(t.loc)
+
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, t.loc)
+	if !day.Equal(t.day) {
+		fmt.Fprintf(t.w, "--- Day changed %s\n", day.Format("Mon Jan 02 2006"))
+		t.day = day
+	}
+
+	fmt.Fprintf(t.w, "%s %s\n", ts.Format("15:04:05"), line)
+}
+
+func (t *textWriter) Close() error {
+	return t.w.Flush()
+}
+
+func eventTime(event storage.Event, message *storage.Message) int64 {
+	if event.Time != 0 {
+		return event.Time
+	}
+	return message.Time
+}
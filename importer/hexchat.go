@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	hexchatBegin = regexp.MustCompile(`^\*\*\*\* BEGIN LOGGING AT (.+)$`)
+	hexchatLine  = regexp.MustCompile(`^(\w{3} [ \d]\d \d\d:\d\d:\d\d) ([^\t]*)\t(.*)$`)
+)
+
+// hexchatParser parses the default HexChat log format, the timestamps
+// leave out the year so it comes from the line that begins the log
+type hexchatParser struct {
+	year  int
+	month time.Month
+	loc   *time.Location
+}
+
+func newHexChatParser(opts Options) *hexchatParser {
+	return &hexchatParser{
+		year:  opts.Date.Year(),
+		month: opts.Date.Month(),
+		loc:   opts.Location,
+	}
+}
+
+func (p *hexchatParser) parseLine(line string) (*entry, error) {
+	if m := hexchatBegin.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("Mon Jan _2 15:04:05 2006", m[1], p.loc)
+		if err != nil {
+			return nil, err
+		}
+		p.year = t.Year()
+		p.month = t.Month()
+		return nil, nil
+	}
+
+	m := hexchatLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, nil
+	}
+	if p.year <= 1 {
+		return nil, fmt.Errorf("the log does not say what year it starts in, pass a date")
+	}
+
+	t, err := time.ParseInLocation("Jan _2 15:04:05", m[1], p.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The log went past new year
+	if t.Month() < p.month {
+		p.year++
+	}
+	p.month = t.Month()
+	t = t.AddDate(p.year-t.Year(), 0, 0)
+
+	return parseTabbed(t, m[2], m[3]), nil
+}
@@ -0,0 +1,297 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+const (
+	FormatIrssi   = "irssi"
+	FormatWeechat = "weechat"
+	FormatZNC     = "znc"
+	FormatHexChat = "hexchat"
+)
+
+var Formats = []string{FormatIrssi, FormatWeechat, FormatZNC, FormatHexChat}
+
+type Options struct {
+	Format  string
+	Network string
+	Channel string
+	// Date is the day the log starts on, ZNC logs do not include it and the
+	// other formats use it until the log tells them otherwise
+	Date time.Time
+	// Location is the time zone of the timestamps in the log, it defaults
+	// to the local time zone
+	Location *time.Location
+}
+
+// entry is a line of a log that maps to a message or an event
+type entry struct {
+	time    time.Time
+	from    string
+	content string
+	event   *storage.Event
+	line    string
+}
+
+type lineParser interface {
+	// parseLine returns nil for lines that do not map to anything
+	parseLine(line string) (*entry, error)
+}
+
+// Import parses a log and calls fn with the messages in the order they were
+// logged, events get collapsed the same way they are when logged live
+func Import(r io.Reader, opts Options, fn func(*storage.Message) error) error {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Network == "" || opts.Channel == "" {
+		return fmt.Errorf("the network and channel of the log are required")
+	}
+
+	var parser lineParser
+	switch opts.Format {
+	case FormatIrssi:
+		parser = newIrssiParser(opts)
+	case FormatWeechat:
+		parser = newWeechatParser(opts)
+	case FormatZNC:
+		if opts.Date.IsZero() {
+			return fmt.Errorf("znc logs need a date")
+		}
+		parser = newZNCParser(opts)
+	case FormatHexChat:
+		parser = newHexChatParser(opts)
+	default:
+		return fmt.Errorf("unknown log format: %s", opts.Format)
+	}
+
+	b := &builder{
+		network: opts.Network,
+		channel: opts.Channel,
+		fn:      fn,
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		e, err := parser.parseLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		if e == nil {
+			continue
+		}
+		e.line = line
+
+		err = b.add(e)
+		if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return b.flush()
+}
+
+// PathInfo returns the channel and date the default log paths of a format
+// include, they are empty when the path does not include them
+func PathInfo(format, path string, loc *time.Location) (string, time.Time) {
+	if loc == nil {
+		loc = time.Local
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	switch format {
+	case FormatWeechat:
+		// irc.<server>.<channel>.weechatlog
+		if parts := strings.SplitN(name, ".", 3); len(parts) == 3 && parts[0] == "irc" {
+			return parts[2], time.Time{}
+		}
+	case FormatZNC:
+		// <network>/<channel>/<date>.log
+		date, err := time.ParseInLocation("2006-01-02", name, loc)
+		if err == nil {
+			return filepath.Base(filepath.Dir(path)), date
+		}
+		return "", time.Time{}
+	}
+
+	return name, time.Time{}
+}
+
+// builder turns entries into messages
+type builder struct {
+	network string
+	channel string
+	fn      func(*storage.Message) error
+	pending *storage.Message
+
+	second int64
+	seq    int
+}
+
+func (b *builder) add(e *entry) error {
+	if e.event != nil {
+		if b.pending != nil && storage.ShouldCollapse(b.pending, *e.event) {
+			b.pending.Events = append(b.pending.Events, *e.event)
+			return nil
+		}
+
+		err := b.flush()
+		if err != nil {
+			return err
+		}
+
+		b.pending = &storage.Message{
+			ID:      b.id(e),
+			Network: b.network,
+			To:      b.channel,
+			Time:    e.time.Unix(),
+			Events:  []storage.Event{*e.event},
+		}
+		return nil
+	}
+
+	err := b.flush()
+	if err != nil {
+		return err
+	}
+
+	return b.fn(&storage.Message{
+		ID:      b.id(e),
+		Network: b.network,
+		From:    e.from,
+		To:      b.channel,
+		Content: e.content,
+		Time:    e.time.Unix(),
+	})
+}
+
+func (b *builder) flush() error {
+	if b.pending == nil {
+		return nil
+	}
+
+	msg := b.pending
+	b.pending = nil
+	return b.fn(msg)
+}
+
+const idChars = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+// id returns an ID in the betterguid format, it starts with the time in
+// milliseconds so it sorts with the IDs of messages logged live. The rest
+// is made up of the position of the entry within its second, which keeps
+// entries with the same timestamp in order, and a hash of the line, which
+// makes importing the same log twice overwrite the messages from the first
+// import instead of adding them again.
+func (b *builder) id(e *entry) string {
+	second := e.time.Unix()
+	if second != b.second {
+		b.second = second
+		b.seq = 0
+	}
+	seq := b.seq
+	b.seq++
+
+	var id [20]byte
+
+	ms := second * 1000
+	for i := 7; i >= 0; i-- {
+		id[i] = idChars[ms%64]
+		ms /= 64
+	}
+
+	for i := 10; i >= 8; i-- {
+		id[i] = idChars[seq%64]
+		seq /= 64
+	}
+
+	hash := sha1.Sum([]byte(b.network + "\x00" + b.channel + "\x00" + e.line))
+	for i := 11; i < 20; i++ {
+		id[i] = idChars[hash[i]%64]
+	}
+
+	return string(id[:])
+}
+
+type eventPattern struct {
+	typ string
+	re  *regexp.Regexp
+	// params are the submatches of re that make up the params of the
+	// event, in the order the server logs them
+	params []int
+}
+
+func pattern(typ, re string, params ...int) eventPattern {
+	return eventPattern{
+		typ:    typ,
+		re:     regexp.MustCompile(re),
+		params: params,
+	}
+}
+
+func matchEvent(patterns []eventPattern, s string) *storage.Event {
+	for _, p := range patterns {
+		m := p.re.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+
+		event := &storage.Event{
+			Type: p.typ,
+		}
+		for _, i := range p.params {
+			event.Params = append(event.Params, m[i])
+		}
+
+		// Parts and kicks without a reason are logged without the param
+		last := len(event.Params) - 1
+		if (p.typ == "part" || p.typ == "kick") && event.Params[last] == "" {
+			event.Params = event.Params[:last]
+		}
+
+		return event
+	}
+
+	return nil
+}
+
+func newEntry(t time.Time, event *storage.Event) *entry {
+	event.Time = t.Unix()
+	return &entry{
+		time:  t,
+		event: event,
+	}
+}
+
+func action(text string) string {
+	return "\x01ACTION " + text + "\x01"
+}
+
+func stripNickPrefix(nick string) string {
+	return strings.TrimLeft(nick, " ~&@%+")
+}
+
+// onDate returns the time of day in clock on the day of date
+func onDate(date, clock time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+func importString(t *testing.T, log string, opts Options) []*storage.Message {
+	opts.Network = "freenode"
+	if opts.Channel == "" {
+		opts.Channel = "#go"
+	}
+	opts.Location = time.UTC
+
+	var messages []*storage.Message
+	err := Import(strings.NewReader(log), opts, func(msg *storage.Message) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	require.Nil(t, err)
+	return messages
+}
+
+func unix(s string) int64 {
+	t, _ := time.Parse("2006-01-02 15:04:05", s)
+	return t.Unix()
+}
+
+func assertMessages(t *testing.T, messages []*storage.Message) {
+	require.Len(t, messages, 5)
+
+	assert.Equal(t, "alice", messages[0].From)
+	assert.Equal(t, "hello", messages[0].Content)
+	assert.Equal(t, "#go", messages[0].To)
+	assert.Equal(t, "freenode", messages[0].Network)
+	assert.Equal(t, unix("2019-03-12 21:04:10"), messages[0].Time)
+
+	assert.Equal(t, "\x01ACTION waves\x01", messages[1].Content)
+	assert.Equal(t, "bob", messages[1].From)
+
+	assert.Equal(t, unix("2019-03-12 21:05:00"), messages[2].Time)
+	assert.Equal(t, []storage.Event{
+		{Type: "join", Params: []string{"carol"}, Time: unix("2019-03-12 21:05:00")},
+		{Type: "part", Params: []string{"dave", "bye"}, Time: unix("2019-03-12 21:05:01")},
+		{Type: "quit", Params: []string{"eve", "Quit: leaving"}, Time: unix("2019-03-12 21:05:02")},
+		{Type: "nick", Params: []string{"carol", "carol_"}, Time: unix("2019-03-12 21:05:03")},
+	}, messages[2].Events)
+
+	assert.Equal(t, []storage.Event{
+		{Type: "kick", Params: []string{"bob", "alice", "spam"}, Time: unix("2019-03-13 00:01:00")},
+	}, messages[3].Events)
+
+	assert.Equal(t, []storage.Event{
+		{Type: "topic", Params: []string{"alice", "Go is fun"}, Time: unix("2019-03-13 00:02:00")},
+	}, messages[4].Events)
+
+	ids := make([]string, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+		assert.Len(t, msg.ID, 20)
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+}
+
+func TestImportIrssi(t *testing.T) {
+	log := `--- Log opened Tue Mar 12 21:04:00 2019
+21:04:10 <@alice> hello
+21:04:20  * bob waves
+21:05:00 -!- carol [~carol@host] has joined #go
+21:05:01 -!- dave [~dave@host] has left #go [bye]
+21:05:02 -!- eve [~eve@host] has quit [Quit: leaving]
+21:05:03 -!- carol is now known as carol_
+21:05:04 -!- mode/#go [+o alice] by ChanServ
+--- Day changed Wed Mar 13 2019
+00:01 -!- bob was kicked from #go by alice [spam]
+00:02 -!- alice changed the topic of #go to: Go is fun
+--- Log closed Wed Mar 13 00:03:00 2019
+`
+	assertMessages(t, importString(t, log, Options{Format: FormatIrssi}))
+
+	err := Import(strings.NewReader("21:04 <alice> hi"), Options{
+		Format:  FormatIrssi,
+		Network: "freenode",
+		Channel: "#go",
+	}, func(*storage.Message) error { return nil })
+	assert.NotNil(t, err)
+}
+
+func TestImportWeechat(t *testing.T) {
+	log := "2019-03-12 21:04:10\t@alice\thello\n" +
+		"2019-03-12 21:04:20\t *\tbob waves\n" +
+		"2019-03-12 21:05:00\t-->\tcarol (~carol@host) has joined #go\n" +
+		"2019-03-12 21:05:01\t<--\tdave (~dave@host) has left #go (bye)\n" +
+		"2019-03-12 21:05:02\t<--\teve (~eve@host) has quit (Quit: leaving)\n" +
+		"2019-03-12 21:05:03\t--\tcarol is now known as carol_\n" +
+		"2019-03-12 21:05:04\t--\tMode #go [+o alice] by ChanServ\n" +
+		"2019-03-13 00:01:00\t<--\talice has kicked bob (spam)\n" +
+		"2019-03-13 00:02:00\t--\talice has changed topic for #go from \"old\" to \"Go is fun\"\n"
+
+	assertMessages(t, importString(t, log, Options{Format: FormatWeechat}))
+}
+
+func TestImportZNC(t *testing.T) {
+	day1 := `[21:04:10] <alice> hello
+[21:04:20] * bob waves
+[21:05:00] *** Joins: carol (~carol@host)
+[21:05:01] *** Parts: dave (~dave@host) (bye)
+[21:05:02] *** Quits: eve (~eve@host) (Quit: leaving)
+[21:05:03] *** carol is now known as carol_
+`
+	day2 := `[00:01:00] *** bob was kicked by alice (spam)
+[00:02:00] *** alice changes topic to 'Go is fun'
+`
+	date := time.Date(2019, 3, 12, 0, 0, 0, 0, time.UTC)
+	messages := importString(t, day1, Options{Format: FormatZNC, Date: date})
+	messages = append(messages, importString(t, day2, Options{Format: FormatZNC, Date: date.AddDate(0, 0, 1)})...)
+	assertMessages(t, messages)
+
+	err := Import(strings.NewReader(day1), Options{
+		Format:  FormatZNC,
+		Network: "freenode",
+		Channel: "#go",
+	}, func(*storage.Message) error { return nil })
+	assert.NotNil(t, err)
+}
+
+func TestImportHexChat(t *testing.T) {
+	log := "**** BEGIN LOGGING AT Tue Mar 12 21:04:00 2019\n\n" +
+		"Mar 12 21:04:10 <alice>\thello\n" +
+		"Mar 12 21:04:20 *\tbob waves\n" +
+		"Mar 12 21:05:00 -->\tcarol (~carol@host) has joined #go\n" +
+		"Mar 12 21:05:01 <--\tdave (~dave@host) has left #go (bye)\n" +
+		"Mar 12 21:05:02 <--\teve has quit (Quit: leaving)\n" +
+		"Mar 12 21:05:03 ---\tcarol is now known as carol_\n" +
+		"Mar 13 00:01:00 <--\talice has kicked bob from #go (spam)\n" +
+		"Mar 13 00:02:00 ---\talice has changed the topic to: Go is fun\n" +
+		"**** ENDING LOGGING AT Wed Mar 13 00:03:00 2019\n"
+
+	assertMessages(t, importString(t, log, Options{Format: FormatHexChat}))
+
+	messages := importString(t, "Dec 31 23:59:59 <alice>\ta\nJan 01 00:00:00 <alice>\tb\n", Options{
+		Format: FormatHexChat,
+		Date:   time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+	require.Len(t, messages, 2)
+	assert.Equal(t, unix("2020-01-01 00:00:00"), messages[1].Time)
+}
+
+func TestImportIDs(t *testing.T) {
+	log := "2019-03-12 21:04:10\talice\tsame\n" +
+		"2019-03-12 21:04:10\talice\tsame\n" +
+		"2019-03-12 21:04:10\tbob\tsecond\n"
+
+	first := importString(t, log, Options{Format: FormatWeechat})
+	second := importString(t, log, Options{Format: FormatWeechat})
+	require.Len(t, first, 3)
+
+	ids := map[string]bool{}
+	for i := range first {
+		assert.Equal(t, first[i].ID, second[i].ID)
+		ids[first[i].ID] = true
+	}
+	assert.Len(t, ids, 3)
+	assert.True(t, first[0].ID < first[1].ID)
+	assert.True(t, first[1].ID < first[2].ID)
+
+	// Messages logged live sort after the imported ones
+	assert.True(t, first[2].ID < betterguid.New())
+}
+
+func TestPathInfo(t *testing.T) {
+	channel, date := PathInfo(FormatIrssi, "/home/me/irclogs/freenode/#go.log", time.UTC)
+	assert.Equal(t, "#go", channel)
+	assert.True(t, date.IsZero())
+
+	channel, _ = PathInfo(FormatWeechat, "logs/irc.freenode.#go.weechatlog", time.UTC)
+	assert.Equal(t, "#go", channel)
+
+	channel, date = PathInfo(FormatZNC, "moddata/log/freenode/#go/2019-03-12.log", time.UTC)
+	assert.Equal(t, "#go", channel)
+	assert.Equal(t, time.Date(2019, 3, 12, 0, 0, 0, 0, time.UTC), date)
+
+	channel, _ = PathInfo(FormatHexChat, "logs/freenode/#go.log", time.UTC)
+	assert.Equal(t, "#go", channel)
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	irssiOpened     = regexp.MustCompile(`^--- Log opened (.+)$`)
+	irssiDayChanged = regexp.MustCompile(`^--- Day changed (.+)$`)
+	irssiLine       = regexp.MustCompile(`^(\d\d:\d\d(?::\d\d)?) (.*)$`)
+	irssiMessage    = regexp.MustCompile(`^<[ ~&@%+]?([^>]+)> (.*)$`)
+	irssiAction     = regexp.MustCompile(`^ \* (\S+) (.*)$`)
+
+	irssiEvents = []eventPattern{
+		pattern("join", `^-!- (\S+) \[[^\]]*\] has joined \S+$`, 1),
+		pattern("part", `^-!- (\S+) \[[^\]]*\] has left \S+ \[(.*)\]$`, 1, 2),
+		pattern("quit", `^-!- (\S+) \[[^\]]*\] has quit \[(.*)\]$`, 1, 2),
+		pattern("nick", `^-!- (\S+) is now known as (\S+)$`, 1, 2),
+		pattern("kick", `^-!- (\S+) was kicked from \S+ by (\S+) \[(.*)\]$`, 1, 2, 3),
+		pattern("topic", `^-!- (\S+) changed the topic of \S+ to: (.*)$`, 1, 2),
+	}
+)
+
+// irssiParser parses the default irssi log format, where the date only
+// shows up in the lines that open the log and change the day
+type irssiParser struct {
+	date time.Time
+	loc  *time.Location
+}
+
+func newIrssiParser(opts Options) *irssiParser {
+	return &irssiParser{
+		date: opts.Date,
+		loc:  opts.Location,
+	}
+}
+
+func (p *irssiParser) parseLine(line string) (*entry, error) {
+	if m := irssiOpened.FindStringSubmatch(line); m != nil {
+		date, err := time.ParseInLocation("Mon Jan 02 15:04:05 2006", m[1], p.loc)
+		if err != nil {
+			return nil, err
+		}
+		p.date = date
+		return nil, nil
+	}
+
+	if m := irssiDayChanged.FindStringSubmatch(line); m != nil {
+		date, err := time.ParseInLocation("Mon Jan 02 2006", m[1], p.loc)
+		if err != nil {
+			return nil, err
+		}
+		p.date = date
+		return nil, nil
+	}
+
+	m := irssiLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, nil
+	}
+	if p.date.IsZero() {
+		return nil, fmt.Errorf("the log does not say what day it starts on, pass a date")
+	}
+
+	layout := "15:04"
+	if len(m[1]) > 5 {
+		layout = "15:04:05"
+	}
+	clock, err := time.Parse(layout, m[1])
+	if err != nil {
+		return nil, err
+	}
+	t := onDate(p.date, clock, p.loc)
+	rest := m[2]
+
+	if m := irssiMessage.FindStringSubmatch(rest); m != nil {
+		return &entry{time: t, from: m[1], content: m[2]}, nil
+	}
+	if m := irssiAction.FindStringSubmatch(rest); m != nil {
+		return &entry{time: t, from: m[1], content: action(m[2])}, nil
+	}
+	if event := matchEvent(irssiEvents, rest); event != nil {
+		return newEntry(t, event), nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	weechatLine = regexp.MustCompile(`^(\d{4}-\d\d-\d\d \d\d:\d\d:\d\d)\t([^\t]*)\t(.*)$`)
+
+	// tabbedEvents match the events of the formats that separate the
+	// prefix of a line from the text with a tab, weechat and HexChat
+	tabbedEvents = []eventPattern{
+		pattern("join", `^(\S+) \([^)]*\) has joined \S+$`, 1),
+		pattern("part", `^(\S+) \([^)]*\) has left \S+(?: \((.*)\))?$`, 1, 2),
+		pattern("quit", `^(\S+)(?: \([^)]*\))? has quit(?: \((.*)\))?$`, 1, 2),
+		pattern("nick", `^(\S+) is now known as (\S+)$`, 1, 2),
+		pattern("kick", `^(\S+) has kicked (\S+)(?: from \S+)?(?: \((.*)\))?$`, 2, 1, 3),
+		pattern("topic", `^(\S+) has changed topic for \S+ (?:from ".*" )?to "(.*)"$`, 1, 2),
+		pattern("topic", `^(\S+) has changed the topic to: (.*)$`, 1, 2),
+	}
+)
+
+type weechatParser struct {
+	loc *time.Location
+}
+
+func newWeechatParser(opts Options) *weechatParser {
+	return &weechatParser{
+		loc: opts.Location,
+	}
+}
+
+func (p *weechatParser) parseLine(line string) (*entry, error) {
+	m := weechatLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", m[1], p.loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTabbed(t, m[2], m[3]), nil
+}
+
+// parseTabbed parses the part of a weechat or HexChat line that comes after
+// the timestamp, the prefix is the nick for messages
+func parseTabbed(t time.Time, prefix, text string) *entry {
+	prefix = strings.TrimSpace(prefix)
+
+	switch prefix {
+	case "-->", "<--", "--", "---":
+		if event := matchEvent(tabbedEvents, text); event != nil {
+			return newEntry(t, event)
+		}
+		return nil
+
+	case "*":
+		parts := strings.SplitN(text, " ", 2)
+		if len(parts) < 2 {
+			return nil
+		}
+		return &entry{time: t, from: stripNickPrefix(parts[0]), content: action(parts[1])}
+
+	case "", "=!=", "-->>", "<<--":
+		return nil
+	}
+
+	prefix = strings.TrimSuffix(strings.TrimPrefix(prefix, "<"), ">")
+	return &entry{time: t, from: stripNickPrefix(prefix), content: text}
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"regexp"
+	"time"
+)
+
+var (
+	zncLine    = regexp.MustCompile(`^\[(\d\d:\d\d:\d\d)\] (.*)$`)
+	zncMessage = regexp.MustCompile(`^<([^>]+)> (.*)$`)
+	zncAction  = regexp.MustCompile(`^\* (\S+) (.*)$`)
+
+	zncEvents = []eventPattern{
+		pattern("join", `^\*\*\* Joins: (\S+) \([^)]*\)$`, 1),
+		pattern("part", `^\*\*\* Parts: (\S+) \([^)]*\) \((.*)\)$`, 1, 2),
+		pattern("quit", `^\*\*\* Quits: (\S+) \([^)]*\) \((.*)\)$`, 1, 2),
+		pattern("nick", `^\*\*\* (\S+) is now known as (\S+)$`, 1, 2),
+		pattern("kick", `^\*\*\* (\S+) was kicked by (\S+) \((.*)\)$`, 1, 2, 3),
+		pattern("topic", `^\*\*\* (\S+) changes topic to '(.*)'$`, 1, 2),
+	}
+)
+
+// zncParser parses the logs of the ZNC log module, they are split into a
+// file per day and the date is only part of the file name
+type zncParser struct {
+	date time.Time
+	loc  *time.Location
+}
+
+func newZNCParser(opts Options) *zncParser {
+	return &zncParser{
+		date: opts.Date,
+		loc:  opts.Location,
+	}
+}
+
+func (p *zncParser) parseLine(line string) (*entry, error) {
+	m := zncLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, nil
+	}
+
+	clock, err := time.Parse("15:04:05", m[1])
+	if err != nil {
+		return nil, err
+	}
+	t := onDate(p.date, clock, p.loc)
+	rest := m[2]
+
+	if event := matchEvent(zncEvents, rest); event != nil {
+		return newEntry(t, event), nil
+	}
+	if m := zncMessage.FindStringSubmatch(rest); m != nil {
+		return &entry{time: t, from: stripNickPrefix(m[1]), content: m[2]}, nil
+	}
+	if m := zncAction.FindStringSubmatch(rest); m != nil {
+		return &entry{time: t, from: m[1], content: action(m[2])}, nil
+	}
+
+	return nil, nil
+}
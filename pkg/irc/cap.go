@@ -4,7 +4,7 @@ import (
 	"strings"
 )
 
-var clientWantedCaps = []string{"cap-notify"}
+var clientWantedCaps = []string{"cap-notify", ReadMarkerCap}
 
 func (c *Client) GetCapability(name string) ([]string, bool) {
 	c.lock.Lock()
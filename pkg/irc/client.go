@@ -12,9 +12,11 @@ import (
 )
 
 type Config struct {
-	Host           string
-	Port           string
-	TLS            bool
+	Host string
+	Port string
+	TLS  bool
+	// Servers get tried in order after Host when connecting fails
+	Servers        []Server
 	TLSConfig      *tls.Config
 	ServerPassword string
 	Nick           string
@@ -32,11 +34,37 @@ type Config struct {
 	// Source is the reply to SOURCE CTCP messages
 	Source string
 
+	// WebIRC returns the WEBIRC block to send to a server before
+	// registration, it lets the server see the real host and IP of the user
+	// behind the gateway. It gets called every time a connection is made
+	// since servers can have different gateway passwords, nil means none.
+	WebIRC func(Server) *WebIRC
+	// GetServerPassword returns the password to send to a server when
+	// ServerPassword is empty, it gets called every time a connection is
+	// made like WebIRC.
+	GetServerPassword func(Server) string
+
 	HandleNickInUse func(string) string
+	// IgnoreCTCP returns true for CTCP requests that should not get an
+	// automatic reply
+	IgnoreCTCP func(*Message) bool
 
 	Dialer Dialer
 }
 
+type Server struct {
+	Host string
+	Port string
+	TLS  bool
+}
+
+type WebIRC struct {
+	Password string
+	Gateway  string
+	Hostname string
+	IP       string
+}
+
 type Client struct {
 	Config *Config
 
@@ -47,6 +75,8 @@ type Client struct {
 	state    *state
 	nick     string
 	channels []string
+	servers  []Server
+	server   int
 
 	wantedCapabilities    []string
 	requestedCapabilities map[string][]string
@@ -72,11 +102,19 @@ type Client struct {
 
 func NewClient(config *Config) *Client {
 	if config.Port == "" {
-		if config.TLS {
-			config.Port = "6697"
-		} else {
-			config.Port = "6667"
+		config.Port = defaultPort(config.TLS)
+	}
+
+	servers := []Server{{
+		Host: config.Host,
+		Port: config.Port,
+		TLS:  config.TLS,
+	}}
+	for _, server := range config.Servers {
+		if server.Port == "" {
+			server.Port = defaultPort(server.TLS)
 		}
+		servers = append(servers, server)
 	}
 
 	if config.Username == "" {
@@ -101,6 +139,7 @@ func NewClient(config *Config) *Client {
 		ConnectionChanged:     make(chan ConnectionState, 4),
 		Features:              NewFeatures(),
 		nick:                  config.Nick,
+		servers:               servers,
 		requestedCapabilities: map[string][]string{},
 		enabledCapabilities:   map[string][]string{},
 		dialer:                config.Dialer,
@@ -120,6 +159,13 @@ func NewClient(config *Config) *Client {
 	return client
 }
 
+func defaultPort(tls bool) string {
+	if tls {
+		return "6697"
+	}
+	return "6667"
+}
+
 func (c *Client) initSASL() {
 	saslMechanisms := []SASL{}
 
@@ -191,10 +237,30 @@ func (c *Client) setRegistered(reg bool) {
 	c.lock.Unlock()
 }
 
+// Host returns the host of the first server, it identifies the network
+// regardless of which server the client is connected to
 func (c *Client) Host() string {
 	return c.Config.Host
 }
 
+// Server returns the server the client is connected or trying to connect to
+func (c *Client) Server() Server {
+	c.lock.Lock()
+	server := c.servers[c.server]
+	c.lock.Unlock()
+	return server
+}
+
+// nextServer moves on to the next server and returns its index,
+// it wraps around to 0 when all servers have been tried
+func (c *Client) nextServer() int {
+	c.lock.Lock()
+	c.server = (c.server + 1) % len(c.servers)
+	i := c.server
+	c.lock.Unlock()
+	return i
+}
+
 func (c *Client) LocalAddr() net.Addr {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -304,6 +370,21 @@ func (c *Client) writePass(password string) {
 	c.write("PASS " + password)
 }
 
+func (c *Client) writeWebIRC(webirc *WebIRC) {
+	ip := webirc.IP
+	if strings.HasPrefix(ip, ":") {
+		// A leading colon would turn the IP into the trailing parameter
+		ip = "0" + ip
+	}
+
+	hostname := webirc.Hostname
+	if hostname == "" {
+		hostname = ip
+	}
+
+	c.writef(WEBIRC+" %s %s %s %s", webirc.Password, webirc.Gateway, hostname, ip)
+}
+
 func (c *Client) writeNick(nick string) {
 	c.write("NICK " + nick)
 }
@@ -317,9 +398,18 @@ func (c *Client) authenticate(response string) {
 }
 
 func (c *Client) register() {
+	if c.Config.WebIRC != nil {
+		if webirc := c.Config.WebIRC(c.Server()); webirc != nil {
+			c.writeWebIRC(webirc)
+		}
+	}
 	c.beginCAP()
-	if c.Config.ServerPassword != "" {
-		c.writePass(c.Config.ServerPassword)
+	password := c.Config.ServerPassword
+	if password == "" && c.Config.GetServerPassword != nil {
+		password = c.Config.GetServerPassword(c.Server())
+	}
+	if password != "" {
+		c.writePass(password)
 	}
 	c.writeNick(c.Config.Nick)
 	c.writeUser(c.Config.Username, c.Config.Realname)
@@ -158,6 +158,66 @@ func TestRegister(t *testing.T) {
 	assert.Equal(t, "PASS pass\r\n", <-out)
 	assert.Equal(t, "NICK nick\r\n", <-out)
 	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+
+	c.Config.ServerPassword = ""
+	c.Config.WebIRC = func(server Server) *WebIRC {
+		return &WebIRC{
+			Password: "secret",
+			Gateway:  "dispatch",
+			Hostname: "host.com",
+			IP:       "1.2.3.4",
+		}
+	}
+	c.register()
+	assert.Equal(t, "WEBIRC secret dispatch host.com 1.2.3.4\r\n", <-out)
+	assert.Equal(t, "CAP LS 302\r\n", <-out)
+	assert.Equal(t, "NICK nick\r\n", <-out)
+	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+}
+
+func TestRegisterServerPassword(t *testing.T) {
+	c, out := testClientSend()
+	c.Config.Nick = "nick"
+	c.Config.Username = "user"
+	c.Config.Realname = "rn"
+	c.servers = []Server{{Host: "irc.example.com"}, {Host: "backup.example.com"}}
+	c.Config.GetServerPassword = func(server Server) string {
+		if server.Host == "backup.example.com" {
+			return "backup"
+		}
+		return ""
+	}
+
+	c.register()
+	assert.Equal(t, "CAP LS 302\r\n", <-out)
+	assert.Equal(t, "NICK nick\r\n", <-out)
+	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+
+	// It is asked again for the server it fails over to
+	c.nextServer()
+	c.register()
+	assert.Equal(t, "CAP LS 302\r\n", <-out)
+	assert.Equal(t, "PASS backup\r\n", <-out)
+	assert.Equal(t, "NICK nick\r\n", <-out)
+	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+
+	// The password of the network is sent to every server
+	c.Config.ServerPassword = "pass"
+	c.register()
+	assert.Equal(t, "CAP LS 302\r\n", <-out)
+	assert.Equal(t, "PASS pass\r\n", <-out)
+	assert.Equal(t, "NICK nick\r\n", <-out)
+	assert.Equal(t, "USER user 0 * :rn\r\n", <-out)
+}
+
+func TestWebIRC(t *testing.T) {
+	c, out := testClientSend()
+	c.writeWebIRC(&WebIRC{
+		Password: "secret",
+		Gateway:  "dispatch",
+		IP:       "::1",
+	})
+	assert.Equal(t, "WEBIRC secret dispatch 0::1 0::1\r\n", <-out)
 }
 
 func TestFlushChannels(t *testing.T) {
@@ -116,6 +116,11 @@ func (c *Client) tryConnect() {
 			return
 		}
 
+		// Only back off once every server has been tried
+		if c.nextServer() != 0 {
+			continue
+		}
+
 		time.Sleep(c.backoff.Duration())
 	}
 }
@@ -124,15 +129,21 @@ func (c *Client) connect() error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	conn, err := c.dialer.Dial("tcp", net.JoinHostPort(c.Config.Host, c.Config.Port))
+	server := c.servers[c.server]
+
+	conn, err := c.dialer.Dial("tcp", net.JoinHostPort(server.Host, server.Port))
 	if err != nil {
 		return err
 	}
 
-	if c.Config.TLS {
-		c.Config.TLSConfig.ServerName = c.Config.Host
+	if server.TLS {
+		tlsConfig := &tls.Config{}
+		if c.Config.TLSConfig != nil {
+			tlsConfig = c.Config.TLSConfig.Clone()
+		}
+		tlsConfig.ServerName = server.Host
 
-		tlsConn := tls.Client(conn, c.Config.TLSConfig)
+		tlsConn := tls.Client(conn, tlsConfig)
 		err = tlsConn.Handshake()
 		if err != nil {
 			return err
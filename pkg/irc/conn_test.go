@@ -99,6 +99,20 @@ func TestConnectTLS(t *testing.T) {
 	waitConnAndClose(t, c)
 }
 
+func TestConnectNextServer(t *testing.T) {
+	c := NewClient(&Config{
+		Host: "127.0.0.1",
+		Port: "45677",
+		Servers: []Server{
+			{Host: "127.0.0.1", Port: "45678"},
+		},
+	})
+	c.Connect()
+	waitConnAndClose(t, c)
+	assert.Equal(t, Server{Host: "127.0.0.1", Port: "45678"}, c.Server())
+	assert.Equal(t, "127.0.0.1", c.Host())
+}
+
 func TestConnectDefaultPorts(t *testing.T) {
 	c := NewClient(&Config{
 		Host: "127.0.0.1",
@@ -110,6 +124,16 @@ func TestConnectDefaultPorts(t *testing.T) {
 		TLS:  true,
 	})
 	assert.Equal(t, "6697", c.Config.Port)
+
+	c = NewClient(&Config{
+		Host: "127.0.0.1",
+		Servers: []Server{
+			{Host: "127.0.0.2"},
+			{Host: "127.0.0.3", TLS: true},
+		},
+	})
+	assert.Equal(t, "6667", c.servers[1].Port)
+	assert.Equal(t, "6697", c.servers[2].Port)
 }
 
 func TestWrite(t *testing.T) {
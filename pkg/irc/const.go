@@ -29,6 +29,8 @@ const (
 	ERROR        = "ERROR"
 	PING         = "PING"
 	PONG         = "PONG"
+	WEBIRC       = "WEBIRC"
+	MARKREAD     = "MARKREAD"
 
 	RPL_WELCOME           = "001"
 	RPL_YOURHOST          = "002"
@@ -59,7 +59,8 @@ func (c *Client) handleMessage(msg *Message) {
 
 	case PRIVMSG:
 		if c.Config.AutoCTCP {
-			if ctcp := msg.ToCTCP(); ctcp != nil {
+			if ctcp := msg.ToCTCP(); ctcp != nil &&
+				(c.Config.IgnoreCTCP == nil || !c.Config.IgnoreCTCP(msg)) {
 				c.handleCTCP(ctcp, msg)
 			}
 		}
@@ -0,0 +1,45 @@
+package irc
+
+import (
+	"strings"
+	"time"
+)
+
+// ReadMarkerCap lets the clients of an account share where they stopped
+// reading, https://ircv3.net/specs/extensions/read-marker
+const ReadMarkerCap = "draft/read-marker"
+
+const readMarkerTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// MarkRead tells the server that everything in target up to t has been
+// read, it does nothing when the server does not support read markers
+func (c *Client) MarkRead(target string, t time.Time) {
+	if c.HasCapability(ReadMarkerCap) {
+		c.Writef("MARKREAD %s timestamp=%s", target, t.UTC().Format(readMarkerTimeFormat))
+	}
+}
+
+// ParseReadMarker returns the target and time of a MARKREAD message, the
+// time is zero when the target has no read marker
+func ParseReadMarker(msg *Message) (string, time.Time, bool) {
+	if msg.Command != MARKREAD || len(msg.Params) < 2 {
+		return "", time.Time{}, false
+	}
+
+	ts := msg.Params[1]
+	if !strings.HasPrefix(ts, "timestamp=") {
+		return "", time.Time{}, false
+	}
+
+	ts = ts[len("timestamp="):]
+	if ts == "*" {
+		return msg.Params[0], time.Time{}, true
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	return msg.Params[0], t, true
+}
@@ -0,0 +1,36 @@
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkRead(t *testing.T) {
+	c, out := testClientSend()
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	// Nothing gets sent unless the server supports read markers
+	c.MarkRead("#chan", ts)
+	c.enabledCapabilities[ReadMarkerCap] = nil
+	c.MarkRead("#chan", ts)
+	assert.Equal(t, "MARKREAD #chan timestamp=2020-01-02T03:04:05.006Z\r\n", <-out)
+}
+
+func TestParseReadMarker(t *testing.T) {
+	target, ts, ok := ParseReadMarker(ParseMessage("MARKREAD #chan timestamp=2020-01-02T03:04:05.006Z"))
+	assert.True(t, ok)
+	assert.Equal(t, "#chan", target)
+	assert.True(t, ts.Equal(time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)))
+
+	target, ts, ok = ParseReadMarker(ParseMessage("MARKREAD nick timestamp=*"))
+	assert.True(t, ok)
+	assert.Equal(t, "nick", target)
+	assert.True(t, ts.IsZero())
+
+	_, _, ok = ParseReadMarker(ParseMessage("MARKREAD #chan"))
+	assert.False(t, ok)
+	_, _, ok = ParseReadMarker(ParseMessage("MARKREAD #chan timestamp=yesterday"))
+	assert.False(t, ok)
+}
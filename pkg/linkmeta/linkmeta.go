@@ -1,24 +1,153 @@
 package linkmeta
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
 	"strings"
+	"syscall"
 	"time"
 
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
+
+	"github.com/khlieng/dispatch/pkg/netutil"
+)
+
+const (
+	// MaxBodySize is how much of a page gets read looking for metadata
+	MaxBodySize = 1 << 20
+	// MaxRedirects is how many redirects get followed
+	MaxRedirects = 5
 )
 
 var (
-	Client = &http.Client{
-		Timeout: 15 * time.Second,
-	}
+	// Client fetches the links, use NewClient to replace it
+	Client = NewClient(Options{})
 
-	ErrContentType = errors.New("Unsupported Content-Type")
+	ErrContentType   = errors.New("Unsupported Content-Type")
+	ErrAddress       = errors.New("Links to private addresses are not allowed")
+	ErrScheme        = errors.New("Only http and https links are supported")
+	ErrTooManyHops   = fmt.Errorf("Stopped after %d redirects", MaxRedirects)
+	contentTypesHTML = []string{"text/html", "application/xhtml+xml"}
 )
 
+// Dialer makes the connections when fetching through a proxy
+type Dialer interface {
+	Dial(network, address string) (net.Conn, error)
+}
+
+type Options struct {
+	// Dialer connects through a proxy, the proxy gets to resolve the host
+	// so the host gets resolved and checked before connecting
+	Dialer Dialer
+	// AllowPrivate allows links to private and loopback addresses
+	AllowPrivate bool
+	Timeout      time.Duration
+}
+
+// NewClient returns a http.Client that refuses to connect to private,
+// loopback and link-local addresses, including through redirects
+func NewClient(opts Options) *http.Client {
+	if opts.Timeout == 0 {
+		opts.Timeout = 15 * time.Second
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+	}
+	if !opts.AllowPrivate {
+		// The check runs on the address that is actually being connected to,
+		// hosts that resolve to something else the second time do not get past it
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowedIP(net.ParseIP(host)) {
+				return ErrAddress
+			}
+			return nil
+		}
+	}
+
+	dial := dialer.DialContext
+	if opts.Dialer != nil {
+		dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			if !opts.AllowPrivate {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return nil, err
+				}
+				err = checkHost(ctx, host)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return opts.Dialer.Dial(network, address)
+		}
+	}
+
+	return &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			DialContext:           dial,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConns:          16,
+			IdleConnTimeout:       time.Minute,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= MaxRedirects {
+				return ErrTooManyHops
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrScheme
+			}
+			return nil
+		},
+	}
+}
+
+func allowedIP(ip net.IP) bool {
+	return ip != nil &&
+		!netutil.IsPrivateIP(ip) &&
+		!ip.IsLoopback() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast()
+}
+
+// checkHost resolves host and checks all of its addresses
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !allowedIP(ip) {
+			return ErrAddress
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrAddress
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !allowedIP(addr.IP) {
+			return ErrAddress
+		}
+	}
+	return nil
+}
+
 type Meta struct {
 	URL         string `json:"URL"`
 	SiteName    string `json:"siteName,omitempty"`
@@ -27,25 +156,114 @@ type Meta struct {
 	Description string `json:"description"`
 	ImageURL    string `json:"imageURL,omitempty"`
 	VideoURL    string `json:"videoURL,omitempty"`
+
+	// Type is image, video or audio when the link goes straight to a file
+	Type     string `json:"type,omitempty"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+
+	// ThumbnailURL is where a smaller version of the image can be found
+	ThumbnailURL string `json:"thumbnailURL,omitempty"`
+
+	// Author and EmbedType come from oEmbed, Width and Height are the
+	// dimensions of the embed then
+	Author    string `json:"author,omitempty"`
+	AuthorURL string `json:"authorURL,omitempty"`
+	EmbedType string `json:"embedType,omitempty"`
+}
+
+// Fetcher fetches the metadata of links, providers get asked first and
+// pages that point to an oEmbed endpoint get it from there
+type Fetcher struct {
+	// Client should be a client from NewClient since it is what stops links
+	// to private addresses
+	Client    *http.Client
+	Providers []Provider
 }
 
 func Fetch(url string) (*Meta, error) {
-	resp, err := http.Get(url)
+	return FetchWith(Client, url)
+}
+
+// FetchWith fetches url with client and the default oEmbed providers
+func FetchWith(client *http.Client, url string) (*Meta, error) {
+	f := Fetcher{
+		Client:    client,
+		Providers: DefaultProviders,
+	}
+	return f.Fetch(url)
+}
+
+func (f *Fetcher) Fetch(url string) (*Meta, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, ErrScheme
+	}
+
+	if provider := FindProvider(f.Providers, url); provider != nil {
+		meta, err := f.oembed(provider.EndpointURL(url), url)
+		if err == nil {
+			return meta, nil
+		}
+	}
+
+	resp, err := f.Client.Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// TODO: Image links
-	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
-		return nil, ErrContentType
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	if hasContentType(resp, contentTypesHTML) {
+		meta, oembedURL, err := extractMeta(io.LimitReader(resp.Body, MaxBodySize), url)
+		if err != nil || oembedURL == "" {
+			return meta, err
+		}
+
+		if embed, err := f.oembed(oembedURL, url); err == nil {
+			meta.merge(embed)
+		}
+		return meta, nil
+	}
+
+	if mediaType(resp) != "" {
+		return ExtractMediaMeta(resp, url), nil
+	}
+
+	return nil, ErrContentType
+}
+
+func contentType(resp *http.Response) string {
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
 	}
+	return contentType
+}
 
-	return ExtractMeta(resp.Body, url)
+func hasContentType(resp *http.Response, types []string) bool {
+	contentType := contentType(resp)
+	for _, t := range types {
+		if contentType == t {
+			return true
+		}
+	}
+	return false
 }
 
 func ExtractMeta(body io.Reader, url string) (*Meta, error) {
-	meta := Meta{URL: url}
+	meta, _, err := extractMeta(body, url)
+	return meta, err
+}
+
+// extractMeta also returns the oEmbed endpoint the page points to
+func extractMeta(body io.Reader, pageURL string) (*Meta, string, error) {
+	meta := Meta{URL: pageURL}
+	var oembedURL string
 	var currentNode atom.Atom
 
 	z := html.NewTokenizer(body)
@@ -54,9 +272,9 @@ func ExtractMeta(body io.Reader, url string) (*Meta, error) {
 		switch tt {
 		case html.ErrorToken:
 			if z.Err() == io.EOF {
-				return &meta, nil
+				return &meta, oembedURL, nil
 			}
-			return nil, z.Err()
+			return nil, "", z.Err()
 
 		case html.TextToken:
 			if currentNode == atom.Title && meta.Title == "" {
@@ -118,6 +336,30 @@ func ExtractMeta(body io.Reader, url string) (*Meta, error) {
 				continue
 			}
 
+			if node == atom.Link && hasAttr && oembedURL == "" {
+				var rel, typ, href string
+				var key, val []byte
+				for hasAttr {
+					key, val, hasAttr = z.TagAttr()
+					switch atom.String(key) {
+					case "rel":
+						rel = string(val)
+
+					case "type":
+						typ = string(val)
+
+					case "href":
+						href = string(val)
+					}
+				}
+
+				if rel == "alternate" && typ == "application/json+oembed" && href != "" {
+					oembedURL = resolveURL(pageURL, href)
+				}
+
+				continue
+			}
+
 			if tt == html.StartTagToken {
 				currentNode = node
 			} else {
@@ -125,7 +367,7 @@ func ExtractMeta(body io.Reader, url string) (*Meta, error) {
 			}
 
 			if (node == atom.Head && tt == html.EndTagToken) || node == atom.Body {
-				return &meta, nil
+				return &meta, oembedURL, nil
 			}
 		}
 	}
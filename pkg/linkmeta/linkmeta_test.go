@@ -0,0 +1,213 @@
+package linkmeta
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const page = `<html><head>
+<title>Example</title>
+<meta property="og:description" content="Description">
+</head><body></body></html>`
+
+func testServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0, 1, 2, 3})
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head>")
+		fmt.Fprint(w, strings.Repeat(" ", MaxBodySize))
+		fmt.Fprint(w, "<title>Too far</title></head></html>")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFetch(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	client := NewClient(Options{AllowPrivate: true})
+
+	meta, err := FetchWith(client, srv.URL)
+	require.Nil(t, err)
+	assert.Equal(t, "Example", meta.Title)
+	assert.Equal(t, "Description", meta.Description)
+
+	_, err = FetchWith(client, srv.URL+"/file")
+	assert.Equal(t, ErrContentType, err)
+
+	_, err = FetchWith(client, srv.URL+"/redirect")
+	assert.True(t, strings.Contains(err.Error(), ErrTooManyHops.Error()))
+
+	meta, err = FetchWith(client, srv.URL+"/large")
+	require.Nil(t, err)
+	assert.Equal(t, "", meta.Title)
+
+	_, err = FetchWith(client, "file:///etc/passwd")
+	assert.Equal(t, ErrScheme, err)
+}
+
+func TestFetchPrivate(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	_, err := FetchWith(NewClient(Options{}), srv.URL)
+	require.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrAddress.Error()))
+}
+
+type testDialer struct {
+	dialed []string
+}
+
+func (d *testDialer) Dial(network, address string) (net.Conn, error) {
+	d.dialed = append(d.dialed, address)
+	return net.Dial(network, address)
+}
+
+func TestFetchDialer(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	dialer := &testDialer{}
+	_, err := FetchWith(NewClient(Options{Dialer: dialer}), srv.URL)
+	require.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrAddress.Error()))
+	assert.Len(t, dialer.dialed, 0)
+
+	_, err = FetchWith(NewClient(Options{Dialer: dialer, AllowPrivate: true}), srv.URL)
+	require.Nil(t, err)
+	assert.Equal(t, []string{strings.TrimPrefix(srv.URL, "http://")}, dialer.dialed)
+}
+
+func TestAllowedIP(t *testing.T) {
+	cases := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.allowed, allowedIP(net.ParseIP(tc.ip)), tc.ip)
+	}
+}
+
+func testImage(w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+func TestFetchMedia(t *testing.T) {
+	img := testImage(640, 480)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cat.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+		w.Write(img)
+	})
+	mux.HandleFunc("/video.mp4", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Header().Set("Content-Length", "1000")
+		w.Write(make([]byte, 1000))
+	})
+	mux.HandleFunc("/file.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewClient(Options{AllowPrivate: true})
+
+	meta, err := FetchWith(client, srv.URL+"/cat.png")
+	require.Nil(t, err)
+	assert.Equal(t, &Meta{
+		URL:      srv.URL + "/cat.png",
+		Title:    "cat.png",
+		Type:     "image",
+		MIMEType: "image/png",
+		Size:     int64(len(img)),
+		Width:    640,
+		Height:   480,
+	}, meta)
+
+	meta, err = FetchWith(client, srv.URL+"/video.mp4")
+	require.Nil(t, err)
+	assert.Equal(t, "video", meta.Type)
+	assert.Equal(t, "video/mp4", meta.MIMEType)
+	assert.Equal(t, int64(1000), meta.Size)
+	assert.Equal(t, 0, meta.Width)
+
+	_, err = FetchWith(client, srv.URL+"/file.zip")
+	assert.Equal(t, ErrContentType, err)
+}
+
+func TestThumbnail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/large.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(testImage(800, 200))
+	})
+	mux.HandleFunc("/small.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(testImage(100, 50))
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewClient(Options{AllowPrivate: true})
+
+	data, contentType, err := Thumbnail(client, srv.URL+"/large.png", 400)
+	require.Nil(t, err)
+	assert.Equal(t, "image/png", contentType)
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.Equal(t, 400, cfg.Width)
+	assert.Equal(t, 100, cfg.Height)
+
+	data, _, err = Thumbnail(client, srv.URL+"/small.png", 400)
+	require.Nil(t, err)
+	cfg, err = png.DecodeConfig(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.Equal(t, 100, cfg.Width)
+
+	_, _, err = Thumbnail(client, srv.URL+"/page", 400)
+	assert.Equal(t, ErrContentType, err)
+}
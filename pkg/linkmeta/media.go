@@ -0,0 +1,167 @@
+package linkmeta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxImageHeaderSize is how much of an image gets read looking for
+	// its dimensions, some JPEGs have a lot of metadata before them
+	MaxImageHeaderSize = 256 << 10
+	// MaxImageSize is the largest image that gets downloaded for a thumbnail
+	MaxImageSize = 10 << 20
+	// MaxImagePixels stops thumbnails of images that are small files but
+	// decode to something huge, an image this size takes 64MB decoded
+	MaxImagePixels = 16000000
+)
+
+var (
+	ErrImageTooLarge = errors.New("Image is too large")
+
+	contentTypesImage = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+)
+
+// mediaType returns image, video or audio depending on the Content-Type of
+// the response, or an empty string if it is something else
+func mediaType(resp *http.Response) string {
+	t := contentType(resp)
+	for _, media := range []string{"image", "video", "audio"} {
+		if strings.HasPrefix(t, media+"/") {
+			return media
+		}
+	}
+	return ""
+}
+
+// ExtractMediaMeta returns the metadata of a link that goes straight to an
+// image, video or audio file. Only the start of images gets read to find
+// their dimensions.
+func ExtractMediaMeta(resp *http.Response, url string) *Meta {
+	meta := &Meta{
+		URL:      url,
+		Title:    path.Base(resp.Request.URL.Path),
+		Type:     mediaType(resp),
+		MIMEType: contentType(resp),
+	}
+
+	if meta.Title == "/" || meta.Title == "." {
+		meta.Title = ""
+	}
+
+	if resp.ContentLength > 0 {
+		meta.Size = resp.ContentLength
+	}
+
+	if hasContentType(resp, contentTypesImage) {
+		cfg, _, err := image.DecodeConfig(io.LimitReader(resp.Body, MaxImageHeaderSize))
+		if err == nil {
+			meta.Width = cfg.Width
+			meta.Height = cfg.Height
+		}
+	}
+
+	return meta
+}
+
+// Thumbnail fetches the image at url and scales it down to fit within
+// size*size pixels, it returns the encoded thumbnail and its Content-Type.
+// Images that already fit are only re-encoded.
+func Thumbnail(client *http.Client, url string, size int) ([]byte, string, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, "", ErrScheme
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	if !hasContentType(resp, contentTypesImage) {
+		return nil, "", ErrContentType
+	}
+
+	if resp.ContentLength > MaxImageSize {
+		return nil, "", ErrImageTooLarge
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > MaxImageSize {
+		return nil, "", ErrImageTooLarge
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	img := scale(src, size)
+	buf := &bytes.Buffer{}
+
+	// Formats that can be transparent stay PNG, everything else is
+	// smaller as JPEG
+	if format == "png" || format == "gif" {
+		err = png.Encode(buf, img)
+		return buf.Bytes(), "image/png", err
+	}
+
+	err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+	return buf.Bytes(), "image/jpeg", err
+}
+
+// scale returns img scaled down to fit within size*size pixels, keeping
+// its aspect ratio
+func scale(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	if w > h {
+		h = h * size / w
+		w = size
+	} else {
+		w = w * size / h
+		h = size
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,271 @@
+package linkmeta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Provider is an oEmbed provider, links that match one of its schemes get
+// their metadata from its endpoint. Schemes use * as a wildcard, like
+// https://*.youtube.com/watch*
+type Provider struct {
+	Name     string
+	Endpoint string
+	Schemes  []string
+
+	// patterns are the compiled schemes of providers made with NewProvider
+	patterns []*regexp.Regexp
+}
+
+// NewProvider returns a provider with its schemes compiled, it returns an
+// error if the endpoint or one of the schemes is not an http(s) URL
+func NewProvider(name, endpoint string, schemes []string) (Provider, error) {
+	p := Provider{
+		Name:     name,
+		Endpoint: endpoint,
+		Schemes:  schemes,
+	}
+
+	if !isHTTP(endpoint) {
+		return p, fmt.Errorf("oEmbed provider %s: invalid endpoint %q", name, endpoint)
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return p, fmt.Errorf("oEmbed provider %s: %v", name, err)
+	}
+	if len(schemes) == 0 {
+		return p, fmt.Errorf("oEmbed provider %s: %v", name, errNoSchemes)
+	}
+
+	for _, scheme := range schemes {
+		if !isHTTP(scheme) {
+			return p, fmt.Errorf("oEmbed provider %s: invalid scheme %q", name, scheme)
+		}
+
+		pattern, err := schemePattern(scheme)
+		if err != nil {
+			return p, fmt.Errorf("oEmbed provider %s: %v", name, err)
+		}
+		p.patterns = append(p.patterns, pattern)
+	}
+
+	return p, nil
+}
+
+var errNoSchemes = errors.New("no schemes")
+
+func mustProvider(name, endpoint string, schemes ...string) Provider {
+	p, err := NewProvider(name, endpoint, schemes)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func isHTTP(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// DefaultProviders are sites that do not always point to their oEmbed
+// endpoint or that have better metadata there than on the page
+var DefaultProviders = []Provider{
+	mustProvider("YouTube", "https://www.youtube.com/oembed",
+		"https://*.youtube.com/watch*",
+		"https://*.youtube.com/v/*",
+		"https://youtu.be/*",
+	),
+	mustProvider("Vimeo", "https://vimeo.com/api/oembed.json",
+		"https://vimeo.com/*",
+		"https://player.vimeo.com/video/*",
+	),
+	mustProvider("SoundCloud", "https://soundcloud.com/oembed",
+		"https://soundcloud.com/*",
+	),
+	mustProvider("Twitter", "https://publish.twitter.com/oembed",
+		"https://twitter.com/*/status/*",
+		"https://*.twitter.com/*/status/*",
+	),
+	mustProvider("GitHub Gist", "https://github.com/api/oembed",
+		"https://gist.github.com/*",
+	),
+}
+
+// Match returns true if link matches one of the schemes of the provider,
+// providers that are not made with NewProvider compile their schemes on
+// every call
+func (p *Provider) Match(link string) bool {
+	if p.patterns != nil {
+		for _, pattern := range p.patterns {
+			if pattern.MatchString(link) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, scheme := range p.Schemes {
+		if pattern, err := schemePattern(scheme); err == nil && pattern.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// EndpointURL returns the URL to request the oEmbed data of link from
+func (p *Provider) EndpointURL(link string) string {
+	endpoint := strings.Replace(p.Endpoint, "{format}", "json", -1)
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+
+	q := u.Query()
+	q.Set("url", link)
+	q.Set("format", "json")
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func schemePattern(scheme string) (*regexp.Regexp, error) {
+	parts := strings.Split(scheme, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// FindProvider returns the first provider that matches link
+func FindProvider(providers []Provider, link string) *Provider {
+	for i := range providers {
+		if providers[i].Match(link) {
+			return &providers[i]
+		}
+	}
+	return nil
+}
+
+type oembedResponse struct {
+	Type         string      `json:"type"`
+	Title        string      `json:"title"`
+	AuthorName   string      `json:"author_name"`
+	AuthorURL    string      `json:"author_url"`
+	ProviderName string      `json:"provider_name"`
+	URL          string      `json:"url"`
+	ThumbnailURL string      `json:"thumbnail_url"`
+	Width        interface{} `json:"width"`
+	Height       interface{} `json:"height"`
+}
+
+// oembed fetches the oEmbed data of link from endpoint
+func (f *Fetcher) oembed(endpoint, link string) (*Meta, error) {
+	if !isHTTP(endpoint) {
+		return nil, ErrScheme
+	}
+
+	resp, err := f.Client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+
+	var res oembedResponse
+	err = json.NewDecoder(io.LimitReader(resp.Body, MaxBodySize)).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Meta{
+		URL:       link,
+		SiteName:  res.ProviderName,
+		Title:     res.Title,
+		Author:    res.AuthorName,
+		AuthorURL: res.AuthorURL,
+		EmbedType: res.Type,
+		ImageURL:  resolveURL(endpoint, res.ThumbnailURL),
+	}
+
+	// Photos are the image itself, the thumbnail is only used when
+	// there is none
+	if res.Type == "photo" && res.URL != "" {
+		meta.ImageURL = resolveURL(endpoint, res.URL)
+	}
+
+	meta.Width = oembedInt(res.Width)
+	meta.Height = oembedInt(res.Height)
+
+	return meta, nil
+}
+
+// oembedInt returns the width or height of an embed, they are numbers in
+// the spec but some providers send strings and rich embeds can leave
+// them empty
+func oembedInt(v interface{}) int {
+	switch v := v.(type) {
+	case float64:
+		return int(v)
+
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// merge fills meta with what oEmbed has, oEmbed wins where both have
+// something
+func (m *Meta) merge(embed *Meta) {
+	if embed.SiteName != "" {
+		m.SiteName = embed.SiteName
+	}
+	if embed.Title != "" {
+		m.Title = embed.Title
+	}
+	if embed.ImageURL != "" {
+		m.ImageURL = embed.ImageURL
+	}
+	if embed.Author != "" {
+		m.Author = embed.Author
+	}
+	if embed.AuthorURL != "" {
+		m.AuthorURL = embed.AuthorURL
+	}
+	if embed.EmbedType != "" {
+		m.EmbedType = embed.EmbedType
+	}
+	if embed.Width != 0 {
+		m.Width = embed.Width
+	}
+	if embed.Height != 0 {
+		m.Height = embed.Height
+	}
+}
+
+// resolveURL resolves ref relative to base, it returns ref if either of
+// them can not be parsed
+func resolveURL(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
@@ -0,0 +1,177 @@
+package linkmeta
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMatch(t *testing.T) {
+	schemes := []string{
+		"https://*.example.com/watch*",
+		"https://exam.pl/*",
+	}
+	compiled, err := NewProvider("Example", "https://example.com/oembed", schemes)
+	require.Nil(t, err)
+
+	for _, p := range []Provider{compiled, {Schemes: schemes}} {
+		assert.True(t, p.Match("https://www.example.com/watch?v=123"))
+		assert.True(t, p.Match("https://exam.pl/abc"))
+		assert.False(t, p.Match("https://www.example.com/other"))
+		assert.False(t, p.Match("http://exam.pl/abc"))
+		assert.False(t, p.Match("https://example.pl/abc"))
+	}
+
+	assert.Equal(t, &DefaultProviders[0], FindProvider(DefaultProviders, "https://youtu.be/abc"))
+	assert.Nil(t, FindProvider(DefaultProviders, "https://example.com"))
+}
+
+func TestNewProvider(t *testing.T) {
+	_, err := NewProvider("Example", "ftp://example.com/oembed", []string{"https://example.com/*"})
+	assert.NotNil(t, err)
+
+	_, err = NewProvider("Example", "https://example.com/oembed", nil)
+	assert.NotNil(t, err)
+
+	_, err = NewProvider("Example", "https://example.com/oembed", []string{"example.com/*"})
+	assert.NotNil(t, err)
+}
+
+func TestProviderEndpointURL(t *testing.T) {
+	p := Provider{Endpoint: "https://example.com/oembed.{format}?key=1"}
+	assert.Equal(t,
+		"https://example.com/oembed.json?format=json&key=1&url=https%3A%2F%2Fexample.com%2Fvideo",
+		p.EndpointURL("https://example.com/video"))
+}
+
+func oembedServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("url") {
+		case "https://videos.test/watch/1":
+			fmt.Fprint(w, `{
+				"type": "video",
+				"title": "A video",
+				"author_name": "Someone",
+				"author_url": "https://videos.test/someone",
+				"provider_name": "Videos",
+				"thumbnail_url": "/thumb.jpg",
+				"width": 640,
+				"height": "360"
+			}`)
+
+		case "https://photos.test/1":
+			fmt.Fprint(w, `{
+				"type": "photo",
+				"url": "https://photos.test/1.jpg",
+				"thumbnail_url": "https://photos.test/1_small.jpg",
+				"width": 1024,
+				"height": 768
+			}`)
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head>
+<title>Page title</title>
+<meta property="og:description" content="Page description">
+<link rel="alternate" type="application/json+oembed" href="/oembed?url=https%3A%2F%2Fphotos.test%2F1&format=json">
+</head></html>`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFetchOEmbedProvider(t *testing.T) {
+	srv := oembedServer(t)
+	defer srv.Close()
+
+	f := Fetcher{
+		Client: NewClient(Options{AllowPrivate: true}),
+		Providers: []Provider{{
+			Name:     "Videos",
+			Endpoint: srv.URL + "/oembed",
+			Schemes:  []string{"https://videos.test/watch/*"},
+		}},
+	}
+
+	meta, err := f.Fetch("https://videos.test/watch/1")
+	require.Nil(t, err)
+	assert.Equal(t, &Meta{
+		URL:       "https://videos.test/watch/1",
+		SiteName:  "Videos",
+		Title:     "A video",
+		Author:    "Someone",
+		AuthorURL: "https://videos.test/someone",
+		EmbedType: "video",
+		ImageURL:  srv.URL + "/thumb.jpg",
+		Width:     640,
+		Height:    360,
+	}, meta)
+}
+
+func TestFetchOEmbedDiscovery(t *testing.T) {
+	srv := oembedServer(t)
+	defer srv.Close()
+
+	f := Fetcher{Client: NewClient(Options{AllowPrivate: true})}
+
+	meta, err := f.Fetch(srv.URL + "/page")
+	require.Nil(t, err)
+	assert.Equal(t, &Meta{
+		URL:         srv.URL + "/page",
+		Title:       "Page title",
+		Description: "Page description",
+		EmbedType:   "photo",
+		ImageURL:    "https://photos.test/1.jpg",
+		Width:       1024,
+		Height:      768,
+	}, meta)
+}
+
+func TestFetchOEmbedFallback(t *testing.T) {
+	srv := oembedServer(t)
+	defer srv.Close()
+
+	// The page gets used when the provider has nothing
+	f := Fetcher{
+		Client: NewClient(Options{AllowPrivate: true}),
+		Providers: []Provider{{
+			Endpoint: srv.URL + "/oembed",
+			Schemes:  []string{srv.URL + "/*"},
+		}},
+	}
+
+	meta, err := f.Fetch(srv.URL + "/page")
+	require.Nil(t, err)
+	assert.Equal(t, "Page title", meta.Title)
+	assert.Equal(t, "photo", meta.EmbedType)
+}
+
+func TestMetaMerge(t *testing.T) {
+	meta := &Meta{
+		Title:    "Page title",
+		ImageURL: "https://example.com/page.jpg",
+		Width:    1200,
+		Height:   630,
+	}
+
+	// Rich embeds often leave out the dimensions
+	meta.merge(&Meta{Title: "Embed title", EmbedType: "rich"})
+	assert.Equal(t, &Meta{
+		Title:     "Embed title",
+		ImageURL:  "https://example.com/page.jpg",
+		EmbedType: "rich",
+		Width:     1200,
+		Height:    630,
+	}, meta)
+}
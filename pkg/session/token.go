@@ -0,0 +1,43 @@
+package session
+
+import (
+	"crypto/sha256"
+	"strconv"
+	"strings"
+)
+
+// TokenPrefix starts every API token, it makes them easy to tell apart from
+// session keys and to search for when they end up somewhere public
+const TokenPrefix = "dispatch_"
+
+// NewToken returns a new API token for the user with the given ID
+func NewToken(userID uint64) (string, error) {
+	key, err := newSessionKey()
+	if err != nil {
+		return "", err
+	}
+
+	return TokenPrefix + strconv.FormatUint(userID, 10) + "_" + key, nil
+}
+
+// ParseToken returns the ID of the user an API token belongs to
+func ParseToken(token string) (uint64, bool) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return 0, false
+	}
+
+	parts := strings.SplitN(token[len(TokenPrefix):], "_", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	return id, err == nil
+}
+
+// HashToken returns the hash API tokens get stored as, the tokens are random
+// so there is no need for a slow hash
+func HashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
@@ -0,0 +1,104 @@
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrInvalidKey = errors.New("Invalid VAPID key")
+
+	b64 = base64.RawURLEncoding
+)
+
+// VAPID identifies the application server to push services, browsers only
+// accept pushes signed with the key the subscription was made with
+type VAPID struct {
+	key *ecdsa.PrivateKey
+}
+
+// GenerateVAPID returns a new key pair
+func GenerateVAPID() (*VAPID, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &VAPID{key: key}, nil
+}
+
+// ParseVAPID parses a private key in the format PrivateKey returns
+func ParseVAPID(privateKey string) (*VAPID, error) {
+	d, err := b64.DecodeString(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{
+		D: new(big.Int).SetBytes(d),
+	}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(d)
+
+	return &VAPID{key: key}, nil
+}
+
+// PrivateKey returns the private key as unpadded base64url
+func (v *VAPID) PrivateKey() string {
+	d := make([]byte, 32)
+	b := v.key.D.Bytes()
+	copy(d[32-len(b):], b)
+	return b64.EncodeToString(d)
+}
+
+// PublicKey returns the uncompressed public key as unpadded base64url, it
+// is the applicationServerKey browsers subscribe with
+func (v *VAPID) PublicKey() string {
+	return b64.EncodeToString(elliptic.Marshal(v.key.Curve, v.key.X, v.key.Y))
+}
+
+// Authorization returns the Authorization header for a push to endpoint,
+// subject is a mailto: or https: URL the push service can reach the
+// operator at
+func (v *VAPID) Authorization(endpoint, subject string, exp time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": exp.Unix(),
+	}
+	if subject != "" {
+		claims["sub"] = subject
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	token := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." + b64.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(token))
+
+	r, s, err := ecdsa.Sign(rand.Reader, v.key, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	sig := make([]byte, 64)
+	rb, sb := r.Bytes(), s.Bytes()
+	copy(sig[32-len(rb):32], rb)
+	copy(sig[64-len(sb):], sb)
+
+	return "vapid t=" + token + "." + b64.EncodeToString(sig) + ", k=" + v.PublicKey(), nil
+}
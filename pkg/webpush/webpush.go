@@ -0,0 +1,235 @@
+// Package webpush sends Web Push messages with encrypted payloads
+// (RFC 8030, RFC 8291) signed with VAPID (RFC 8292).
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// MaxPayloadSize is the largest payload every push service has to
+	// accept once it is encrypted, minus the encryption overhead
+	MaxPayloadSize = 4096 - recordOverhead
+
+	recordSize     = 4096
+	recordOverhead = 16 + 4 + 1 + 65 + 16 + 1
+)
+
+var (
+	// ErrGone means the subscription has expired or the user unsubscribed,
+	// it should be deleted
+	ErrGone = errors.New("Push subscription is gone")
+
+	ErrPayloadTooLarge = errors.New("Push payload is too large")
+	ErrInvalidKeys     = errors.New("Invalid push subscription keys")
+	ErrInvalidEndpoint = errors.New("Invalid push subscription endpoint")
+)
+
+// Subscription is what the browser gets from PushManager.subscribe
+type Subscription struct {
+	Endpoint string
+	// P256dh and Auth are unpadded base64url, padded base64url works too
+	P256dh string
+	Auth   string
+}
+
+// Validate checks that the keys of the subscription can be used and that
+// the endpoint is https
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return ErrInvalidEndpoint
+	}
+
+	uaPublic, err := decodeKey(s.P256dh)
+	if err != nil {
+		return err
+	}
+	authSecret, err := decodeKey(s.Auth)
+	if err != nil {
+		return err
+	}
+	if x, _ := elliptic.Unmarshal(elliptic.P256(), uaPublic); x == nil || len(authSecret) != 16 {
+		return ErrInvalidKeys
+	}
+
+	return nil
+}
+
+type Options struct {
+	// TTL is how long the push service keeps the message if the browser
+	// is offline, 0 means it only gets delivered right away
+	TTL     time.Duration
+	Urgency string
+	// Topic replaces messages with the same topic that are still waiting
+	Topic string
+}
+
+// Client sends pushes
+type Client struct {
+	HTTP    *http.Client
+	VAPID   *VAPID
+	Subject string
+}
+
+// Send encrypts payload for the subscription and posts it to its endpoint
+func (c *Client) Send(sub *Subscription, payload []byte, opts Options) error {
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	auth, err := c.VAPID.Authorization(sub.Endpoint, c.Subject, time.Now().Add(12*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(opts.TTL/time.Second)))
+	if opts.Urgency != "" {
+		req.Header.Set("Urgency", opts.Urgency)
+	}
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("push to %s failed: %s", req.URL.Host, resp.Status)
+	}
+
+	return nil
+}
+
+// Encrypt encrypts payload for the subscription as a single aes128gcm
+// record, the result is the body of the push request
+func Encrypt(sub *Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublic, err := decodeKey(sub.P256dh)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := decodeKey(sub.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, uaPublic)
+	if x == nil || len(authSecret) != 16 {
+		return nil, ErrInvalidKeys
+	}
+
+	asKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asKey.X, asKey.Y)
+
+	sx, _ := curve.ScalarMult(x, y, asKey.D.Bytes())
+	ecdhSecret := make([]byte, 32)
+	sb := sx.Bytes()
+	copy(ecdhSecret[32-len(sb):], sb)
+
+	salt := make([]byte, 16)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm, err := expand(ecdhSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := expand(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := expand(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payload is the last and only record, 0x02 marks that
+	plaintext := append(append([]byte{}, payload...), 2)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[16:], recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func expand(secret, salt, info []byte, n int) ([]byte, error) {
+	out := make([]byte, n)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out)
+	return out, err
+}
+
+func decodeKey(s string) ([]byte, error) {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	b, err := b64.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidKeys
+	}
+	return b, nil
+}
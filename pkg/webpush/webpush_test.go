@@ -0,0 +1,204 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// browser holds the keys a browser makes when it subscribes
+type browser struct {
+	key  *ecdsa.PrivateKey
+	auth []byte
+}
+
+func newBrowser(t *testing.T, endpoint string) (*browser, *Subscription) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	auth := make([]byte, 16)
+	rand.Read(auth)
+
+	return &browser{key: key, auth: auth}, &Subscription{
+		Endpoint: endpoint,
+		P256dh:   b64.EncodeToString(elliptic.Marshal(elliptic.P256(), key.X, key.Y)),
+		Auth:     b64.EncodeToString(auth),
+	}
+}
+
+// decrypt does what the browser does with the body of a push
+func (b *browser) decrypt(t *testing.T, body []byte) []byte {
+	require.True(t, len(body) > 21)
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	idlen := int(body[20])
+	asPublic := body[21 : 21+idlen]
+	ciphertext := body[21+idlen:]
+	require.True(t, len(ciphertext) <= int(rs))
+
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, asPublic)
+	require.NotNil(t, x)
+	sx, _ := curve.ScalarMult(x, y, b.key.D.Bytes())
+	ecdhSecret := make([]byte, 32)
+	sb := sx.Bytes()
+	copy(ecdhSecret[32-len(sb):], sb)
+
+	uaPublic := elliptic.Marshal(curve, b.key.X, b.key.Y)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm, err := expand(ecdhSecret, b.auth, keyInfo, 32)
+	require.Nil(t, err)
+	cek, err := expand(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	require.Nil(t, err)
+	nonce, err := expand(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	require.Nil(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.Nil(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.Nil(t, err)
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.Nil(t, err)
+	require.Equal(t, byte(2), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+// verifyVAPID checks the Authorization header the way a push service does
+func verifyVAPID(t *testing.T, header, audience string) map[string]interface{} {
+	require.True(t, strings.HasPrefix(header, "vapid "))
+
+	var token, key string
+	for _, part := range strings.Split(strings.TrimPrefix(header, "vapid "), ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "t=") {
+			token = part[2:]
+		} else if strings.HasPrefix(part, "k=") {
+			key = part[2:]
+		}
+	}
+
+	pub, err := b64.DecodeString(key)
+	require.Nil(t, err)
+	x, y := elliptic.Unmarshal(elliptic.P256(), pub)
+	require.NotNil(t, x)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	sig, err := b64.DecodeString(parts[2])
+	require.Nil(t, err)
+	require.Len(t, sig, 64)
+
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.True(t, ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		hash[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])))
+
+	payload, err := b64.DecodeString(parts[1])
+	require.Nil(t, err)
+	claims := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, audience, claims["aud"])
+	return claims
+}
+
+func TestSend(t *testing.T) {
+	vapid, err := GenerateVAPID()
+	require.Nil(t, err)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := &Client{VAPID: vapid, Subject: "mailto:admin@example.com"}
+	b, sub := newBrowser(t, srv.URL+"/push/1")
+
+	err = c.Send(sub, []byte(`{"hello":"world"}`), Options{TTL: time.Hour, Urgency: "high", Topic: "dm"})
+	require.Nil(t, err)
+
+	r := <-received
+	assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+	assert.Equal(t, "3600", r.Header.Get("TTL"))
+	assert.Equal(t, "high", r.Header.Get("Urgency"))
+	assert.Equal(t, "dm", r.Header.Get("Topic"))
+	assert.True(t, strings.HasSuffix(r.Header.Get("Authorization"), ", k="+vapid.PublicKey()))
+
+	claims := verifyVAPID(t, r.Header.Get("Authorization"), srv.URL)
+	assert.Equal(t, "mailto:admin@example.com", claims["sub"])
+
+	assert.Equal(t, `{"hello":"world"}`, string(b.decrypt(t, <-bodies)))
+
+	_, gone := newBrowser(t, srv.URL+"/gone")
+	assert.Equal(t, ErrGone, c.Send(gone, []byte("hi"), Options{}))
+}
+
+func TestEncrypt(t *testing.T) {
+	b, sub := newBrowser(t, "https://push.example.com")
+
+	payload := []byte(strings.Repeat("a", MaxPayloadSize))
+	body, err := Encrypt(sub, payload)
+	require.Nil(t, err)
+	assert.True(t, len(body) <= 4096)
+	assert.Equal(t, payload, b.decrypt(t, body))
+
+	_, err = Encrypt(sub, append(payload, 'a'))
+	assert.Equal(t, ErrPayloadTooLarge, err)
+
+	_, err = Encrypt(&Subscription{P256dh: "nope", Auth: sub.Auth}, []byte("hi"))
+	assert.Equal(t, ErrInvalidKeys, err)
+}
+
+func TestVAPIDKeys(t *testing.T) {
+	vapid, err := GenerateVAPID()
+	require.Nil(t, err)
+
+	parsed, err := ParseVAPID(vapid.PrivateKey())
+	require.Nil(t, err)
+	assert.Equal(t, vapid.PublicKey(), parsed.PublicKey())
+	assert.Len(t, vapid.PublicKey(), 87)
+
+	_, err = ParseVAPID("short")
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
+func TestValidate(t *testing.T) {
+	_, sub := newBrowser(t, "https://push.example.com/1")
+	assert.Nil(t, sub.Validate())
+
+	invalid := *sub
+	invalid.Endpoint = "http://push.example.com/1"
+	assert.Equal(t, ErrInvalidEndpoint, invalid.Validate())
+
+	invalid = *sub
+	invalid.Auth = b64.EncodeToString([]byte("short"))
+	assert.Equal(t, ErrInvalidKeys, invalid.Validate())
+
+	invalid = *sub
+	invalid.P256dh = b64.EncodeToString(make([]byte, 65))
+	assert.Equal(t, ErrInvalidKeys, invalid.Validate())
+}
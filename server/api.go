@@ -0,0 +1,391 @@
+package server
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/mailru/easyjson"
+)
+
+// The REST API is versioned so scripts and bots keep working when the
+// websocket protocol the web client uses changes
+const apiPrefix = "/api/v1/"
+
+const (
+	apiDefaultCount = 50
+	apiMaxCount     = 200
+	apiMaxBodySize  = 64 * 1024
+)
+
+// apiAuth lets requests in with an API token or a session cookie, the token
+// is nil for requests with a session cookie
+func (d *Dispatch) apiAuth(w http.ResponseWriter, r *http.Request) (*State, *storage.APIToken) {
+	if _, ok := bearerToken(r); ok {
+		return d.tokenAuth(r)
+	}
+	return d.handleAuth(w, r, false, false), nil
+}
+
+func (d *Dispatch) serveAPI(w http.ResponseWriter, r *http.Request, state *State, token *storage.APIToken) {
+	if !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		apiError(w, r, http.StatusNotFound, "Unknown API version")
+		return
+	}
+
+	// Browsers can not send JSON to another origin without asking first,
+	// requiring it keeps other sites from using the session cookie
+	if r.Method == http.MethodPost {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if contentType != "application/json" {
+			apiError(w, r, http.StatusUnsupportedMediaType, "The body has to be JSON")
+			return
+		}
+	}
+
+	params, err := apiParams(r)
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	// Session cookies can do anything, API tokens only what their scopes
+	// allow
+	authorized := func(scope string) bool {
+		if token != nil && !token.HasScopes(scope) {
+			apiError(w, r, http.StatusForbidden, "The API token is missing the "+scope+" scope")
+			return false
+		}
+		return true
+	}
+
+	switch {
+	case len(params) == 1 && params[0] == "networks":
+		switch r.Method {
+		case http.MethodGet:
+			if authorized(storage.ScopeReadLogs) {
+				apiNetworks(w, r, state)
+			}
+		case http.MethodPost:
+			if authorized(storage.ScopeManageNetworks) {
+				apiConnect(w, r, state)
+			}
+		default:
+			apiMethodNotAllowed(w, r, "GET, POST")
+		}
+
+	case len(params) == 3 && params[0] == "networks" && params[2] == "channels":
+		switch r.Method {
+		case http.MethodGet:
+			if authorized(storage.ScopeReadLogs) {
+				apiChannels(w, r, state, params[1])
+			}
+		case http.MethodPost:
+			if authorized(storage.ScopeManageNetworks) {
+				apiJoin(w, r, state, params[1])
+			}
+		default:
+			apiMethodNotAllowed(w, r, "GET, POST")
+		}
+
+	case len(params) == 4 && params[0] == "networks" && params[2] == "channels":
+		if r.Method != http.MethodDelete {
+			apiMethodNotAllowed(w, r, "DELETE")
+			return
+		}
+		if authorized(storage.ScopeManageNetworks) {
+			apiPart(w, r, state, params[1], params[3])
+		}
+
+	case len(params) == 4 && params[0] == "networks" && params[2] == "messages":
+		switch r.Method {
+		case http.MethodGet:
+			if authorized(storage.ScopeReadLogs) {
+				apiMessages(w, r, state, params[1], params[3])
+			}
+		case http.MethodPost:
+			if authorized(storage.ScopeSendMessages) {
+				apiSendMessage(w, r, state, params[1], params[3])
+			}
+		default:
+			apiMethodNotAllowed(w, r, "GET, POST")
+		}
+
+	case len(params) == 1 && params[0] == "search":
+		if r.Method != http.MethodGet {
+			apiMethodNotAllowed(w, r, "GET")
+			return
+		}
+		if authorized(storage.ScopeReadLogs) {
+			apiSearch(w, r, state)
+		}
+
+	default:
+		apiError(w, r, http.StatusNotFound, "Not found")
+	}
+}
+
+// apiParams splits the path after the API prefix into its unescaped
+// segments, channel names have to be escaped since they start with #
+func apiParams(r *http.Request) ([]string, error) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), apiPrefix), "/")
+	params := strings.Split(path, "/")
+
+	for i, param := range params {
+		p, err := url.PathUnescape(param)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = p
+	}
+
+	return params, nil
+}
+
+func apiError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	writeJSONStatus(w, r, code, Error{Message: message})
+}
+
+func apiMethodNotAllowed(w http.ResponseWriter, r *http.Request, allow string) {
+	w.Header().Set("Allow", allow)
+	apiError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+func readAPIBody(w http.ResponseWriter, r *http.Request, v easyjson.Unmarshaler) bool {
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxBodySize))
+	if err != nil {
+		apiError(w, r, http.StatusRequestEntityTooLarge, "The body is too large")
+		return false
+	}
+
+	err = easyjson.Unmarshal(b, v)
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, "Invalid JSON")
+		return false
+	}
+
+	return true
+}
+
+func apiNetworks(w http.ResponseWriter, r *http.Request, state *State) {
+	networks := state.publicNetworks()
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].ID < networks[j].ID
+	})
+
+	writeJSON(w, r, Networks{Networks: networks})
+}
+
+func apiConnect(w http.ResponseWriter, r *http.Request, state *State) {
+	var network storage.Network
+	if !readAPIBody(w, r, &network) {
+		return
+	}
+
+	if network.Host == "" {
+		apiError(w, r, http.StatusBadRequest, "A network needs a host")
+		return
+	}
+
+	err := state.connect(&network, requestIPBytes(r))
+	if err != nil {
+		log.Println(r.RemoteAddr, "[API] Refused to add server", network.Host+":", err)
+
+		code := http.StatusForbidden
+		if err == errInvalidNetworkID {
+			code = http.StatusBadRequest
+		} else if err == errNetworkIDInUse {
+			code = http.StatusConflict
+		}
+		apiError(w, r, code, err.Error())
+		return
+	}
+
+	log.Println(r.RemoteAddr, "[API] Add server", network.Host)
+
+	res := network.Copy()
+	res.ServerPassword = ""
+	res.Password = ""
+	res.Username = ""
+	res.Realname = ""
+
+	writeJSONStatus(w, r, http.StatusCreated, res)
+}
+
+func apiChannels(w http.ResponseWriter, r *http.Request, state *State, networkID string) {
+	network, ok := state.network(networkID)
+	if !ok {
+		apiError(w, r, http.StatusNotFound, "Unknown network")
+		return
+	}
+
+	channels := network.Channels()
+	if channels == nil {
+		channels = []*storage.Channel{}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+
+	writeJSON(w, r, Channels{Channels: channels})
+}
+
+func apiJoin(w http.ResponseWriter, r *http.Request, state *State, networkID string) {
+	var data Join
+	if !readAPIBody(w, r, &data) {
+		return
+	}
+
+	i, ok := state.client(networkID)
+	if !ok {
+		apiError(w, r, http.StatusNotFound, "Unknown network")
+		return
+	}
+
+	if len(data.Channels) == 0 {
+		apiError(w, r, http.StatusBadRequest, "No channels to join")
+		return
+	}
+
+	i.Join(data.Channels...)
+
+	// Joining finishes when the server replies, the channels show up in
+	// the channel list after that
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func apiPart(w http.ResponseWriter, r *http.Request, state *State, networkID, channel string) {
+	if _, ok := state.network(networkID); !ok {
+		apiError(w, r, http.StatusNotFound, "Unknown network")
+		return
+	}
+
+	state.part(networkID, []string{channel})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiMessages(w http.ResponseWriter, r *http.Request, state *State, networkID, target string) {
+	if _, ok := state.network(networkID); !ok {
+		apiError(w, r, http.StatusNotFound, "Unknown network")
+		return
+	}
+
+	count := apiDefaultCount
+	if c := r.URL.Query().Get("count"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 {
+			apiError(w, r, http.StatusBadRequest, "Invalid count")
+			return
+		}
+		if n < apiMaxCount {
+			count = n
+		} else {
+			count = apiMaxCount
+		}
+	}
+
+	res, err := state.messages(networkID, target, count, r.URL.Query().Get("before"))
+	if err != nil {
+		log.Println("[API]", err)
+		apiError(w, r, http.StatusInternalServerError, "Could not read messages")
+		return
+	}
+	if res.Messages == nil {
+		res.Messages = []storage.Message{}
+	}
+
+	writeJSON(w, r, res)
+}
+
+func apiSendMessage(w http.ResponseWriter, r *http.Request, state *State, networkID, target string) {
+	var data Message
+	if !readAPIBody(w, r, &data) {
+		return
+	}
+
+	if data.Content == "" {
+		apiError(w, r, http.StatusBadRequest, "The message has no content")
+		return
+	}
+
+	id, ok := state.sendMessage(networkID, target, data.Content)
+	if !ok {
+		apiError(w, r, http.StatusNotFound, "Unknown network")
+		return
+	}
+
+	msg := Message{
+		ID:      id,
+		Network: networkID,
+		From:    state.nick(networkID),
+		To:      target,
+		Content: data.Content,
+	}
+
+	// The web client only shows the messages it sent itself right away,
+	// open tabs need to hear about this one
+	state.sendJSON("message", msg)
+
+	writeJSONStatus(w, r, http.StatusCreated, msg)
+}
+
+func apiSearch(w http.ResponseWriter, r *http.Request, state *State) {
+	q := r.URL.Query()
+	query := MessageSearch{
+		Network: q.Get("network"),
+		Channel: q.Get("channel"),
+		From:    q.Get("from"),
+		Nick:    q.Get("nick"),
+		Event:   q.Get("event"),
+		Q:       q.Get("q"),
+	}
+
+	var err error
+	query.Start, err = queryInt64(q, "start")
+	if err == nil {
+		query.End, err = queryInt64(q, "end")
+	}
+	if err == nil {
+		var n int64
+		n, err = queryInt64(q, "offset")
+		query.Offset = int(n)
+	}
+	if err == nil {
+		var n int64
+		n, err = queryInt64(q, "limit")
+		query.Limit = int(n)
+	}
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := state.search(query)
+	if res.Error != "" {
+		apiError(w, r, http.StatusInternalServerError, res.Error)
+		return
+	}
+
+	writeJSON(w, r, res)
+}
+
+func queryInt64(q url.Values, key string) (int64, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errors.New("Invalid " + key)
+	}
+	return n, nil
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI(t *testing.T) {
+	d := &Dispatch{cfg: &config.Config{NetworkAllowlist: true}}
+	s := NewState(user, d)
+
+	network := user.NewNetwork(&storage.Network{
+		ID:             "api.com",
+		Host:           "api.com",
+		Nick:           "nick",
+		ServerPassword: "serversecret",
+		Password:       "secret",
+	}, irc.NewClient(&irc.Config{Nick: "nick", Username: "user", Host: "api.com"}))
+	network.AddChannel(network.NewChannel("#api"))
+	s.networks["api.com"] = network
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		d.serveAPI(w, r, s, nil)
+		return w
+	}
+
+	res := request("GET", "/api/v1/networks", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	var networks Networks
+	require.Nil(t, networks.UnmarshalJSON(res.Body.Bytes()))
+	require.Len(t, networks.Networks, 1)
+	assert.Equal(t, "api.com", networks.Networks[0].ID)
+	assert.NotContains(t, res.Body.String(), "secret")
+
+	res = request("POST", "/api/v1/networks", `{"host":"not.allowed"}`)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	var e Error
+	require.Nil(t, e.UnmarshalJSON(res.Body.Bytes()))
+	assert.Equal(t, "Connecting to this network is not allowed", e.Message)
+
+	res = request("GET", "/api/v1/networks/api.com/channels", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	var channels Channels
+	require.Nil(t, channels.UnmarshalJSON(res.Body.Bytes()))
+	require.Len(t, channels.Channels, 1)
+	assert.Equal(t, "#api", channels.Channels[0].Name)
+
+	res = request("POST", "/api/v1/networks/api.com/channels", `{"channels":["#go"]}`)
+	assert.Equal(t, http.StatusAccepted, res.Code)
+	res = request("POST", "/api/v1/networks/nope/channels", `{"channels":["#go"]}`)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// Only JSON bodies are accepted
+	r := httptest.NewRequest("POST", "/api/v1/networks/api.com/messages/%23api", strings.NewReader("content=hi"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	d.serveAPI(w, r, s, nil)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	res = request("POST", "/api/v1/networks/api.com/messages/%23api", `{"content":"hello api"}`)
+	assert.Equal(t, http.StatusCreated, res.Code)
+	var msg Message
+	require.Nil(t, msg.UnmarshalJSON(res.Body.Bytes()))
+	assert.NotEmpty(t, msg.ID)
+	assert.Equal(t, "nick", msg.From)
+	assert.Equal(t, "#api", msg.To)
+
+	broadcast := <-s.broadcast
+	assert.Equal(t, "message", broadcast.Type)
+	assert.Equal(t, msg, broadcast.Data)
+
+	assert.Eventually(t, func() bool {
+		messages, _, err := user.LastMessages("api.com", "#api", 10)
+		return err == nil && len(messages) == 1 && messages[0].ID == msg.ID
+	}, time.Second, 10*time.Millisecond)
+
+	res = request("GET", "/api/v1/networks/api.com/messages/%23api?count=10", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	var messages Messages
+	require.Nil(t, messages.UnmarshalJSON(res.Body.Bytes()))
+	require.Len(t, messages.Messages, 1)
+	assert.Equal(t, "hello api", messages.Messages[0].Content)
+
+	res = request("GET", "/api/v1/networks/api.com/messages/%23api?count=nope", "")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = request("GET", "/api/v1/networks/api.com/messages/%23empty", "")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"messages":[]`)
+
+	res = request("GET", "/api/v1/networks/nope/messages/%23api", "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	res = request("DELETE", "/api/v1/networks/api.com/channels/%23api", "")
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Eventually(t, func() bool {
+		return network.Channel("#api") == nil
+	}, time.Second, 10*time.Millisecond)
+
+	res = request("PUT", "/api/v1/networks", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, POST", res.Header().Get("Allow"))
+
+	res = request("GET", "/api/v2/networks", "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	res = request("GET", "/api/v1/nope", "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestAPIConnect(t *testing.T) {
+	// The networks get saved, they go in a store of their own
+	storage.InMemory = true
+	defer func() { storage.InMemory = false }()
+
+	u, err := storage.NewUser(memory.New())
+	require.Nil(t, err)
+
+	d := &Dispatch{cfg: &config.Config{}}
+	s := NewState(u, d)
+	defer s.kill()
+
+	r := httptest.NewRequest("POST", "/api/v1/networks", strings.NewReader(
+		`{"id":"api","host":"api.test","serverPassword":"serversecret","password":"secret"}`))
+	r.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	d.serveAPI(res, r, s, nil)
+	<-s.reset
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Contains(t, res.Body.String(), `"id":"api"`)
+	assert.NotContains(t, res.Body.String(), "secret")
+
+	network, ok := s.network("api")
+	require.True(t, ok)
+	assert.Equal(t, "serversecret", network.ServerPassword)
+}
+
+func TestAPIToken(t *testing.T) {
+	d := &Dispatch{}
+	s := NewState(user, d)
+	d.states = &stateStore{states: map[uint64]*State{user.ID: s}}
+
+	network := user.NewNetwork(&storage.Network{ID: "token.com", Host: "token.com"},
+		irc.NewClient(&irc.Config{Nick: "bot", Username: "bot", Host: "token.com"}))
+	s.networks["token.com"] = network
+
+	stored, token, err := user.AddAPIToken("ci", []string{storage.ScopeSendMessages})
+	require.Nil(t, err)
+	defer user.RemoveAPIToken(stored.ID)
+
+	fullToken, full, err := user.AddAPIToken("full", storage.APIScopes)
+	require.Nil(t, err)
+	defer user.RemoveAPIToken(fullToken.ID)
+
+	request := func(method, path, token, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, r)
+		return w
+	}
+
+	res := request("POST", "/api/v1/networks/token.com/messages/%23ci", token, `{"content":"build passed"}`)
+	assert.Equal(t, http.StatusCreated, res.Code)
+	<-s.broadcast
+
+	// The token can only send messages
+	res = request("GET", "/api/v1/networks/token.com/messages/%23ci", token, "")
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	res = request("POST", "/api/v1/networks/token.com/channels", token, `{"channels":["#ci"]}`)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = request("GET", "/api/v1/networks", token+"x", "")
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	res = request("GET", "/api/v1/networks", "", "")
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	// The websocket and the other endpoints need every scope
+	r := httptest.NewRequest("GET", "/ws", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	assert.Nil(t, d.handleAuth(httptest.NewRecorder(), r, false, false))
+	r.Header.Set("Authorization", "Bearer "+full)
+	assert.Equal(t, s, d.handleAuth(httptest.NewRecorder(), r, false, false))
+
+	require.Nil(t, user.RemoveAPIToken(stored.ID))
+	res = request("POST", "/api/v1/networks/token.com/messages/%23ci", token, `{"content":"build failed"}`)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestRequestIPBytes(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, []byte{10, 0, 0, 1}, requestIPBytes(r))
+
+	r.RemoteAddr = "[2001:db8::1]:1234"
+	assert.Equal(t, []byte(net.ParseIP("2001:db8::1")), requestIPBytes(r))
+
+	r.Header.Set("X-Forwarded-For", "10.0.0.2")
+	assert.Equal(t, []byte{10, 0, 0, 2}, requestIPBytes(r))
+
+	r.Header.Del("X-Forwarded-For")
+	r.RemoteAddr = "nope"
+	assert.Nil(t, requestIPBytes(r))
+}
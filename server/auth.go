@@ -3,13 +3,25 @@ package server
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/khlieng/dispatch/pkg/cookie"
 	"github.com/khlieng/dispatch/pkg/session"
 	"github.com/khlieng/dispatch/storage"
 )
 
+// handleAuth returns the state of the user the request is from. Requests
+// with an API token only get through if the token has every scope, the
+// websocket and the endpoints that use this give full access to the user.
 func (d *Dispatch) handleAuth(w http.ResponseWriter, r *http.Request, createUser, refresh bool) *State {
+	if _, ok := bearerToken(r); ok {
+		state, token := d.tokenAuth(r)
+		if token == nil || !token.HasScopes(storage.APIScopes...) {
+			return nil
+		}
+		return state
+	}
+
 	var state *State
 
 	cookie, err := r.Cookie(cookie.Name(r, session.CookieName))
@@ -46,7 +58,7 @@ func (d *Dispatch) handleAuth(w http.ResponseWriter, r *http.Request, createUser
 		}
 
 		if state != nil {
-			log.Println(r.RemoteAddr, "[Auth] GET", r.URL.Path, "| Valid token | User ID:", state.user.ID)
+			log.Println(r.RemoteAddr, "[Auth]", r.Method, r.URL.Path, "| Valid token | User ID:", state.user.ID)
 		} else if createUser {
 			state, err = d.newUser(w, r)
 			if err != nil {
@@ -58,6 +70,46 @@ func (d *Dispatch) handleAuth(w http.ResponseWriter, r *http.Request, createUser
 	return state
 }
 
+// tokenAuth returns the state of the user that owns the API token in the
+// Authorization header along with the stored token, both are nil if the
+// token is not valid
+func (d *Dispatch) tokenAuth(r *http.Request) (*State, *storage.APIToken) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, nil
+	}
+
+	userID, ok := session.ParseToken(token)
+	if !ok {
+		return nil, nil
+	}
+
+	state := d.states.get(userID)
+	if state == nil {
+		return nil, nil
+	}
+
+	t, err := state.user.APIToken(token)
+	if err != nil && err != storage.ErrNotFound {
+		log.Println("[Auth]", err)
+	}
+	if t == nil {
+		return nil, nil
+	}
+
+	log.Println(r.RemoteAddr, "[Auth]", r.Method, r.URL.Path, "| Valid API token", t.Name, "| User ID:", userID)
+
+	return state, t
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return auth[7:], true
+	}
+	return "", false
+}
+
 func (d *Dispatch) newUser(w http.ResponseWriter, r *http.Request) (*State, error) {
 	user, err := storage.NewUser(d.Store)
 	if err != nil {
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/khlieng/dispatch/export"
+)
+
+// serveExport sends the message logs of the user as a file download, the
+// query takes the format, network, channel, from and to options of the
+// export command, times are in UTC
+func (d *Dispatch) serveExport(w http.ResponseWriter, r *http.Request, state *State) {
+	q := r.URL.Query()
+
+	opts := export.Options{
+		Format:  q.Get("format"),
+		Network: q.Get("network"),
+		Channel: q.Get("channel"),
+	}
+	if opts.Format == "" {
+		opts.Format = export.FormatText
+	}
+
+	var err error
+	opts.Start, err = export.ParseTime(q.Get("from"), false, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.End, err = export.ParseTime(q.Get("to"), true, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !export.ValidFormat(opts.Format) {
+		http.Error(w, "unknown export format: "+opts.Format, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(opts.Format))
+	w.Header().Set("Content-Disposition", "attachment; filename=dispatch-export."+export.Extension(opts.Format))
+
+	err = export.Export(w, state.user.ForEachMessage, opts)
+	if err != nil {
+		log.Println("[Export]", err)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// nick returns the current nick of the user on network
+func (s *State) nick(network string) string {
+	if i, ok := s.client(network); ok {
+		return i.GetNick()
+	}
+	return ""
+}
+
+// highlights returns the IDs of the messages that highlight the user, the
+// current highlight rules and nick get used
+func (s *State) highlights(network string, messages []storage.Message) []string {
+	nick := s.nick(network)
+
+	var ids []string
+	for i := range messages {
+		if messages[i].Content != "" && s.user.IsHighlight(nick, &messages[i]) {
+			ids = append(ids, messages[i].ID)
+		}
+	}
+	return ids
+}
+
+// logMessage logs a message and stores it as a mention if it highlighted
+// the user
+func (s *State) logMessage(msg *storage.Message, highlight bool) {
+	err := s.user.LogMessage(msg)
+	if err != nil {
+		log.Println("[Messages]", err)
+		return
+	}
+
+	if highlight {
+		err = s.user.AddMention(msg)
+		if err != nil {
+			log.Println("[Highlight]", err)
+		}
+	}
+}
+
+// serveMentions lists the mentions of the user that have not been read,
+// all=true lists the ones that have been read as well
+func (d *Dispatch) serveMentions(w http.ResponseWriter, r *http.Request, state *State) {
+	mentions, err := state.user.Mentions(r.URL.Query().Get("all") != "true")
+	if err != nil {
+		log.Println("[Highlight]", err)
+		fail(w, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, r, Mentions{Mentions: mentions})
+}
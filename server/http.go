@@ -9,6 +9,10 @@ import (
 )
 
 func writeJSON(w http.ResponseWriter, r *http.Request, data easyjson.Marshaler) {
+	writeJSONStatus(w, r, http.StatusOK, data)
+}
+
+func writeJSONStatus(w http.ResponseWriter, r *http.Request, code int, data easyjson.Marshaler) {
 	json, err := easyjson.Marshal(data)
 	if err != nil {
 		log.Println(err)
@@ -20,11 +24,13 @@ func writeJSON(w http.ResponseWriter, r *http.Request, data easyjson.Marshaler)
 
 	if len(json) > 1400 && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(code)
 
 		gzw := getGzipWriter(w)
 		gzw.Write(json)
 		putGzipWriter(gzw)
 	} else {
+		w.WriteHeader(code)
 		w.Write(json)
 	}
 }
@@ -15,6 +15,11 @@ type connectDefaults struct {
 	ServerPassword bool
 }
 
+type connectPreset struct {
+	*config.Preset
+	ServerPassword bool
+}
+
 type dispatchVersion struct {
 	Tag    string
 	Commit string
@@ -22,15 +27,30 @@ type dispatchVersion struct {
 }
 
 type indexData struct {
-	Defaults connectDefaults
-	Networks []*storage.Network
-	Channels []*storage.Channel
-	OpenDMs  []storage.Tab
-	HexIP    bool
-	Version  dispatchVersion
+	Defaults         connectDefaults
+	Presets          []connectPreset
+	NetworkAllowlist bool
+	Networks         []*storage.Network
+	Channels         []*storage.Channel
+	OpenDMs          []storage.Tab
+	HexIP            bool
+	Version          dispatchVersion
 
 	Settings *storage.ClientSettings
 
+	Highlights *storage.HighlightRules
+	Ignores    []*storage.IgnoreRule
+	APITokens  []*storage.APIToken
+	// UnreadMentions is how many mentions are waiting in the inbox
+	UnreadMentions int
+	ReadMarkers    []*storage.ReadMarker
+	// Unread has the unread message and highlight counts of every channel
+	// and private chat
+	Unread []storage.UnreadCount
+	// VAPIDPublicKey is what browsers subscribe to pushes with, it is empty
+	// when push is turned off
+	VAPIDPublicKey string
+
 	// Users in the selected channel
 	Users *Userlist
 
@@ -46,7 +66,8 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 			Defaults:       &cfg.Defaults,
 			ServerPassword: cfg.Defaults.ServerPassword != "",
 		},
-		HexIP: cfg.HexIP,
+		NetworkAllowlist: cfg.NetworkAllowlist,
+		HexIP:            cfg.HexIP,
 		Version: dispatchVersion{
 			Tag:    version.Tag,
 			Commit: version.Commit,
@@ -54,6 +75,14 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 		},
 	}
 
+	presets := cfg.Presets()
+	for i := range presets {
+		data.Presets = append(data.Presets, connectPreset{
+			Preset:         &presets[i],
+			ServerPassword: presets[i].ServerPassword != "",
+		})
+	}
+
 	if state == nil {
 		data.Settings = storage.DefaultClientSettings()
 		return &data
@@ -61,23 +90,32 @@ func (d *Dispatch) getIndexData(r *http.Request, state *State) *indexData {
 
 	data.Settings = state.user.ClientSettings()
 
-	state.lock.Lock()
-	for _, network := range state.networks {
-		network = network.Copy()
-		network.Password = ""
-		network.Username = ""
-		network.Realname = ""
+	if rules, err := state.user.HighlightRules(); err == nil {
+		data.Highlights = rules
+	}
+	if rules, err := state.user.IgnoreRules(); err == nil {
+		data.Ignores = rules
+	}
+	if tokens, err := state.user.APITokens(); err == nil {
+		data.APITokens = tokens
+	}
+	if mentions, err := state.user.Mentions(true); err == nil {
+		data.UnreadMentions = len(mentions)
+	}
+	data.VAPIDPublicKey = d.vapidPublicKey()
 
+	for _, network := range state.publicNetworks() {
 		data.Networks = append(data.Networks, network)
 		data.Channels = append(data.Channels, network.Channels()...)
 	}
-	state.lock.Unlock()
 
 	openDMs, err := state.user.OpenDMs()
 	if err == nil {
 		data.OpenDMs = openDMs
 	}
 
+	data.addUnread(state)
+
 	tab, err := tabFromRequest(r)
 	if err == nil && hasTab(data.Channels, openDMs, tab.Network, tab.Name) {
 		data.addUsersAndMessages(tab.Network, tab.Name, state)
@@ -100,9 +138,11 @@ func (d *indexData) addUsersAndMessages(network, name string, state *State) {
 	messages, hasMore, err := state.user.LastMessages(network, name, 50)
 	if err == nil && len(messages) > 0 {
 		m := Messages{
-			Network:  network,
-			To:       name,
-			Messages: messages,
+			Network:    network,
+			To:         name,
+			Messages:   messages,
+			LinkMeta:   state.storedLinkMeta(messages),
+			Highlights: state.highlights(network, messages),
 		}
 
 		if hasMore {
@@ -113,6 +153,23 @@ func (d *indexData) addUsersAndMessages(network, name string, state *State) {
 	}
 }
 
+func (d *indexData) addUnread(state *State) {
+	markers, err := state.user.ReadMarkers()
+	if err != nil {
+		return
+	}
+	d.ReadMarkers = markers
+
+	tabs := append([]storage.Tab{}, d.OpenDMs...)
+	for _, ch := range d.Channels {
+		tabs = append(tabs, storage.Tab{Network: ch.Network, Name: ch.Name})
+	}
+
+	if unread, err := state.user.UnreadCounts(tabs); err == nil {
+		d.Unread = unread
+	}
+}
+
 func hasTab(channels []*storage.Channel, openDMs []storage.Tab, network, name string) bool {
 	if name != "" {
 		for _, ch := range channels {
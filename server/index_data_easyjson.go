@@ -39,9 +39,32 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 		}
 		switch key {
 		case "defaults":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Defaults).UnmarshalJSON(data))
+			(out.Defaults).UnmarshalEasyJSON(in)
+		case "presets":
+			if in.IsNull() {
+				in.Skip()
+				out.Presets = nil
+			} else {
+				in.Delim('[')
+				if out.Presets == nil {
+					if !in.IsDelim(']') {
+						out.Presets = make([]connectPreset, 0, 4)
+					} else {
+						out.Presets = []connectPreset{}
+					}
+				} else {
+					out.Presets = (out.Presets)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 connectPreset
+					(v1).UnmarshalEasyJSON(in)
+					out.Presets = append(out.Presets, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
+		case "networkAllowlist":
+			out.NetworkAllowlist = bool(in.Bool())
 		case "networks":
 			if in.IsNull() {
 				in.Skip()
@@ -58,19 +81,17 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 					out.Networks = (out.Networks)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 *storage.Network
+					var v2 *storage.Network
 					if in.IsNull() {
 						in.Skip()
-						v1 = nil
+						v2 = nil
 					} else {
-						if v1 == nil {
-							v1 = new(storage.Network)
-						}
-						if data := in.Raw(); in.Ok() {
-							in.AddError((*v1).UnmarshalJSON(data))
+						if v2 == nil {
+							v2 = new(storage.Network)
 						}
+						(*v2).UnmarshalEasyJSON(in)
 					}
-					out.Networks = append(out.Networks, v1)
+					out.Networks = append(out.Networks, v2)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -91,19 +112,17 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 					out.Channels = (out.Channels)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v2 *storage.Channel
+					var v3 *storage.Channel
 					if in.IsNull() {
 						in.Skip()
-						v2 = nil
+						v3 = nil
 					} else {
-						if v2 == nil {
-							v2 = new(storage.Channel)
-						}
-						if data := in.Raw(); in.Ok() {
-							in.AddError((*v2).UnmarshalJSON(data))
+						if v3 == nil {
+							v3 = new(storage.Channel)
 						}
+						(*v3).UnmarshalEasyJSON(in)
 					}
-					out.Channels = append(out.Channels, v2)
+					out.Channels = append(out.Channels, v3)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -124,9 +143,9 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 					out.OpenDMs = (out.OpenDMs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v3 storage.Tab
-					easyjson7e607aefDecodeGithubComKhliengDispatchStorage(in, &v3)
-					out.OpenDMs = append(out.OpenDMs, v3)
+					var v4 storage.Tab
+					easyjson7e607aefDecodeGithubComKhliengDispatchStorage(in, &v4)
+					out.OpenDMs = append(out.OpenDMs, v4)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -134,9 +153,7 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 		case "hexIP":
 			out.HexIP = bool(in.Bool())
 		case "version":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Version).UnmarshalJSON(data))
-			}
+			(out.Version).UnmarshalEasyJSON(in)
 		case "settings":
 			if in.IsNull() {
 				in.Skip()
@@ -145,10 +162,138 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 				if out.Settings == nil {
 					out.Settings = new(storage.ClientSettings)
 				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.Settings).UnmarshalJSON(data))
+				(*out.Settings).UnmarshalEasyJSON(in)
+			}
+		case "highlights":
+			if in.IsNull() {
+				in.Skip()
+				out.Highlights = nil
+			} else {
+				if out.Highlights == nil {
+					out.Highlights = new(storage.HighlightRules)
+				}
+				easyjson7e607aefDecodeGithubComKhliengDispatchStorage1(in, out.Highlights)
+			}
+		case "ignores":
+			if in.IsNull() {
+				in.Skip()
+				out.Ignores = nil
+			} else {
+				in.Delim('[')
+				if out.Ignores == nil {
+					if !in.IsDelim(']') {
+						out.Ignores = make([]*storage.IgnoreRule, 0, 8)
+					} else {
+						out.Ignores = []*storage.IgnoreRule{}
+					}
+				} else {
+					out.Ignores = (out.Ignores)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 *storage.IgnoreRule
+					if in.IsNull() {
+						in.Skip()
+						v5 = nil
+					} else {
+						if v5 == nil {
+							v5 = new(storage.IgnoreRule)
+						}
+						easyjson7e607aefDecodeGithubComKhliengDispatchStorage2(in, v5)
+					}
+					out.Ignores = append(out.Ignores, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "apiTokens":
+			if in.IsNull() {
+				in.Skip()
+				out.APITokens = nil
+			} else {
+				in.Delim('[')
+				if out.APITokens == nil {
+					if !in.IsDelim(']') {
+						out.APITokens = make([]*storage.APIToken, 0, 8)
+					} else {
+						out.APITokens = []*storage.APIToken{}
+					}
+				} else {
+					out.APITokens = (out.APITokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 *storage.APIToken
+					if in.IsNull() {
+						in.Skip()
+						v6 = nil
+					} else {
+						if v6 == nil {
+							v6 = new(storage.APIToken)
+						}
+						easyjson7e607aefDecodeGithubComKhliengDispatchStorage3(in, v6)
+					}
+					out.APITokens = append(out.APITokens, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "unreadMentions":
+			out.UnreadMentions = int(in.Int())
+		case "readMarkers":
+			if in.IsNull() {
+				in.Skip()
+				out.ReadMarkers = nil
+			} else {
+				in.Delim('[')
+				if out.ReadMarkers == nil {
+					if !in.IsDelim(']') {
+						out.ReadMarkers = make([]*storage.ReadMarker, 0, 8)
+					} else {
+						out.ReadMarkers = []*storage.ReadMarker{}
+					}
+				} else {
+					out.ReadMarkers = (out.ReadMarkers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 *storage.ReadMarker
+					if in.IsNull() {
+						in.Skip()
+						v7 = nil
+					} else {
+						if v7 == nil {
+							v7 = new(storage.ReadMarker)
+						}
+						easyjson7e607aefDecodeGithubComKhliengDispatchStorage4(in, v7)
+					}
+					out.ReadMarkers = append(out.ReadMarkers, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "unread":
+			if in.IsNull() {
+				in.Skip()
+				out.Unread = nil
+			} else {
+				in.Delim('[')
+				if out.Unread == nil {
+					if !in.IsDelim(']') {
+						out.Unread = make([]storage.UnreadCount, 0, 1)
+					} else {
+						out.Unread = []storage.UnreadCount{}
+					}
+				} else {
+					out.Unread = (out.Unread)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v8 storage.UnreadCount
+					easyjson7e607aefDecodeGithubComKhliengDispatchStorage5(in, &v8)
+					out.Unread = append(out.Unread, v8)
+					in.WantComma()
 				}
+				in.Delim(']')
 			}
+		case "vapidPublicKey":
+			out.VAPIDPublicKey = string(in.String())
 		case "users":
 			if in.IsNull() {
 				in.Skip()
@@ -157,9 +302,7 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 				if out.Users == nil {
 					out.Users = new(Userlist)
 				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.Users).UnmarshalJSON(data))
-				}
+				(*out.Users).UnmarshalEasyJSON(in)
 			}
 		case "messages":
 			if in.IsNull() {
@@ -169,9 +312,829 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 				if out.Messages == nil {
 					out.Messages = new(Messages)
 				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.Messages).UnmarshalJSON(data))
+				(*out.Messages).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, in indexData) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if true {
+		const prefix string = ",\"defaults\":"
+		first = false
+		out.RawString(prefix[1:])
+		(in.Defaults).MarshalEasyJSON(out)
+	}
+	if len(in.Presets) != 0 {
+		const prefix string = ",\"presets\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v9, v10 := range in.Presets {
+				if v9 > 0 {
+					out.RawByte(',')
+				}
+				(v10).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NetworkAllowlist {
+		const prefix string = ",\"networkAllowlist\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.NetworkAllowlist))
+	}
+	if len(in.Networks) != 0 {
+		const prefix string = ",\"networks\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.Networks {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				if v12 == nil {
+					out.RawString("null")
+				} else {
+					(*v12).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v13, v14 := range in.Channels {
+				if v13 > 0 {
+					out.RawByte(',')
+				}
+				if v14 == nil {
+					out.RawString("null")
+				} else {
+					(*v14).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.OpenDMs) != 0 {
+		const prefix string = ",\"openDMs\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v15, v16 := range in.OpenDMs {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				easyjson7e607aefEncodeGithubComKhliengDispatchStorage(out, v16)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.HexIP {
+		const prefix string = ",\"hexIP\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.HexIP))
+	}
+	if true {
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.Version).MarshalEasyJSON(out)
+	}
+	if in.Settings != nil {
+		const prefix string = ",\"settings\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Settings).MarshalEasyJSON(out)
+	}
+	if in.Highlights != nil {
+		const prefix string = ",\"highlights\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson7e607aefEncodeGithubComKhliengDispatchStorage1(out, *in.Highlights)
+	}
+	if len(in.Ignores) != 0 {
+		const prefix string = ",\"ignores\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v17, v18 := range in.Ignores {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				if v18 == nil {
+					out.RawString("null")
+				} else {
+					easyjson7e607aefEncodeGithubComKhliengDispatchStorage2(out, *v18)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.APITokens) != 0 {
+		const prefix string = ",\"apiTokens\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v19, v20 := range in.APITokens {
+				if v19 > 0 {
+					out.RawByte(',')
+				}
+				if v20 == nil {
+					out.RawString("null")
+				} else {
+					easyjson7e607aefEncodeGithubComKhliengDispatchStorage3(out, *v20)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.UnreadMentions != 0 {
+		const prefix string = ",\"unreadMentions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.UnreadMentions))
+	}
+	if len(in.ReadMarkers) != 0 {
+		const prefix string = ",\"readMarkers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v21, v22 := range in.ReadMarkers {
+				if v21 > 0 {
+					out.RawByte(',')
+				}
+				if v22 == nil {
+					out.RawString("null")
+				} else {
+					easyjson7e607aefEncodeGithubComKhliengDispatchStorage4(out, *v22)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Unread) != 0 {
+		const prefix string = ",\"unread\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v23, v24 := range in.Unread {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				easyjson7e607aefEncodeGithubComKhliengDispatchStorage5(out, v24)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.VAPIDPublicKey != "" {
+		const prefix string = ",\"vapidPublicKey\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.VAPIDPublicKey))
+	}
+	if in.Users != nil {
+		const prefix string = ",\"users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Users).MarshalEasyJSON(out)
+	}
+	if in.Messages != nil {
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Messages).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v indexData) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v indexData) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *indexData) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *indexData) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer(l, v)
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchStorage5(in *jlexer.Lexer, out *storage.UnreadCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "messages":
+			out.Messages = int(in.Int())
+		case "highlights":
+			out.Highlights = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchStorage5(out *jwriter.Writer, in storage.UnreadCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Messages != 0 {
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Messages))
+	}
+	if in.Highlights != 0 {
+		const prefix string = ",\"highlights\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Highlights))
+	}
+	out.RawByte('}')
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchStorage4(in *jlexer.Lexer, out *storage.ReadMarker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchStorage4(out *jwriter.Writer, in storage.ReadMarker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchStorage3(in *jlexer.Lexer, out *storage.APIToken) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 string
+					v25 = string(in.String())
+					out.Scopes = append(out.Scopes, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created":
+			out.Created = int64(in.Int64())
+		case "lastUsed":
+			out.LastUsed = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchStorage3(out *jwriter.Writer, in storage.APIToken) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if len(in.Scopes) != 0 {
+		const prefix string = ",\"scopes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v26, v27 := range in.Scopes {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v27))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Created != 0 {
+		const prefix string = ",\"created\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Created))
+	}
+	if in.LastUsed != 0 {
+		const prefix string = ",\"lastUsed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.LastUsed))
+	}
+	out.RawByte('}')
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *storage.IgnoreRule) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "mask":
+			out.Mask = string(in.String())
+		case "regex":
+			out.Regex = bool(in.Bool())
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "types":
+			if in.IsNull() {
+				in.Skip()
+				out.Types = nil
+			} else {
+				in.Delim('[')
+				if out.Types == nil {
+					if !in.IsDelim(']') {
+						out.Types = make([]string, 0, 4)
+					} else {
+						out.Types = []string{}
+					}
+				} else {
+					out.Types = (out.Types)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 string
+					v28 = string(in.String())
+					out.Types = append(out.Types, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in storage.IgnoreRule) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Mask != "" {
+		const prefix string = ",\"mask\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Mask))
+	}
+	if in.Regex {
+		const prefix string = ",\"regex\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Regex))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Types) != 0 {
+		const prefix string = ",\"types\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v29, v30 := range in.Types {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v30))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, out *storage.HighlightRules) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nick":
+			out.Nick = bool(in.Bool())
+		case "keywords":
+			if in.IsNull() {
+				in.Skip()
+				out.Keywords = nil
+			} else {
+				in.Delim('[')
+				if out.Keywords == nil {
+					if !in.IsDelim(']') {
+						out.Keywords = make([]string, 0, 4)
+					} else {
+						out.Keywords = []string{}
+					}
+				} else {
+					out.Keywords = (out.Keywords)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v31 string
+					v31 = string(in.String())
+					out.Keywords = append(out.Keywords, v31)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "patterns":
+			if in.IsNull() {
+				in.Skip()
+				out.Patterns = nil
+			} else {
+				in.Delim('[')
+				if out.Patterns == nil {
+					if !in.IsDelim(']') {
+						out.Patterns = make([]string, 0, 4)
+					} else {
+						out.Patterns = []string{}
+					}
+				} else {
+					out.Patterns = (out.Patterns)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v32 string
+					v32 = string(in.String())
+					out.Patterns = append(out.Patterns, v32)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "muted":
+			if in.IsNull() {
+				in.Skip()
+				out.Muted = nil
+			} else {
+				in.Delim('[')
+				if out.Muted == nil {
+					if !in.IsDelim(']') {
+						out.Muted = make([]storage.Tab, 0, 2)
+					} else {
+						out.Muted = []storage.Tab{}
+					}
+				} else {
+					out.Muted = (out.Muted)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v33 storage.Tab
+					easyjson7e607aefDecodeGithubComKhliengDispatchStorage(in, &v33)
+					out.Muted = append(out.Muted, v33)
+					in.WantComma()
 				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -183,18 +1146,18 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, in indexData) {
+func easyjson7e607aefEncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer, in storage.HighlightRules) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if true {
-		const prefix string = ",\"defaults\":"
+	if in.Nick {
+		const prefix string = ",\"nick\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.Raw((in.Defaults).MarshalJSON())
+		out.Bool(bool(in.Nick))
 	}
-	if len(in.Networks) != 0 {
-		const prefix string = ",\"networks\":"
+	if len(in.Keywords) != 0 {
+		const prefix string = ",\"keywords\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -203,21 +1166,17 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, i
 		}
 		{
 			out.RawByte('[')
-			for v4, v5 := range in.Networks {
-				if v4 > 0 {
+			for v34, v35 := range in.Keywords {
+				if v34 > 0 {
 					out.RawByte(',')
 				}
-				if v5 == nil {
-					out.RawString("null")
-				} else {
-					out.Raw((*v5).MarshalJSON())
-				}
+				out.String(string(v35))
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.Channels) != 0 {
-		const prefix string = ",\"channels\":"
+	if len(in.Patterns) != 0 {
+		const prefix string = ",\"patterns\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -226,21 +1185,17 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, i
 		}
 		{
 			out.RawByte('[')
-			for v6, v7 := range in.Channels {
-				if v6 > 0 {
+			for v36, v37 := range in.Patterns {
+				if v36 > 0 {
 					out.RawByte(',')
 				}
-				if v7 == nil {
-					out.RawString("null")
-				} else {
-					out.Raw((*v7).MarshalJSON())
-				}
+				out.String(string(v37))
 			}
 			out.RawByte(']')
 		}
 	}
-	if len(in.OpenDMs) != 0 {
-		const prefix string = ",\"openDMs\":"
+	if len(in.Muted) != 0 {
+		const prefix string = ",\"muted\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -249,91 +1204,17 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer(out *jwriter.Writer, i
 		}
 		{
 			out.RawByte('[')
-			for v8, v9 := range in.OpenDMs {
-				if v8 > 0 {
+			for v38, v39 := range in.Muted {
+				if v38 > 0 {
 					out.RawByte(',')
 				}
-				easyjson7e607aefEncodeGithubComKhliengDispatchStorage(out, v9)
+				easyjson7e607aefEncodeGithubComKhliengDispatchStorage(out, v39)
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.HexIP {
-		const prefix string = ",\"hexIP\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Bool(bool(in.HexIP))
-	}
-	if true {
-		const prefix string = ",\"version\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Raw((in.Version).MarshalJSON())
-	}
-	if in.Settings != nil {
-		const prefix string = ",\"settings\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Raw((*in.Settings).MarshalJSON())
-	}
-	if in.Users != nil {
-		const prefix string = ",\"users\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Raw((*in.Users).MarshalJSON())
-	}
-	if in.Messages != nil {
-		const prefix string = ",\"messages\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Raw((*in.Messages).MarshalJSON())
-	}
 	out.RawByte('}')
 }
-
-// MarshalJSON supports json.Marshaler interface
-func (v indexData) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson7e607aefEncodeGithubComKhliengDispatchServer(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v indexData) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson7e607aefEncodeGithubComKhliengDispatchServer(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *indexData) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson7e607aefDecodeGithubComKhliengDispatchServer(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *indexData) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson7e607aefDecodeGithubComKhliengDispatchServer(l, v)
-}
 func easyjson7e607aefDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out *storage.Tab) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -480,7 +1361,189 @@ func (v *dispatchVersion) UnmarshalJSON(data []byte) error {
 func (v *dispatchVersion) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson7e607aefDecodeGithubComKhliengDispatchServer1(l, v)
 }
-func easyjson7e607aefDecodeGithubComKhliengDispatchServer2(in *jlexer.Lexer, out *connectDefaults) {
+func easyjson7e607aefDecodeGithubComKhliengDispatchServer2(in *jlexer.Lexer, out *connectPreset) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	out.Preset = new(config.Preset)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "serverPassword":
+			out.ServerPassword = bool(in.Bool())
+		case "name":
+			out.Name = string(in.String())
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = string(in.String())
+		case "tls":
+			out.TLS = bool(in.Bool())
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+				out.Channels = nil
+			} else {
+				in.Delim('[')
+				if out.Channels == nil {
+					if !in.IsDelim(']') {
+						out.Channels = make([]string, 0, 4)
+					} else {
+						out.Channels = []string{}
+					}
+				} else {
+					out.Channels = (out.Channels)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 string
+					v40 = string(in.String())
+					out.Channels = append(out.Channels, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "requireSASL":
+			out.RequireSASL = bool(in.Bool())
+		case "readOnly":
+			out.ReadOnly = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7e607aefEncodeGithubComKhliengDispatchServer2(out *jwriter.Writer, in connectPreset) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ServerPassword {
+		const prefix string = ",\"serverPassword\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.ServerPassword))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
+	}
+	if in.Port != "" {
+		const prefix string = ",\"port\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Port))
+	}
+	if in.TLS {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.TLS))
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v41, v42 := range in.Channels {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v42))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.RequireSASL {
+		const prefix string = ",\"requireSASL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.RequireSASL))
+	}
+	if in.ReadOnly {
+		const prefix string = ",\"readOnly\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.ReadOnly))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v connectPreset) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v connectPreset) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *connectPreset) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *connectPreset) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer2(l, v)
+}
+func easyjson7e607aefDecodeGithubComKhliengDispatchServer3(in *jlexer.Lexer, out *connectDefaults) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -524,9 +1587,9 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer2(in *jlexer.Lexer, out
 					out.Channels = (out.Channels)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v10 string
-					v10 = string(in.String())
-					out.Channels = append(out.Channels, v10)
+					var v43 string
+					v43 = string(in.String())
+					out.Channels = append(out.Channels, v43)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -547,7 +1610,7 @@ func easyjson7e607aefDecodeGithubComKhliengDispatchServer2(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson7e607aefEncodeGithubComKhliengDispatchServer2(out *jwriter.Writer, in connectDefaults) {
+func easyjson7e607aefEncodeGithubComKhliengDispatchServer3(out *jwriter.Writer, in connectDefaults) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -597,11 +1660,11 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer2(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('[')
-			for v11, v12 := range in.Channels {
-				if v11 > 0 {
+			for v44, v45 := range in.Channels {
+				if v44 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v12))
+				out.String(string(v45))
 			}
 			out.RawByte(']')
 		}
@@ -642,23 +1705,23 @@ func easyjson7e607aefEncodeGithubComKhliengDispatchServer2(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v connectDefaults) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson7e607aefEncodeGithubComKhliengDispatchServer2(&w, v)
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v connectDefaults) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson7e607aefEncodeGithubComKhliengDispatchServer2(w, v)
+	easyjson7e607aefEncodeGithubComKhliengDispatchServer3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *connectDefaults) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson7e607aefDecodeGithubComKhliengDispatchServer2(&r, v)
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *connectDefaults) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson7e607aefDecodeGithubComKhliengDispatchServer2(l, v)
+	easyjson7e607aefDecodeGithubComKhliengDispatchServer3(l, v)
 }
@@ -1,13 +1,67 @@
 package server
 
-import "net"
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
 
 func addrToIPBytes(addr net.Addr) []byte {
-	ip := addr.(*net.TCPAddr).IP
+	return ipBytes(addr.(*net.TCPAddr).IP)
+}
+
+// requestIPBytes returns the IP of the client that sent r, X-Forwarded-For
+// is used when it is set like it is for websockets
+func requestIPBytes(r *http.Request) []byte {
+	if ip := net.ParseIP(r.Header.Get("X-Forwarded-For")); ip != nil {
+		return ipBytes(ip)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ipBytes(ip)
+	}
+	return nil
+}
 
+func ipBytes(ip net.IP) []byte {
 	if ipv4 := ip.To4(); ipv4 != nil {
 		return ipv4
 	}
 
 	return ip
 }
+
+// lookupHostname returns the forward-confirmed hostname of ip,
+// falling back to the IP itself
+func lookupHostname(ip net.IP) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	names, err := net.DefaultResolver.LookupAddr(ctx, ip.String())
+	if err != nil {
+		return ip.String()
+	}
+
+	for _, name := range names {
+		name = strings.TrimSuffix(name, ".")
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if addr.IP.Equal(ip) {
+				return name
+			}
+		}
+	}
+
+	return ip.String()
+}
@@ -7,25 +7,27 @@ import (
 	"log"
 	"net"
 	"strings"
+	"sync"
 
 	"github.com/eyedeekay/goSam"
+	"github.com/khlieng/dispatch/config"
 	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/storage"
 	"golang.org/x/net/proxy"
 )
 
-func createNickInUseHandler(i *irc.Client, state *State) func(string) string {
+func createNickInUseHandler(i *irc.Client, network string, state *State) func(string) string {
 	return func(nick string) string {
 		newNick := nick + "_"
 
 		if newNick == i.GetNick() {
 			state.sendJSON("nick_fail", NickFail{
-				Network: i.Host(),
+				Network: network,
 			})
 		}
 
 		state.sendJSON("error", IRCError{
-			Network: i.Host(),
+			Network: network,
 			Message: fmt.Sprintf("Nickname %s is unavailable, trying %s instead", nick, newNick),
 		})
 
@@ -33,13 +35,83 @@ func createNickInUseHandler(i *irc.Client, state *State) func(string) string {
 	}
 }
 
+func createIgnoreCTCPHandler(network string, state *State) func(*irc.Message) bool {
+	return func(msg *irc.Message) bool {
+		channel := msg.Sender
+		if len(msg.Params) > 0 && isChannel(msg.Params[0]) {
+			channel = msg.Params[0]
+		}
+
+		return state.user.IsIgnored(storage.IgnoreCTCP, network, channel, msg.Sender, msg.Ident, msg.Host)
+	}
+}
+
+func findWebIRC(webirc []config.WebIRC, host string) *config.WebIRC {
+	for i := range webirc {
+		if strings.EqualFold(webirc[i].Host, host) {
+			return &webirc[i]
+		}
+	}
+	return nil
+}
+
+// createWebIRCHandler returns the WEBIRC block for each server the client
+// connects to, the hostname of srcIP is looked up on the first connection
+// so it does not hold up whoever asked for the connection
+func createWebIRCHandler(webirc []config.WebIRC, srcIP []byte) func(irc.Server) *irc.WebIRC {
+	if len(webirc) == 0 || len(srcIP) == 0 {
+		return nil
+	}
+
+	ip := net.IP(srcIP)
+	var hostname string
+	var once sync.Once
+
+	return func(server irc.Server) *irc.WebIRC {
+		cfg := findWebIRC(webirc, server.Host)
+		if cfg == nil {
+			return nil
+		}
+
+		once.Do(func() {
+			hostname = lookupHostname(ip)
+		})
+
+		return &irc.WebIRC{
+			Password: cfg.Password,
+			Gateway:  cfg.Gateway,
+			Hostname: hostname,
+			IP:       ip.String(),
+		}
+	}
+}
+
+// createServerPasswordHandler returns the server password of the preset of
+// each server the client connects to, failing over to a server of another
+// preset sends the password of that one
+func createServerPasswordHandler(cfg *config.Config) func(irc.Server) string {
+	return func(server irc.Server) string {
+		if preset := cfg.Preset(server.Host); preset != nil {
+			return preset.ServerPassword
+		}
+		return ""
+	}
+}
+
+// connectIRC adds network to the state and connects to it, it returns nil
+// without connecting if the state already has a network with its ID
 func connectIRC(network *storage.Network, state *State, srcIP []byte) *irc.Client {
 	cfg := state.srv.Config()
 
 	ircCfg := network.IRCConfig()
 	ircCfg.AutoCTCP = cfg.AutoCTCP
 
-	if ircCfg.TLS {
+	useTLS := ircCfg.TLS
+	for _, server := range ircCfg.Servers {
+		useTLS = useTLS || server.TLS
+	}
+
+	if useTLS {
 		ircCfg.TLSConfig = &tls.Config{
 			InsecureSkipVerify: !cfg.VerifyCertificates,
 		}
@@ -53,48 +125,54 @@ func connectIRC(network *storage.Network, state *State, srcIP []byte) *irc.Clien
 		ircCfg.Username = hex.EncodeToString(srcIP)
 	}
 
-	if ircCfg.ServerPassword == "" &&
-		cfg.Defaults.ServerPassword != "" &&
-		ircCfg.Host == cfg.Defaults.Host {
-		ircCfg.ServerPassword = cfg.Defaults.ServerPassword
-	}
-
-	if cfg.Proxy.Enabled && strings.ToLower(cfg.Proxy.Protocol) == "socks5" {
-		addr := net.JoinHostPort(cfg.Proxy.Host, cfg.Proxy.Port)
-
-		var auth *proxy.Auth
-		if cfg.Proxy.Username != "" {
-			auth = &proxy.Auth{
-				User:     cfg.Proxy.Username,
-				Password: cfg.Proxy.Password,
-			}
-		}
+	ircCfg.WebIRC = createWebIRCHandler(cfg.WebIRC, srcIP)
 
-		dialer, err := proxy.SOCKS5("tcp", addr, auth, irc.DefaultDialer)
-		if err != nil {
-			log.Println(err)
-		} else {
-			ircCfg.Dialer = dialer
-		}
+	if ircCfg.ServerPassword == "" {
+		ircCfg.GetServerPassword = createServerPasswordHandler(cfg)
 	}
 
-	if cfg.Proxy.Enabled && strings.ToLower(cfg.Proxy.Protocol) == "i2p" {
-		addr := net.JoinHostPort(cfg.Proxy.Host, cfg.Proxy.Port)
-
-		client, err := goSam.NewClient(addr)
+	if cfg.Proxy.Enabled {
+		dialer, err := proxyDialer(cfg.Proxy)
 		if err != nil {
 			log.Println(err)
-		} else {
-			ircCfg.Dialer = client
+		} else if dialer != nil {
+			ircCfg.Dialer = dialer
 		}
 	}
 
 	i := irc.NewClient(ircCfg)
-	i.Config.HandleNickInUse = createNickInUseHandler(i, state)
+	i.Config.HandleNickInUse = createNickInUseHandler(i, network.ID, state)
+	i.Config.IgnoreCTCP = createIgnoreCTCPHandler(network.ID, state)
 
-	state.setNetwork(network.Host, state.user.NewNetwork(network, i))
+	if !state.addNetwork(network.ID, state.user.NewNetwork(network, i)) {
+		return nil
+	}
 	i.Connect()
-	go newIRCHandler(i, state).run()
+	go newIRCHandler(i, network.ID, state).run()
 
 	return i
 }
+
+// proxyDialer returns a dialer that connects through the configured proxy,
+// it returns nil if the protocol is not supported
+func proxyDialer(cfg config.Proxy) (irc.Dialer, error) {
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+
+	switch strings.ToLower(cfg.Protocol) {
+	case "socks5":
+		var auth *proxy.Auth
+		if cfg.Username != "" {
+			auth = &proxy.Auth{
+				User:     cfg.Username,
+				Password: cfg.Password,
+			}
+		}
+
+		return proxy.SOCKS5("tcp", addr, auth, irc.DefaultDialer)
+
+	case "i2p":
+		return goSam.NewClient(addr)
+	}
+
+	return nil, nil
+}
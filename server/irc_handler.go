@@ -24,8 +24,9 @@ var excludedErrors = []string{
 }
 
 type ircHandler struct {
-	client *irc.Client
-	state  *State
+	client  *irc.Client
+	network string
+	state   *State
 
 	whois       WhoisReply
 	motdBuffer  MOTD
@@ -35,9 +36,10 @@ type ircHandler struct {
 	handlers map[string]func(*irc.Message)
 }
 
-func newIRCHandler(client *irc.Client, state *State) *ircHandler {
+func newIRCHandler(client *irc.Client, network string, state *State) *ircHandler {
 	i := &ircHandler{
 		client:      client,
+		network:     network,
 		state:       state,
 		dccProgress: make(chan irc.DownloadProgress, 4),
 	}
@@ -54,7 +56,7 @@ func (i *ircHandler) run() {
 		select {
 		case msg, ok := <-i.client.Messages:
 			if !ok {
-				i.state.deleteNetwork(i.client.Host())
+				i.state.deleteNetwork(i.network)
 				return
 			}
 
@@ -70,9 +72,9 @@ func (i *ircHandler) run() {
 				}
 			}
 
-			i.state.sendJSON("connection_update", newConnectionUpdate(i.client.Host(), state))
+			i.state.sendJSON("connection_update", newConnectionUpdate(i.network, state))
 
-			if network, ok := i.state.network(i.client.Host()); ok {
+			if network, ok := i.state.network(i.network); ok {
 				var err string
 				if state.Error != nil {
 					err = state.Error.Error()
@@ -109,7 +111,7 @@ func (i *ircHandler) dispatchMessage(msg *irc.Message) {
 		len(msg.Params) > 1 &&
 		!isExcludedError(msg.Command) {
 		err := IRCError{
-			Network: i.client.Host(),
+			Network: i.network,
 			Message: strings.Join(msg.Params[1:], " "),
 		}
 
@@ -127,7 +129,7 @@ func (i *ircHandler) dispatchMessage(msg *irc.Message) {
 
 func (i *ircHandler) nick(msg *irc.Message) {
 	nick := Nick{
-		Network: i.client.Host(),
+		Network: i.network,
 		Old:     msg.Sender,
 		New:     msg.LastParam(),
 	}
@@ -146,12 +148,14 @@ func (i *ircHandler) nick(msg *irc.Message) {
 }
 
 func (i *ircHandler) join(msg *irc.Message) {
-	host := i.client.Host()
+	// Ignored joins still have to reach the userlist
+	ignored := i.ignored(storage.IgnoreJoins, msg.Params[0], msg)
 
 	i.state.sendJSON("join", Join{
-		Network:  host,
+		Network:  i.network,
 		User:     msg.Sender,
 		Channels: msg.Params,
+		Ignored:  ignored,
 	})
 
 	channel := msg.Params[0]
@@ -160,11 +164,11 @@ func (i *ircHandler) join(msg *irc.Message) {
 		// In case no topic is set and there's a cached one that needs to be cleared
 		i.client.Topic(channel)
 
-		if network, ok := i.state.network(host); ok {
+		if network, ok := i.state.network(i.network); ok {
 			if ch := network.Channel(channel); ch != nil {
 				ch.SetJoined(true)
 			} else {
-				i.state.sendLastMessages(host, channel, 50)
+				i.state.sendLastMessages(i.network, channel, 50)
 
 				ch = network.NewChannel(channel)
 				ch.SetJoined(true)
@@ -174,14 +178,17 @@ func (i *ircHandler) join(msg *irc.Message) {
 		}
 	}
 
-	go i.state.user.LogEvent(host, "join", []string{msg.Sender}, channel)
+	if !ignored {
+		go i.state.user.LogEvent(i.network, "join", []string{msg.Sender}, channel)
+	}
 }
 
 func (i *ircHandler) part(msg *irc.Message) {
 	part := Part{
-		Network: i.client.Host(),
+		Network: i.network,
 		User:    msg.Sender,
 		Channel: msg.Params[0],
+		Ignored: i.ignored(storage.IgnoreJoins, msg.Params[0], msg),
 	}
 
 	params := []string{part.User}
@@ -197,7 +204,9 @@ func (i *ircHandler) part(msg *irc.Message) {
 		go i.state.user.RemoveChannel(part.Network, part.Channel)
 	}
 
-	go i.state.user.LogEvent(part.Network, "part", params, part.Channel)
+	if !part.Ignored {
+		go i.state.user.LogEvent(part.Network, "part", params, part.Channel)
+	}
 }
 
 func (i *ircHandler) kick(msg *irc.Message) {
@@ -206,7 +215,7 @@ func (i *ircHandler) kick(msg *irc.Message) {
 	}
 
 	kick := Kick{
-		Network: i.client.Host(),
+		Network: i.network,
 		Channel: msg.Params[0],
 		Sender:  msg.Sender,
 		User:    msg.Params[1],
@@ -242,7 +251,9 @@ func (i *ircHandler) message(msg *irc.Message) {
 	if ctcp := msg.ToCTCP(); ctcp != nil {
 		if ctcp.Command == "DCC" && strings.HasPrefix(ctcp.Params, "SEND") {
 			if pack := i.client.ParseDCCSend(ctcp); pack != nil {
-				go i.receiveDCCSend(pack, msg)
+				if !i.ignored(storage.IgnoreDCC, msg.Sender, msg) {
+					go i.receiveDCCSend(pack, msg)
+				}
 				return
 			}
 		} else if ctcp.Command != "ACTION" {
@@ -252,52 +263,89 @@ func (i *ircHandler) message(msg *irc.Message) {
 
 	message := Message{
 		ID:      betterguid.New(),
-		Network: i.client.Host(),
+		Network: i.network,
 		From:    msg.Sender,
 		Content: msg.LastParam(),
 	}
 	target := msg.Params[0]
+	pm := i.client.Is(target)
+	if pm {
+		target = message.From
+	}
 
-	if i.client.Is(target) {
+	if i.ignored(storage.IgnoreMessages, target, msg) {
+		return
+	}
+
+	logged := target != "*" && !msg.IsFromServer()
+	stored := &storage.Message{
+		ID:      message.ID,
+		Network: message.Network,
+		From:    message.From,
+		To:      target,
+		Content: message.Content,
+	}
+	message.Highlight = logged && i.state.user.IsHighlight(i.client.GetNick(), stored)
+
+	if pm {
 		i.state.sendJSON("pm", message)
 
 		if !msg.IsFromServer() {
-			i.state.user.AddOpenDM(i.client.Host(), message.From)
+			i.state.user.AddOpenDM(i.network, message.From)
 		}
-
-		target = message.From
 	} else {
 		message.To = target
 		i.state.sendJSON("message", message)
 	}
 
-	if target != "*" && !msg.IsFromServer() {
-		go i.state.user.LogMessage(&storage.Message{
-			ID:      message.ID,
-			Network: message.Network,
-			From:    message.From,
-			To:      target,
-			Content: message.Content,
-		})
+	if logged {
+		go i.state.logMessage(stored, message.Highlight)
+
+		if pm {
+			if !i.state.user.IsMuted(i.network, message.From) {
+				i.state.sendPush("pm", stored)
+			}
+		} else if message.Highlight {
+			i.state.sendPush("highlight", stored)
+		}
+
+		i.state.sendLinkMeta(message.Network, target, message.ID, message.Content)
 	}
 }
 
 func (i *ircHandler) quit(msg *irc.Message) {
-	i.state.sendJSON("quit", Quit{
-		Network: i.client.Host(),
+	// The quit only gets shown and logged in the channels it is not ignored
+	// in, the userlists of all of them have to hear about it
+	var channels []string
+	quitChannels := irc.GetQuitChannels(msg)
+	for _, channel := range quitChannels {
+		if !i.ignored(storage.IgnoreJoins, channel, msg) {
+			channels = append(channels, channel)
+		}
+	}
+
+	quit := Quit{
+		Network: i.network,
 		User:    msg.Sender,
 		Reason:  msg.LastParam(),
-	})
+		Ignored: len(quitChannels) > 0 && len(channels) == 0 ||
+			len(quitChannels) == 0 && i.ignored(storage.IgnoreJoins, "", msg),
+	}
+	if !quit.Ignored && len(channels) < len(quitChannels) {
+		quit.Channels = channels
+	}
 
-	channels := irc.GetQuitChannels(msg)
+	i.state.sendJSON("quit", quit)
 
-	go i.state.user.LogEvent(i.client.Host(), "quit", []string{msg.Sender, msg.LastParam()}, channels...)
+	if !quit.Ignored {
+		go i.state.user.LogEvent(i.network, "quit", []string{msg.Sender, msg.LastParam()}, channels...)
+	}
 }
 
 func (i *ircHandler) info(msg *irc.Message) {
 	if msg.Command == irc.RPL_WELCOME {
 		i.state.sendJSON("nick", Nick{
-			Network: i.client.Host(),
+			Network: i.network,
 			New:     msg.Params[0],
 		})
 
@@ -311,15 +359,15 @@ func (i *ircHandler) info(msg *irc.Message) {
 			identd.Remove(i.client.LocalAddr(), i.client.RemoteAddr())
 		}
 
-		if network, ok := i.state.network(i.client.Host()); ok {
+		if network, ok := i.state.network(i.network); ok {
 			network.SetNick(msg.Params[0])
 		}
 
-		go i.state.user.SetNick(msg.Params[0], i.client.Host())
+		go i.state.user.SetNick(msg.Params[0], i.network)
 	}
 
 	i.state.sendJSON("pm", Message{
-		Network: i.client.Host(),
+		Network: i.network,
 		From:    msg.Sender,
 		Content: strings.Join(msg.Params[1:], " "),
 	})
@@ -329,11 +377,11 @@ func (i *ircHandler) features(msg *irc.Message) {
 	features := i.client.Features.Map()
 
 	i.state.sendJSON("features", Features{
-		Network:  i.client.Host(),
+		Network:  i.network,
 		Features: features,
 	})
 
-	if network, ok := i.state.network(i.client.Host()); ok {
+	if network, ok := i.state.network(i.network); ok {
 		network.SetFeatures(features)
 
 		if name := i.client.Features.String("NETWORK"); name != "" {
@@ -373,19 +421,19 @@ func (i *ircHandler) topic(msg *irc.Message) {
 		channel = msg.Params[0]
 		nick = msg.Sender
 
-		go i.state.user.LogEvent(i.client.Host(), "topic", []string{nick, msg.LastParam()}, channel)
+		go i.state.user.LogEvent(i.network, "topic", []string{nick, msg.LastParam()}, channel)
 	} else {
 		channel = msg.Params[1]
 	}
 
 	i.state.sendJSON("topic", Topic{
-		Network: i.client.Host(),
+		Network: i.network,
 		Channel: channel,
 		Topic:   msg.LastParam(),
 		Nick:    nick,
 	})
 
-	if network, ok := i.state.network(i.client.Host()); ok {
+	if network, ok := i.state.network(i.network); ok {
 		network.Channel(channel).SetTopic(msg.LastParam())
 	}
 }
@@ -394,11 +442,11 @@ func (i *ircHandler) noTopic(msg *irc.Message) {
 	channel := msg.Params[1]
 
 	i.state.sendJSON("topic", Topic{
-		Network: i.client.Host(),
+		Network: i.network,
 		Channel: channel,
 	})
 
-	if network, ok := i.state.network(i.client.Host()); ok {
+	if network, ok := i.state.network(i.network); ok {
 		network.Channel(channel).SetTopic("")
 	}
 
@@ -406,14 +454,14 @@ func (i *ircHandler) noTopic(msg *irc.Message) {
 
 func (i *ircHandler) namesEnd(msg *irc.Message) {
 	i.state.sendJSON("users", Userlist{
-		Network: i.client.Host(),
+		Network: i.network,
 		Channel: msg.Params[1],
 		Users:   irc.GetNamreplyUsers(msg),
 	})
 }
 
 func (i *ircHandler) motdStart(msg *irc.Message) {
-	i.motdBuffer.Network = i.client.Host()
+	i.motdBuffer.Network = i.network
 	i.motdBuffer.Title = msg.LastParam()
 }
 
@@ -427,7 +475,7 @@ func (i *ircHandler) motdEnd(msg *irc.Message) {
 }
 
 func (i *ircHandler) list(msg *irc.Message) {
-	if i.listBuffer == nil && i.state.Bool("update_chanlist_"+i.client.Host()) {
+	if i.listBuffer == nil && i.state.Bool("update_chanlist_"+i.network) {
 		i.listBuffer = storage.NewMapChannelListIndex()
 	}
 
@@ -443,7 +491,7 @@ func (i *ircHandler) list(msg *irc.Message) {
 
 func (i *ircHandler) listEnd(msg *irc.Message) {
 	if i.listBuffer != nil {
-		i.state.Set("update_chanlist_"+i.client.Host(), false)
+		i.state.Set("update_chanlist_"+i.network, false)
 
 		go func(idx storage.ChannelListIndex) {
 			idx.Finish()
@@ -456,14 +504,14 @@ func (i *ircHandler) listEnd(msg *irc.Message) {
 
 func (i *ircHandler) badNick(msg *irc.Message) {
 	i.state.sendJSON("nick_fail", NickFail{
-		Network: i.client.Host(),
+		Network: i.network,
 	})
 }
 
 func (i *ircHandler) forward(msg *irc.Message) {
 	if len(msg.Params) > 2 {
 		i.state.sendJSON("channel_forward", ChannelForward{
-			Network: i.client.Host(),
+			Network: i.network,
 			Old:     msg.Params[1],
 			New:     msg.Params[2],
 		})
@@ -472,16 +520,31 @@ func (i *ircHandler) forward(msg *irc.Message) {
 
 func (i *ircHandler) error(msg *irc.Message) {
 	i.state.sendJSON("error", IRCError{
-		Network: i.client.Host(),
+		Network: i.network,
 		Message: msg.LastParam(),
 	})
 }
 
+func (i *ircHandler) readMarker(msg *irc.Message) {
+	target, t, ok := irc.ParseReadMarker(msg)
+	if !ok || t.IsZero() {
+		return
+	}
+
+	go i.state.setReadMarker(&storage.ReadMarker{
+		Network: i.network,
+		Channel: target,
+		Time:    t.Unix(),
+	}, false)
+}
+
 func (i *ircHandler) receiveDCCSend(pack *irc.DCCSend, msg *irc.Message) {
 	cfg := i.state.srv.Config()
 
 	if cfg.DCC.Enabled {
-		if cfg.DCC.Autoget.Enabled {
+		// Downloads get passed straight through to the browser when
+		// nothing can be stored
+		if cfg.DCC.Autoget.Enabled && !storage.InMemory {
 			file, err := os.OpenFile(storage.Path.DownloadedFile(i.state.user.Username, pack.File), os.O_CREATE|os.O_WRONLY, 0644)
 			if err != nil {
 				return
@@ -493,7 +556,7 @@ func (i *ircHandler) receiveDCCSend(pack *irc.DCCSend, msg *irc.Message) {
 			i.state.setPendingDCC(pack.File, pack)
 
 			i.state.sendJSON("dcc_send", DCCSend{
-				Network:  i.client.Host(),
+				Network:  i.network,
 				From:     msg.Sender,
 				Filename: pack.File,
 				Size:     pack.Size(),
@@ -507,6 +570,15 @@ func (i *ircHandler) receiveDCCSend(pack *irc.DCCSend, msg *irc.Message) {
 	}
 }
 
+// ignored returns true if the user ignores messages of type t in channel
+// from the sender of msg, the user never ignores itself
+func (i *ircHandler) ignored(t, channel string, msg *irc.Message) bool {
+	if i.client.Is(msg.Sender) {
+		return false
+	}
+	return i.state.user.IsIgnored(t, i.network, channel, msg.Sender, msg.Ident, msg.Host)
+}
+
 func (i *ircHandler) initHandlers() {
 	i.handlers = map[string]func(*irc.Message){
 		irc.NICK:                 i.nick,
@@ -542,16 +614,17 @@ func (i *ircHandler) initHandlers() {
 		irc.RPL_LISTEND:          i.listEnd,
 		irc.ERR_ERRONEUSNICKNAME: i.badNick,
 		irc.ERR_FORWARD:          i.forward,
+		irc.MARKREAD:             i.readMarker,
 	}
 }
 
 func (i *ircHandler) log(v ...interface{}) {
-	log.Println("[IRC]", i.state.user.ID, i.client.Host(), fmt.Sprint(v...))
+	log.Println("[IRC]", i.state.user.ID, i.network, fmt.Sprint(v...))
 }
 
 func (i *ircHandler) sendDCCInfo(message string, log bool, a ...interface{}) {
 	msg := Message{
-		Network: i.client.Host(),
+		Network: i.network,
 		From:    "@dcc",
 		Content: fmt.Sprintf(message, a...),
 	}
@@ -5,12 +5,13 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/storage"
-	"github.com/khlieng/dispatch/storage/bleve"
-	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/memory"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var user *storage.User
@@ -23,16 +24,13 @@ func TestMain(m *testing.M) {
 
 	storage.Initialize(tempdir, "", "")
 
-	db, err := boltdb.New(storage.Path.Database())
-	if err != nil {
-		log.Fatal(err)
-	}
+	db := memory.New()
 
-	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
-		return db, nil
+	storage.GetMessageStore = func(user *storage.User) (storage.MessageStore, error) {
+		return db.MessageStore(user), nil
 	}
 	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
-		return bleve.New(storage.Path.Index(user.Username))
+		return db.MessageSearchProvider(user), nil
 	}
 
 	user, err = storage.NewUser(db)
@@ -58,7 +56,7 @@ func dispatchMessageMulti(msg *irc.Message) chan WSResponse {
 	})
 	s := NewState(user, &Dispatch{})
 
-	newIRCHandler(c, s).dispatchMessage(msg)
+	newIRCHandler(c, "host.com", s).dispatchMessage(msg)
 
 	return s.broadcast
 }
@@ -151,6 +149,151 @@ func TestHandleIRCMessage(t *testing.T) {
 	assert.Equal(t, "the message", msg.Content)
 }
 
+func TestHandleIRCHighlight(t *testing.T) {
+	res := dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "hey nick, look"},
+	})
+
+	msg, ok := res.Data.(Message)
+	require.True(t, ok)
+	assert.True(t, msg.Highlight)
+
+	assert.Eventually(t, func() bool {
+		mentions, err := user.Mentions(true)
+		return err == nil && len(mentions) == 1 && mentions[0].ID == msg.ID
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, user.MarkMentionsRead())
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#chan", "nickname"},
+	})
+
+	msg, ok = res.Data.(Message)
+	require.True(t, ok)
+	assert.False(t, msg.Highlight)
+
+	// Muted channels do not highlight
+	require.Nil(t, user.SetHighlightRules(&storage.HighlightRules{
+		Nick:  true,
+		Muted: []storage.Tab{{Network: "host.com", Name: "#muted"}},
+	}))
+	defer user.SetHighlightRules(storage.DefaultHighlightRules())
+
+	res = dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"#muted", "hey nick"},
+	})
+
+	msg, ok = res.Data.(Message)
+	require.True(t, ok)
+	assert.False(t, msg.Highlight)
+}
+
+func TestHandleIRCIgnore(t *testing.T) {
+	require.Nil(t, user.AddIgnoreRule(&storage.IgnoreRule{Mask: "*!*@troll.example.com"}))
+	require.Nil(t, user.AddIgnoreRule(&storage.IgnoreRule{
+		Nick:    "joiner",
+		Channel: "#chan",
+		Types:   []string{storage.IgnoreJoins},
+	}))
+	defer func() {
+		rules, _ := user.IgnoreRules()
+		for _, rule := range rules {
+			user.RemoveIgnoreRule(rule.ID)
+		}
+	}()
+
+	dropped := func(msg *irc.Message) bool {
+		select {
+		case <-dispatchMessageMulti(msg):
+			return false
+		default:
+			return true
+		}
+	}
+
+	assert.True(t, dropped(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "troll",
+		Ident:   "troll",
+		Host:    "troll.example.com",
+		Params:  []string{"#chan", "hi"},
+	}))
+	assert.True(t, dropped(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "troll",
+		Ident:   "troll",
+		Host:    "troll.example.com",
+		Params:  []string{"nick", "\x01DCC SEND file 1 1 1\x01"},
+	}))
+	assert.False(t, dropped(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "bob",
+		Ident:   "bob",
+		Host:    "example.com",
+		Params:  []string{"#chan", "hi"},
+	}))
+
+	// Ignored joins, parts and quits still reach the userlists
+	checkResponse(t, "join", Join{
+		Network:  "host.com",
+		User:     "joiner",
+		Channels: []string{"#chan"},
+		Ignored:  true,
+	}, dispatchMessage(&irc.Message{
+		Command: irc.JOIN,
+		Sender:  "joiner",
+		Params:  []string{"#chan"},
+	}))
+	checkResponse(t, "join", Join{
+		Network:  "host.com",
+		User:     "joiner",
+		Channels: []string{"#other"},
+	}, dispatchMessage(&irc.Message{
+		Command: irc.JOIN,
+		Sender:  "joiner",
+		Params:  []string{"#other"},
+	}))
+	checkResponse(t, "part", Part{
+		Network: "host.com",
+		User:    "joiner",
+		Channel: "#chan",
+		Reason:  "bye",
+		Ignored: true,
+	}, dispatchMessage(&irc.Message{
+		Command: irc.PART,
+		Sender:  "joiner",
+		Params:  []string{"#chan", "bye"},
+	}))
+	checkResponse(t, "quit", Quit{
+		Network: "host.com",
+		User:    "troll",
+		Reason:  "bye",
+		Ignored: true,
+	}, dispatchMessage(&irc.Message{
+		Command: irc.QUIT,
+		Sender:  "troll",
+		Ident:   "troll",
+		Host:    "troll.example.com",
+		Params:  []string{"bye"},
+	}))
+	assert.False(t, dropped(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "joiner",
+		Params:  []string{"#chan", "hi"},
+	}))
+
+	s := NewState(user, &Dispatch{})
+	ignoreCTCP := createIgnoreCTCPHandler("host.com", s)
+	assert.True(t, ignoreCTCP(&irc.Message{Sender: "troll", Host: "troll.example.com", Params: []string{"nick"}}))
+	assert.False(t, ignoreCTCP(&irc.Message{Sender: "bob", Host: "example.com", Params: []string{"nick"}}))
+}
+
 func TestHandleIRCQuit(t *testing.T) {
 	res := dispatchMessage(&irc.Message{
 		Command: irc.QUIT,
@@ -191,7 +334,7 @@ func TestHandleIRCWhois(t *testing.T) {
 		Host:     "host.com",
 	})
 	s := NewState(nil, nil)
-	i := newIRCHandler(c, s)
+	i := newIRCHandler(c, "host.com", s)
 
 	i.dispatchMessage(&irc.Message{
 		Command: irc.RPL_WHOISUSER,
@@ -262,7 +405,7 @@ func TestHandleIRCMotd(t *testing.T) {
 		Host:     "host.com",
 	})
 	s := NewState(nil, nil)
-	i := newIRCHandler(c, s)
+	i := newIRCHandler(c, "host.com", s)
 
 	i.dispatchMessage(&irc.Message{
 		Command: irc.RPL_MOTDSTART,
@@ -292,7 +435,7 @@ func TestHandleIRCBadNick(t *testing.T) {
 		Host:     "host.com",
 	})
 	s := NewState(nil, nil)
-	i := newIRCHandler(c, s)
+	i := newIRCHandler(c, "host.com", s)
 
 	i.dispatchMessage(&irc.Message{
 		Command: irc.ERR_ERRONEUSNICKNAME,
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebIRCHandler(t *testing.T) {
+	assert.Nil(t, createWebIRCHandler(nil, net.ParseIP("127.0.0.1")))
+
+	webirc := []config.WebIRC{
+		{Host: "irc.example.com", Gateway: "dispatch", Password: "first"},
+		{Host: "backup.example.com", Gateway: "dispatch", Password: "second"},
+	}
+	assert.Nil(t, createWebIRCHandler(webirc, nil))
+
+	handler := createWebIRCHandler(webirc, net.ParseIP("127.0.0.1"))
+	require.NotNil(t, handler)
+
+	// Every server gets its own WEBIRC block
+	block := handler(irc.Server{Host: "irc.example.com"})
+	require.NotNil(t, block)
+	assert.Equal(t, "first", block.Password)
+	assert.Equal(t, "127.0.0.1", block.IP)
+	assert.NotEmpty(t, block.Hostname)
+
+	block = handler(irc.Server{Host: "BACKUP.example.com"})
+	require.NotNil(t, block)
+	assert.Equal(t, "second", block.Password)
+
+	assert.Nil(t, handler(irc.Server{Host: "other.example.com"}))
+}
+
+func TestCreateServerPasswordHandler(t *testing.T) {
+	handler := createServerPasswordHandler(&config.Config{
+		Networks: []config.Preset{
+			{Host: "irc.example.com"},
+			{Host: "backup.example.com", ServerPassword: "backup"},
+		},
+	})
+
+	assert.Equal(t, "", handler(irc.Server{Host: "irc.example.com"}))
+	assert.Equal(t, "backup", handler(irc.Server{Host: "Backup.example.com"}))
+	assert.Equal(t, "", handler(irc.Server{Host: "other.example.com"}))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/mailru/easyjson"
 
 	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/linkmeta"
 	"github.com/khlieng/dispatch/storage"
 )
 
@@ -69,6 +70,8 @@ type Join struct {
 	Network  string
 	User     string
 	Channels []string
+	// Ignored joins only update the userlist, they are not shown
+	Ignored bool
 }
 
 type Part struct {
@@ -77,6 +80,8 @@ type Part struct {
 	Channel  string
 	Channels []string
 	Reason   string
+	// Ignored parts only update the userlist, they are not shown
+	Ignored bool
 }
 
 type Mode struct {
@@ -87,23 +92,43 @@ type Quit struct {
 	Network string
 	User    string
 	Reason  string
+	// Channels are the channels the quit is shown in when it is ignored in
+	// some of them, it is shown in all of them when this is empty
+	Channels []string
+	// Ignored quits only update the userlists, they are not shown
+	Ignored bool
 }
 
 type Message struct {
-	ID      string
-	Network string
-	From    string
-	To      string
-	Content string
-	Type    string
+	ID        string
+	Network   string
+	From      string
+	To        string
+	Content   string
+	Type      string
+	Highlight bool
 }
 
 type Messages struct {
-	Network  string
-	To       string
-	Messages []storage.Message
+	Network string
+	To      string
+	// Messages is always there, the API returns an empty list for channels
+	// without messages
+	Messages []storage.Message `json:",!omitempty"`
 	Prepend  bool
 	Next     string
+	// LinkMeta is the metadata of the links in the messages, keyed by
+	// message ID
+	LinkMeta map[string][]*linkmeta.Meta
+	// Highlights are the IDs of the messages that highlight the user
+	Highlights []string
+}
+
+type LinkMeta struct {
+	Network string
+	To      string
+	ID      string
+	Meta    []*linkmeta.Meta
 }
 
 type Topic struct {
@@ -175,6 +200,34 @@ type SearchResult struct {
 	Results []storage.Message
 }
 
+type MessageSearch struct {
+	Network string
+	Channel string
+	From    string
+	Nick    string
+	Event   string
+	Start   int64
+	End     int64
+	Q       string
+	Offset  int
+	Limit   int
+}
+
+type MessageSearchResult struct {
+	MessageSearch
+	Total   uint64
+	Results []MessageSearchHit
+	Error   string
+}
+
+type MessageSearchHit struct {
+	ID        string
+	Network   string
+	Channel   string
+	Fragments []string
+	Message   storage.Message
+}
+
 type ClientCert struct {
 	Cert string
 	Key  string
@@ -225,3 +278,82 @@ type DCCSend struct {
 type Tab struct {
 	storage.Tab
 }
+
+type HighlightRules struct {
+	storage.HighlightRules
+}
+
+type IgnoreRule struct {
+	storage.IgnoreRule
+}
+
+type IgnoreRemove struct {
+	ID string
+}
+
+type Ignores struct {
+	Rules []*storage.IgnoreRule
+}
+
+type ReadMarker struct {
+	storage.ReadMarker
+}
+
+type MentionsRead struct {
+	IDs []string
+}
+
+type Mentions struct {
+	Mentions []*storage.Mention
+}
+
+type PushSubscription struct {
+	Endpoint string
+	Keys     PushKeys
+}
+
+type PushKeys struct {
+	P256dh string
+	Auth   string
+}
+
+type PushUnsubscribe struct {
+	Endpoint string
+}
+
+// PushMessage is the payload of a push, Type is pm or highlight
+type PushMessage struct {
+	Type    string
+	ID      string
+	Network string
+	To      string
+	From    string
+	Content string
+}
+
+type Networks struct {
+	Networks []*storage.Network `json:",!omitempty"`
+}
+
+type Channels struct {
+	Channels []*storage.Channel `json:",!omitempty"`
+}
+
+type APITokenAdd struct {
+	Name   string
+	Scopes []string
+}
+
+// APITokenAdded has the token itself along with what got stored
+type APITokenAdded struct {
+	storage.APIToken
+	Token string
+}
+
+type APITokenRemove struct {
+	ID string
+}
+
+type APITokens struct {
+	Tokens []*storage.APIToken
+}
@@ -1,10 +1,11 @@
-//out.Data: false//v28: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+//out.Data: false//v75: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
 
 package server
 
 import (
 	json "encoding/json"
 	irc "github.com/khlieng/dispatch/pkg/irc"
+	linkmeta "github.com/khlieng/dispatch/pkg/linkmeta"
 	storage "github.com/khlieng/dispatch/storage"
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
@@ -368,9 +369,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer3(in *jlexer.Lexer, out
 		case "type":
 			out.Type = string(in.String())
 		case "data":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Data).UnmarshalJSON(data))
-			}
+			(out.Data).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -399,7 +398,7 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer3(out *jwriter.Writer,
 		} else {
 			out.RawString(prefix)
 		}
-		out.Raw((in.Data).MarshalJSON())
+		(in.Data).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
@@ -1231,7 +1230,7 @@ func (v *ReconnectSettings) UnmarshalJSON(data []byte) error {
 func (v *ReconnectSettings) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer9(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, out *Raw) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, out *ReadMarker) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1252,8 +1251,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1264,7 +1267,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer10(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer10(out *jwriter.Writer, in Raw) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer10(out *jwriter.Writer, in ReadMarker) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1274,43 +1277,63 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer10(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.Channel))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Raw) MarshalJSON() ([]byte, error) {
+func (v ReadMarker) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Raw) MarshalEasyJSON(w *jwriter.Writer) {
+func (v ReadMarker) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer10(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Raw) UnmarshalJSON(data []byte) error {
+func (v *ReadMarker) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Raw) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *ReadMarker) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer10(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, out *Quit) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, out *Raw) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1331,10 +1354,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "reason":
-			out.Reason = string(in.String())
+		case "message":
+			out.Message = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1345,7 +1366,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer11(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer11(out *jwriter.Writer, in Quit) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer11(out *jwriter.Writer, in Raw) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1355,53 +1376,43 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer11(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.User))
-	}
-	if in.Reason != "" {
-		const prefix string = ",\"reason\":"
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Reason))
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Quit) MarshalJSON() ([]byte, error) {
+func (v Raw) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Quit) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Raw) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer11(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Quit) UnmarshalJSON(data []byte) error {
+func (v *Raw) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Quit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Raw) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer11(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, out *Part) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, out *Quit) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1424,8 +1435,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, ou
 			out.Network = string(in.String())
 		case "user":
 			out.User = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
 		case "channels":
 			if in.IsNull() {
 				in.Skip()
@@ -1449,8 +1460,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, ou
 				}
 				in.Delim(']')
 			}
-		case "reason":
-			out.Reason = string(in.String())
+		case "ignored":
+			out.Ignored = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1461,7 +1472,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer12(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer, in Part) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer, in Quit) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1481,15 +1492,15 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer,
 		}
 		out.String(string(in.User))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.Reason))
 	}
 	if len(in.Channels) != 0 {
 		const prefix string = ",\"channels\":"
@@ -1510,43 +1521,43 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer12(out *jwriter.Writer,
 			out.RawByte(']')
 		}
 	}
-	if in.Reason != "" {
-		const prefix string = ",\"reason\":"
+	if in.Ignored {
+		const prefix string = ",\"ignored\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Reason))
+		out.Bool(bool(in.Ignored))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Part) MarshalJSON() ([]byte, error) {
+func (v Quit) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Part) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Quit) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer12(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Part) UnmarshalJSON(data []byte) error {
+func (v *Quit) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Quit) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer12(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, out *NickFail) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, out *PushUnsubscribe) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1565,8 +1576,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
+		case "endpoint":
+			out.Endpoint = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1577,43 +1588,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer13(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer, in NickFail) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer13(out *jwriter.Writer, in PushUnsubscribe) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Endpoint != "" {
+		const prefix string = ",\"endpoint\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
+		out.String(string(in.Endpoint))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v NickFail) MarshalJSON() ([]byte, error) {
+func (v PushUnsubscribe) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v NickFail) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PushUnsubscribe) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer13(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *NickFail) UnmarshalJSON(data []byte) error {
+func (v *PushUnsubscribe) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *NickFail) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PushUnsubscribe) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer13(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, out *Nick) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, out *PushSubscription) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1632,12 +1643,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "oldNick":
-			out.Old = string(in.String())
-		case "newNick":
-			out.New = string(in.String())
+		case "endpoint":
+			out.Endpoint = string(in.String())
+		case "keys":
+			(out.Keys).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -1648,63 +1657,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer14(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer14(out *jwriter.Writer, in Nick) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer14(out *jwriter.Writer, in PushSubscription) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Endpoint != "" {
+		const prefix string = ",\"endpoint\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Old != "" {
-		const prefix string = ",\"oldNick\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Old))
+		out.String(string(in.Endpoint))
 	}
-	if in.New != "" {
-		const prefix string = ",\"newNick\":"
+	if true {
+		const prefix string = ",\"keys\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.New))
+		(in.Keys).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Nick) MarshalJSON() ([]byte, error) {
+func (v PushSubscription) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Nick) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PushSubscription) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer14(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Nick) UnmarshalJSON(data []byte) error {
+func (v *PushSubscription) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Nick) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PushSubscription) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer14(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, out *NetworkName) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, out *PushMessage) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1723,10 +1722,18 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "id":
+			out.ID = string(in.String())
 		case "network":
 			out.Network = string(in.String())
-		case "name":
-			out.Name = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "content":
+			out.Content = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1737,53 +1744,93 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer15(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer15(out *jwriter.Writer, in NetworkName) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer15(out *jwriter.Writer, in PushMessage) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
 		first = false
 		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.Network))
 	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	if in.To != "" {
+		const prefix string = ",\"to\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Name))
+		out.String(string(in.To))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v NetworkName) MarshalJSON() ([]byte, error) {
+func (v PushMessage) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v NetworkName) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PushMessage) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer15(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *NetworkName) UnmarshalJSON(data []byte) error {
+func (v *PushMessage) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *NetworkName) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PushMessage) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer15(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, out *Mode) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, out *PushKeys) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1792,7 +1839,6 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, ou
 		in.Skip()
 		return
 	}
-	out.Mode = new(irc.Mode)
 	in.Delim('{')
 	for !in.IsDelim('}') {
 		key := in.UnsafeFieldName(false)
@@ -1803,16 +1849,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "add":
-			out.Add = string(in.String())
-		case "remove":
-			out.Remove = string(in.String())
+		case "p256dh":
+			out.P256dh = string(in.String())
+		case "auth":
+			out.Auth = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1823,83 +1863,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer16(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer16(out *jwriter.Writer, in Mode) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer16(out *jwriter.Writer, in PushKeys) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.P256dh != "" {
+		const prefix string = ",\"p256dh\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Channel))
-	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.User))
-	}
-	if in.Add != "" {
-		const prefix string = ",\"add\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Add))
+		out.String(string(in.P256dh))
 	}
-	if in.Remove != "" {
-		const prefix string = ",\"remove\":"
+	if in.Auth != "" {
+		const prefix string = ",\"auth\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Remove))
+		out.String(string(in.Auth))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Mode) MarshalJSON() ([]byte, error) {
+func (v PushKeys) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Mode) MarshalEasyJSON(w *jwriter.Writer) {
+func (v PushKeys) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer16(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Mode) UnmarshalJSON(data []byte) error {
+func (v *PushKeys) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Mode) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *PushKeys) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer16(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, out *Messages) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, out *Part) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1920,35 +1930,37 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "to":
-			out.To = string(in.String())
-		case "messages":
+		case "user":
+			out.User = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "channels":
 			if in.IsNull() {
 				in.Skip()
-				out.Messages = nil
+				out.Channels = nil
 			} else {
 				in.Delim('[')
-				if out.Messages == nil {
+				if out.Channels == nil {
 					if !in.IsDelim(']') {
-						out.Messages = make([]storage.Message, 0, 0)
+						out.Channels = make([]string, 0, 4)
 					} else {
-						out.Messages = []storage.Message{}
+						out.Channels = []string{}
 					}
 				} else {
-					out.Messages = (out.Messages)[:0]
+					out.Channels = (out.Channels)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v19 storage.Message
-					easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &v19)
-					out.Messages = append(out.Messages, v19)
+					var v19 string
+					v19 = string(in.String())
+					out.Channels = append(out.Channels, v19)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "prepend":
-			out.Prepend = bool(in.Bool())
-		case "next":
-			out.Next = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		case "ignored":
+			out.Ignored = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1959,7 +1971,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer17(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer, in Messages) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer, in Part) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1969,18 +1981,28 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.To != "" {
-		const prefix string = ",\"to\":"
+	if in.User != "" {
+		const prefix string = ",\"user\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.To))
+		out.String(string(in.User))
 	}
-	if len(in.Messages) != 0 {
-		const prefix string = ",\"messages\":"
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
@@ -1989,62 +2011,62 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer17(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('[')
-			for v20, v21 := range in.Messages {
+			for v20, v21 := range in.Channels {
 				if v20 > 0 {
 					out.RawByte(',')
 				}
-				easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, v21)
+				out.String(string(v21))
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Prepend {
-		const prefix string = ",\"prepend\":"
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Bool(bool(in.Prepend))
+		out.String(string(in.Reason))
 	}
-	if in.Next != "" {
-		const prefix string = ",\"next\":"
+	if in.Ignored {
+		const prefix string = ",\"ignored\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Next))
+		out.Bool(bool(in.Ignored))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Messages) MarshalJSON() ([]byte, error) {
+func (v Part) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Messages) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Part) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson42239ddeEncodeGithubComKhliengDispatchServer17(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Messages) UnmarshalJSON(data []byte) error {
+func (v *Part) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Messages) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Part) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson42239ddeDecodeGithubComKhliengDispatchServer17(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, out *Message) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, out *NickFail) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2063,18 +2085,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = string(in.String())
 		case "network":
 			out.Network = string(in.String())
-		case "from":
-			out.From = string(in.String())
-		case "to":
-			out.To = string(in.String())
-		case "content":
-			out.Content = string(in.String())
-		case "type":
-			out.Type = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2085,93 +2097,134 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer18(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer18(out *jwriter.Writer, in Message) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer18(out *jwriter.Writer, in NickFail) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ID != "" {
-		const prefix string = ",\"id\":"
-		first = false
-		out.RawString(prefix[1:])
-		out.String(string(in.ID))
-	}
 	if in.Network != "" {
 		const prefix string = ",\"network\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		first = false
+		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.From != "" {
-		const prefix string = ",\"from\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NickFail) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NickFail) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NickFail) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NickFail) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, out *Nick) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		out.String(string(in.From))
+		in.Skip()
+		return
 	}
-	if in.To != "" {
-		const prefix string = ",\"to\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-		out.String(string(in.To))
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "oldNick":
+			out.Old = string(in.String())
+		case "newNick":
+			out.New = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
-	if in.Content != "" {
-		const prefix string = ",\"content\":"
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer19(out *jwriter.Writer, in Nick) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Old != "" {
+		const prefix string = ",\"oldNick\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Content))
+		out.String(string(in.Old))
 	}
-	if in.Type != "" {
-		const prefix string = ",\"type\":"
+	if in.New != "" {
+		const prefix string = ",\"newNick\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Type))
+		out.String(string(in.New))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Message) MarshalJSON() ([]byte, error) {
+func (v Nick) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer18(w, v)
+func (v Nick) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Message) UnmarshalJSON(data []byte) error {
+func (v *Nick) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer18(l, v)
+func (v *Nick) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, out *MOTD) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, out *Networks) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2190,29 +2243,33 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "content":
+		case "networks":
 			if in.IsNull() {
 				in.Skip()
-				out.Content = nil
+				out.Networks = nil
 			} else {
 				in.Delim('[')
-				if out.Content == nil {
+				if out.Networks == nil {
 					if !in.IsDelim(']') {
-						out.Content = make([]string, 0, 4)
+						out.Networks = make([]*storage.Network, 0, 8)
 					} else {
-						out.Content = []string{}
+						out.Networks = []*storage.Network{}
 					}
 				} else {
-					out.Content = (out.Content)[:0]
+					out.Networks = (out.Networks)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v22 string
-					v22 = string(in.String())
-					out.Content = append(out.Content, v22)
+					var v22 *storage.Network
+					if in.IsNull() {
+						in.Skip()
+						v22 = nil
+					} else {
+						if v22 == nil {
+							v22 = new(storage.Network)
+						}
+						(*v22).UnmarshalEasyJSON(in)
+					}
+					out.Networks = append(out.Networks, v22)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2227,41 +2284,26 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer19(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer19(out *jwriter.Writer, in MOTD) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer, in Networks) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
-		first = false
+	{
+		const prefix string = ",\"networks\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Title != "" {
-		const prefix string = ",\"title\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Title))
-	}
-	if len(in.Content) != 0 {
-		const prefix string = ",\"content\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
+		if in.Networks == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
 		} else {
-			out.RawString(prefix)
-		}
-		{
 			out.RawByte('[')
-			for v23, v24 := range in.Content {
+			for v23, v24 := range in.Networks {
 				if v23 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v24))
+				if v24 == nil {
+					out.RawString("null")
+				} else {
+					(*v24).MarshalEasyJSON(out)
+				}
 			}
 			out.RawByte(']')
 		}
@@ -2270,29 +2312,29 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer19(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v MOTD) MarshalJSON() ([]byte, error) {
+func (v Networks) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v MOTD) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer19(w, v)
+func (v Networks) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *MOTD) UnmarshalJSON(data []byte) error {
+func (v *Networks) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *MOTD) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer19(l, v)
+func (v *Networks) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, out *Kick) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, out *NetworkName) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2313,14 +2355,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "sender":
-			out.Sender = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "reason":
-			out.Reason = string(in.String())
+		case "name":
+			out.Name = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2331,7 +2367,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer20(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer, in Kick) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer21(out *jwriter.Writer, in NetworkName) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2341,25 +2377,101 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.String(string(in.Name))
 	}
-	if in.Sender != "" {
-		const prefix string = ",\"sender\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NetworkName) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NetworkName) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NetworkName) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NetworkName) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, out *Mode) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	out.Mode = new(irc.Mode)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "add":
+			out.Add = string(in.String())
+		case "remove":
+			out.Remove = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer, in Mode) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Sender))
+		out.String(string(in.Channel))
 	}
 	if in.User != "" {
 		const prefix string = ",\"user\":"
@@ -2371,43 +2483,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer20(out *jwriter.Writer,
 		}
 		out.String(string(in.User))
 	}
-	if in.Reason != "" {
-		const prefix string = ",\"reason\":"
+	if in.Add != "" {
+		const prefix string = ",\"add\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Reason))
+		out.String(string(in.Add))
+	}
+	if in.Remove != "" {
+		const prefix string = ",\"remove\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Remove))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Kick) MarshalJSON() ([]byte, error) {
+func (v Mode) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Kick) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer20(w, v)
+func (v Mode) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Kick) UnmarshalJSON(data []byte) error {
+func (v *Mode) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Kick) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer20(l, v)
+func (v *Mode) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, out *Join) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, out *Messages) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2428,27 +2550,103 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "channels":
+		case "to":
+			out.To = string(in.String())
+		case "messages":
 			if in.IsNull() {
 				in.Skip()
-				out.Channels = nil
+				out.Messages = nil
 			} else {
 				in.Delim('[')
-				if out.Channels == nil {
+				if out.Messages == nil {
 					if !in.IsDelim(']') {
-						out.Channels = make([]string, 0, 4)
+						out.Messages = make([]storage.Message, 0, 0)
 					} else {
-						out.Channels = []string{}
+						out.Messages = []storage.Message{}
 					}
 				} else {
-					out.Channels = (out.Channels)[:0]
+					out.Messages = (out.Messages)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 storage.Message
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &v25)
+					out.Messages = append(out.Messages, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "prepend":
+			out.Prepend = bool(in.Bool())
+		case "next":
+			out.Next = string(in.String())
+		case "linkMeta":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.LinkMeta = make(map[string][]*linkmeta.Meta)
+				} else {
+					out.LinkMeta = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v26 []*linkmeta.Meta
+					if in.IsNull() {
+						in.Skip()
+						v26 = nil
+					} else {
+						in.Delim('[')
+						if v26 == nil {
+							if !in.IsDelim(']') {
+								v26 = make([]*linkmeta.Meta, 0, 8)
+							} else {
+								v26 = []*linkmeta.Meta{}
+							}
+						} else {
+							v26 = (v26)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v27 *linkmeta.Meta
+							if in.IsNull() {
+								in.Skip()
+								v27 = nil
+							} else {
+								if v27 == nil {
+									v27 = new(linkmeta.Meta)
+								}
+								easyjson42239ddeDecodeGithubComKhliengDispatchPkgLinkmeta(in, v27)
+							}
+							v26 = append(v26, v27)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					(out.LinkMeta)[key] = v26
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "highlights":
+			if in.IsNull() {
+				in.Skip()
+				out.Highlights = nil
+			} else {
+				in.Delim('[')
+				if out.Highlights == nil {
+					if !in.IsDelim(']') {
+						out.Highlights = make([]string, 0, 4)
+					} else {
+						out.Highlights = []string{}
+					}
+				} else {
+					out.Highlights = (out.Highlights)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v25 string
-					v25 = string(in.String())
-					out.Channels = append(out.Channels, v25)
+					var v28 string
+					v28 = string(in.String())
+					out.Highlights = append(out.Highlights, v28)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2463,7 +2661,3142 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer21(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer21(out *jwriter.Writer, in Join) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer23(out *jwriter.Writer, in Messages) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	{
+		const prefix string = ",\"messages\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Messages == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v29, v30 := range in.Messages {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, v30)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Prepend {
+		const prefix string = ",\"prepend\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Prepend))
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		out.RawString(prefix)
+		out.String(string(in.Next))
+	}
+	if len(in.LinkMeta) != 0 {
+		const prefix string = ",\"linkMeta\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v31First := true
+			for v31Name, v31Value := range in.LinkMeta {
+				if v31First {
+					v31First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v31Name))
+				out.RawByte(':')
+				if v31Value == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v32, v33 := range v31Value {
+						if v32 > 0 {
+							out.RawByte(',')
+						}
+						if v33 == nil {
+							out.RawString("null")
+						} else {
+							easyjson42239ddeEncodeGithubComKhliengDispatchPkgLinkmeta(out, *v33)
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Highlights) != 0 {
+		const prefix string = ",\"highlights\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v34, v35 := range in.Highlights {
+				if v34 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v35))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Messages) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Messages) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Messages) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Messages) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchPkgLinkmeta(in *jlexer.Lexer, out *linkmeta.Meta) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "URL":
+			out.URL = string(in.String())
+		case "siteName":
+			out.SiteName = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "imageURL":
+			out.ImageURL = string(in.String())
+		case "videoURL":
+			out.VideoURL = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "mimeType":
+			out.MIMEType = string(in.String())
+		case "size":
+			out.Size = int64(in.Int64())
+		case "width":
+			out.Width = int(in.Int())
+		case "height":
+			out.Height = int(in.Int())
+		case "thumbnailURL":
+			out.ThumbnailURL = string(in.String())
+		case "author":
+			out.Author = string(in.String())
+		case "authorURL":
+			out.AuthorURL = string(in.String())
+		case "embedType":
+			out.EmbedType = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchPkgLinkmeta(out *jwriter.Writer, in linkmeta.Meta) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.URL != "" {
+		const prefix string = ",\"URL\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.URL))
+	}
+	if in.SiteName != "" {
+		const prefix string = ",\"siteName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SiteName))
+	}
+	if in.Color != "" {
+		const prefix string = ",\"color\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Color))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Title))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Description))
+	}
+	if in.ImageURL != "" {
+		const prefix string = ",\"imageURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ImageURL))
+	}
+	if in.VideoURL != "" {
+		const prefix string = ",\"videoURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.VideoURL))
+	}
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Type))
+	}
+	if in.MIMEType != "" {
+		const prefix string = ",\"mimeType\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MIMEType))
+	}
+	if in.Size != 0 {
+		const prefix string = ",\"size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Size))
+	}
+	if in.Width != 0 {
+		const prefix string = ",\"width\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Width))
+	}
+	if in.Height != 0 {
+		const prefix string = ",\"height\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Height))
+	}
+	if in.ThumbnailURL != "" {
+		const prefix string = ",\"thumbnailURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ThumbnailURL))
+	}
+	if in.Author != "" {
+		const prefix string = ",\"author\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Author))
+	}
+	if in.AuthorURL != "" {
+		const prefix string = ",\"authorURL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.AuthorURL))
+	}
+	if in.EmbedType != "" {
+		const prefix string = ",\"embedType\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.EmbedType))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, out *MessageSearchResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "total":
+			out.Total = uint64(in.Uint64())
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]MessageSearchHit, 0, 0)
+					} else {
+						out.Results = []MessageSearchHit{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v36 MessageSearchHit
+					(v36).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v36)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "error":
+			out.Error = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "event":
+			out.Event = string(in.String())
+		case "start":
+			out.Start = int64(in.Int64())
+		case "end":
+			out.End = int64(in.Int64())
+		case "q":
+			out.Q = string(in.String())
+		case "offset":
+			out.Offset = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer24(out *jwriter.Writer, in MessageSearchResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Total != 0 {
+		const prefix string = ",\"total\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Uint64(uint64(in.Total))
+	}
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v37, v38 := range in.Results {
+				if v37 > 0 {
+					out.RawByte(',')
+				}
+				(v38).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Error))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Event != "" {
+		const prefix string = ",\"event\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Event))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Start))
+	}
+	if in.End != 0 {
+		const prefix string = ",\"end\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.End))
+	}
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Q))
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	if in.Limit != 0 {
+		const prefix string = ",\"limit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Limit))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageSearchResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageSearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageSearchResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageSearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, out *MessageSearchHit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "fragments":
+			if in.IsNull() {
+				in.Skip()
+				out.Fragments = nil
+			} else {
+				in.Delim('[')
+				if out.Fragments == nil {
+					if !in.IsDelim(']') {
+						out.Fragments = make([]string, 0, 4)
+					} else {
+						out.Fragments = []string{}
+					}
+				} else {
+					out.Fragments = (out.Fragments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v39 string
+					v39 = string(in.String())
+					out.Fragments = append(out.Fragments, v39)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "message":
+			easyjson42239ddeDecodeGithubComKhliengDispatchStorage(in, &out.Message)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer25(out *jwriter.Writer, in MessageSearchHit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Fragments) != 0 {
+		const prefix string = ",\"fragments\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v40, v41 := range in.Fragments {
+				if v40 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v41))
+			}
+			out.RawByte(']')
+		}
+	}
+	if true {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson42239ddeEncodeGithubComKhliengDispatchStorage(out, in.Message)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageSearchHit) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageSearchHit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageSearchHit) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageSearchHit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, out *MessageSearch) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "event":
+			out.Event = string(in.String())
+		case "start":
+			out.Start = int64(in.Int64())
+		case "end":
+			out.End = int64(in.Int64())
+		case "q":
+			out.Q = string(in.String())
+		case "offset":
+			out.Offset = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer, in MessageSearch) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Event != "" {
+		const prefix string = ",\"event\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Event))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Start))
+	}
+	if in.End != 0 {
+		const prefix string = ",\"end\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.End))
+	}
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Q))
+	}
+	if in.Offset != 0 {
+		const prefix string = ",\"offset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Offset))
+	}
+	if in.Limit != 0 {
+		const prefix string = ",\"limit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Limit))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageSearch) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageSearch) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageSearch) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageSearch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, out *Message) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "content":
+			out.Content = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "highlight":
+			out.Highlight = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer, in Message) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
+	}
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Type))
+	}
+	if in.Highlight {
+		const prefix string = ",\"highlight\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Highlight))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Message) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Message) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, out *MentionsRead) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "iDs":
+			if in.IsNull() {
+				in.Skip()
+				out.IDs = nil
+			} else {
+				in.Delim('[')
+				if out.IDs == nil {
+					if !in.IsDelim(']') {
+						out.IDs = make([]string, 0, 4)
+					} else {
+						out.IDs = []string{}
+					}
+				} else {
+					out.IDs = (out.IDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v42 string
+					v42 = string(in.String())
+					out.IDs = append(out.IDs, v42)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer28(out *jwriter.Writer, in MentionsRead) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.IDs) != 0 {
+		const prefix string = ",\"iDs\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v43, v44 := range in.IDs {
+				if v43 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v44))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MentionsRead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MentionsRead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MentionsRead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MentionsRead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, out *Mentions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mentions":
+			if in.IsNull() {
+				in.Skip()
+				out.Mentions = nil
+			} else {
+				in.Delim('[')
+				if out.Mentions == nil {
+					if !in.IsDelim(']') {
+						out.Mentions = make([]*storage.Mention, 0, 8)
+					} else {
+						out.Mentions = []*storage.Mention{}
+					}
+				} else {
+					out.Mentions = (out.Mentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v45 *storage.Mention
+					if in.IsNull() {
+						in.Skip()
+						v45 = nil
+					} else {
+						if v45 == nil {
+							v45 = new(storage.Mention)
+						}
+						easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in, v45)
+					}
+					out.Mentions = append(out.Mentions, v45)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer29(out *jwriter.Writer, in Mentions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Mentions) != 0 {
+		const prefix string = ",\"mentions\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v46, v47 := range in.Mentions {
+				if v46 > 0 {
+					out.RawByte(',')
+				}
+				if v47 == nil {
+					out.RawString("null")
+				} else {
+					easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out, *v47)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Mentions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Mentions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Mentions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Mentions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *storage.Mention) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "content":
+			out.Content = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		case "read":
+			out.Read = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in storage.Mention) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.From != "" {
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.From))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Content))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Time))
+	}
+	if in.Read {
+		const prefix string = ",\"read\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Read))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, out *MOTD) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+				out.Content = nil
+			} else {
+				in.Delim('[')
+				if out.Content == nil {
+					if !in.IsDelim(']') {
+						out.Content = make([]string, 0, 4)
+					} else {
+						out.Content = []string{}
+					}
+				} else {
+					out.Content = (out.Content)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v48 string
+					v48 = string(in.String())
+					out.Content = append(out.Content, v48)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer30(out *jwriter.Writer, in MOTD) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Title))
+	}
+	if len(in.Content) != 0 {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v49, v50 := range in.Content {
+				if v49 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v50))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MOTD) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MOTD) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MOTD) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MOTD) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, out *LinkMeta) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "to":
+			out.To = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "meta":
+			if in.IsNull() {
+				in.Skip()
+				out.Meta = nil
+			} else {
+				in.Delim('[')
+				if out.Meta == nil {
+					if !in.IsDelim(']') {
+						out.Meta = make([]*linkmeta.Meta, 0, 8)
+					} else {
+						out.Meta = []*linkmeta.Meta{}
+					}
+				} else {
+					out.Meta = (out.Meta)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v51 *linkmeta.Meta
+					if in.IsNull() {
+						in.Skip()
+						v51 = nil
+					} else {
+						if v51 == nil {
+							v51 = new(linkmeta.Meta)
+						}
+						easyjson42239ddeDecodeGithubComKhliengDispatchPkgLinkmeta(in, v51)
+					}
+					out.Meta = append(out.Meta, v51)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer31(out *jwriter.Writer, in LinkMeta) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.To != "" {
+		const prefix string = ",\"to\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.To))
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if len(in.Meta) != 0 {
+		const prefix string = ",\"meta\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v52, v53 := range in.Meta {
+				if v52 > 0 {
+					out.RawByte(',')
+				}
+				if v53 == nil {
+					out.RawString("null")
+				} else {
+					easyjson42239ddeEncodeGithubComKhliengDispatchPkgLinkmeta(out, *v53)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LinkMeta) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LinkMeta) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LinkMeta) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LinkMeta) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, out *Kick) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "sender":
+			out.Sender = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer32(out *jwriter.Writer, in Kick) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Sender != "" {
+		const prefix string = ",\"sender\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Sender))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Kick) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Kick) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Kick) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Kick) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, out *Join) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+				out.Channels = nil
+			} else {
+				in.Delim('[')
+				if out.Channels == nil {
+					if !in.IsDelim(']') {
+						out.Channels = make([]string, 0, 4)
+					} else {
+						out.Channels = []string{}
+					}
+				} else {
+					out.Channels = (out.Channels)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v54 string
+					v54 = string(in.String())
+					out.Channels = append(out.Channels, v54)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ignored":
+			out.Ignored = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer33(out *jwriter.Writer, in Join) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	if len(in.Channels) != 0 {
+		const prefix string = ",\"channels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v55, v56 := range in.Channels {
+				if v55 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v56))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Ignored {
+		const prefix string = ",\"ignored\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Ignored))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Join) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Join) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Join) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Join) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer34(in *jlexer.Lexer, out *Invite) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer34(out *jwriter.Writer, in Invite) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.User != "" {
+		const prefix string = ",\"user\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.User))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Invite) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Invite) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Invite) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Invite) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer34(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer35(in *jlexer.Lexer, out *Ignores) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "rules":
+			if in.IsNull() {
+				in.Skip()
+				out.Rules = nil
+			} else {
+				in.Delim('[')
+				if out.Rules == nil {
+					if !in.IsDelim(']') {
+						out.Rules = make([]*storage.IgnoreRule, 0, 8)
+					} else {
+						out.Rules = []*storage.IgnoreRule{}
+					}
+				} else {
+					out.Rules = (out.Rules)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v57 *storage.IgnoreRule
+					if in.IsNull() {
+						in.Skip()
+						v57 = nil
+					} else {
+						if v57 == nil {
+							v57 = new(storage.IgnoreRule)
+						}
+						easyjson42239ddeDecodeGithubComKhliengDispatchStorage3(in, v57)
+					}
+					out.Rules = append(out.Rules, v57)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer35(out *jwriter.Writer, in Ignores) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Rules) != 0 {
+		const prefix string = ",\"rules\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v58, v59 := range in.Rules {
+				if v58 > 0 {
+					out.RawByte(',')
+				}
+				if v59 == nil {
+					out.RawString("null")
+				} else {
+					easyjson42239ddeEncodeGithubComKhliengDispatchStorage3(out, *v59)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Ignores) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Ignores) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Ignores) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Ignores) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer35(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage3(in *jlexer.Lexer, out *storage.IgnoreRule) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "mask":
+			out.Mask = string(in.String())
+		case "regex":
+			out.Regex = bool(in.Bool())
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "types":
+			if in.IsNull() {
+				in.Skip()
+				out.Types = nil
+			} else {
+				in.Delim('[')
+				if out.Types == nil {
+					if !in.IsDelim(']') {
+						out.Types = make([]string, 0, 4)
+					} else {
+						out.Types = []string{}
+					}
+				} else {
+					out.Types = (out.Types)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v60 string
+					v60 = string(in.String())
+					out.Types = append(out.Types, v60)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage3(out *jwriter.Writer, in storage.IgnoreRule) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Mask != "" {
+		const prefix string = ",\"mask\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Mask))
+	}
+	if in.Regex {
+		const prefix string = ",\"regex\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Regex))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Types) != 0 {
+		const prefix string = ",\"types\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v61, v62 := range in.Types {
+				if v61 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v62))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer36(in *jlexer.Lexer, out *IgnoreRule) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "nick":
+			out.Nick = string(in.String())
+		case "mask":
+			out.Mask = string(in.String())
+		case "regex":
+			out.Regex = bool(in.Bool())
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "types":
+			if in.IsNull() {
+				in.Skip()
+				out.Types = nil
+			} else {
+				in.Delim('[')
+				if out.Types == nil {
+					if !in.IsDelim(']') {
+						out.Types = make([]string, 0, 4)
+					} else {
+						out.Types = []string{}
+					}
+				} else {
+					out.Types = (out.Types)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v63 string
+					v63 = string(in.String())
+					out.Types = append(out.Types, v63)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer36(out *jwriter.Writer, in IgnoreRule) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Nick != "" {
+		const prefix string = ",\"nick\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Nick))
+	}
+	if in.Mask != "" {
+		const prefix string = ",\"mask\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Mask))
+	}
+	if in.Regex {
+		const prefix string = ",\"regex\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Regex))
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if len(in.Types) != 0 {
+		const prefix string = ",\"types\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v64, v65 := range in.Types {
+				if v64 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v65))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IgnoreRule) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IgnoreRule) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IgnoreRule) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IgnoreRule) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer36(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer37(in *jlexer.Lexer, out *IgnoreRemove) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer37(out *jwriter.Writer, in IgnoreRemove) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IgnoreRemove) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IgnoreRemove) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IgnoreRemove) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IgnoreRemove) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer37(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer38(in *jlexer.Lexer, out *IRCError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer38(out *jwriter.Writer, in IRCError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Target))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IRCError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IRCError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IRCError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IRCError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer38(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer39(in *jlexer.Lexer, out *HighlightRules) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nick":
+			out.Nick = bool(in.Bool())
+		case "keywords":
+			if in.IsNull() {
+				in.Skip()
+				out.Keywords = nil
+			} else {
+				in.Delim('[')
+				if out.Keywords == nil {
+					if !in.IsDelim(']') {
+						out.Keywords = make([]string, 0, 4)
+					} else {
+						out.Keywords = []string{}
+					}
+				} else {
+					out.Keywords = (out.Keywords)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v66 string
+					v66 = string(in.String())
+					out.Keywords = append(out.Keywords, v66)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "patterns":
+			if in.IsNull() {
+				in.Skip()
+				out.Patterns = nil
+			} else {
+				in.Delim('[')
+				if out.Patterns == nil {
+					if !in.IsDelim(']') {
+						out.Patterns = make([]string, 0, 4)
+					} else {
+						out.Patterns = []string{}
+					}
+				} else {
+					out.Patterns = (out.Patterns)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v67 string
+					v67 = string(in.String())
+					out.Patterns = append(out.Patterns, v67)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "muted":
+			if in.IsNull() {
+				in.Skip()
+				out.Muted = nil
+			} else {
+				in.Delim('[')
+				if out.Muted == nil {
+					if !in.IsDelim(']') {
+						out.Muted = make([]storage.Tab, 0, 2)
+					} else {
+						out.Muted = []storage.Tab{}
+					}
+				} else {
+					out.Muted = (out.Muted)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v68 storage.Tab
+					easyjson42239ddeDecodeGithubComKhliengDispatchStorage4(in, &v68)
+					out.Muted = append(out.Muted, v68)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer39(out *jwriter.Writer, in HighlightRules) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Nick {
+		const prefix string = ",\"nick\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Nick))
+	}
+	if len(in.Keywords) != 0 {
+		const prefix string = ",\"keywords\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v69, v70 := range in.Keywords {
+				if v69 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v70))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Patterns) != 0 {
+		const prefix string = ",\"patterns\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v71, v72 := range in.Patterns {
+				if v71 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v72))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Muted) != 0 {
+		const prefix string = ",\"muted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v73, v74 := range in.Muted {
+				if v73 > 0 {
+					out.RawByte(',')
+				}
+				easyjson42239ddeEncodeGithubComKhliengDispatchStorage4(out, v74)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HighlightRules) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HighlightRules) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HighlightRules) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HighlightRules) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer39(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage4(in *jlexer.Lexer, out *storage.Tab) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage4(out *jwriter.Writer, in storage.Tab) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer40(in *jlexer.Lexer, out *FetchMessages) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer40(out *jwriter.Writer, in FetchMessages) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Channel))
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FetchMessages) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FetchMessages) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FetchMessages) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FetchMessages) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer40(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer41(in *jlexer.Lexer, out *Features) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Features = make(map[string]interface{})
+				} else {
+					out.Features = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v75 interface{}
+					if m, ok := v75.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v75.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v75 = in.Interface()
+					}
+					(out.Features)[key] = v75
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer41(out *jwriter.Writer, in Features) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if len(in.Features) != 0 {
+		const prefix string = ",\"features\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v76First := true
+			for v76Name, v76Value := range in.Features {
+				if v76First {
+					v76First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v76Name))
+				out.RawByte(':')
+				if m, ok := v76Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v76Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v76Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Features) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Features) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Features) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Features) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer41(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer42(in *jlexer.Lexer, out *Error) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer42(out *jwriter.Writer, in Error) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Network))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Error) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Error) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer42(l, v)
+}
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer43(in *jlexer.Lexer, out *DCCSend) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "network":
+			out.Network = string(in.String())
+		case "from":
+			out.From = string(in.String())
+		case "filename":
+			out.Filename = string(in.String())
+		case "size":
+			out.Size = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer43(out *jwriter.Writer, in DCCSend) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2473,62 +5806,73 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer21(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.From != "" {
+		const prefix string = ",\"from\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.From))
 	}
-	if len(in.Channels) != 0 {
-		const prefix string = ",\"channels\":"
+	if in.Filename != "" {
+		const prefix string = ",\"filename\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('[')
-			for v26, v27 := range in.Channels {
-				if v26 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v27))
-			}
-			out.RawByte(']')
+		out.String(string(in.Filename))
+	}
+	if in.Size != "" {
+		const prefix string = ",\"size\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Size))
+	}
+	if in.URL != "" {
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.String(string(in.URL))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Join) MarshalJSON() ([]byte, error) {
+func (v DCCSend) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer43(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Join) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer21(w, v)
+func (v DCCSend) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer43(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Join) UnmarshalJSON(data []byte) error {
+func (v *DCCSend) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer43(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Join) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer21(l, v)
+func (v *DCCSend) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer43(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, out *Invite) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer44(in *jlexer.Lexer, out *ConnectionUpdate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2549,10 +5893,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "user":
-			out.User = string(in.String())
+		case "connected":
+			out.Connected = bool(in.Bool())
+		case "error":
+			out.Error = string(in.String())
+		case "errorType":
+			out.ErrorType = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2563,7 +5909,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer22(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer, in Invite) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer44(out *jwriter.Writer, in ConnectionUpdate) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2573,53 +5919,63 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer22(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
+	if in.Connected {
+		const prefix string = ",\"connected\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Channel))
+		out.Bool(bool(in.Connected))
 	}
-	if in.User != "" {
-		const prefix string = ",\"user\":"
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.User))
+		out.String(string(in.Error))
+	}
+	if in.ErrorType != "" {
+		const prefix string = ",\"errorType\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ErrorType))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Invite) MarshalJSON() ([]byte, error) {
+func (v ConnectionUpdate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer44(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Invite) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer22(w, v)
+func (v ConnectionUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer44(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Invite) UnmarshalJSON(data []byte) error {
+func (v *ConnectionUpdate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer44(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Invite) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer22(l, v)
+func (v *ConnectionUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer44(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, out *IRCError) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer45(in *jlexer.Lexer, out *ClientCert) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2638,12 +5994,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "target":
-			out.Target = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "cert":
+			out.Cert = string(in.String())
+		case "key":
+			out.Key = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2654,63 +6008,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer23(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer23(out *jwriter.Writer, in IRCError) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer45(out *jwriter.Writer, in ClientCert) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Cert != "" {
+		const prefix string = ",\"cert\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Target != "" {
-		const prefix string = ",\"target\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Target))
+		out.String(string(in.Cert))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.String(string(in.Key))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v IRCError) MarshalJSON() ([]byte, error) {
+func (v ClientCert) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer45(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v IRCError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer23(w, v)
+func (v ClientCert) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer45(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *IRCError) UnmarshalJSON(data []byte) error {
+func (v *ClientCert) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer45(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *IRCError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer23(l, v)
+func (v *ClientCert) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer45(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, out *FetchMessages) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer46(in *jlexer.Lexer, out *Channels) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2729,12 +6073,37 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "channel":
-			out.Channel = string(in.String())
-		case "next":
-			out.Next = string(in.String())
+		case "channels":
+			if in.IsNull() {
+				in.Skip()
+				out.Channels = nil
+			} else {
+				in.Delim('[')
+				if out.Channels == nil {
+					if !in.IsDelim(']') {
+						out.Channels = make([]*storage.Channel, 0, 8)
+					} else {
+						out.Channels = []*storage.Channel{}
+					}
+				} else {
+					out.Channels = (out.Channels)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v77 *storage.Channel
+					if in.IsNull() {
+						in.Skip()
+						v77 = nil
+					} else {
+						if v77 == nil {
+							v77 = new(storage.Channel)
+						}
+						(*v77).UnmarshalEasyJSON(in)
+					}
+					out.Channels = append(out.Channels, v77)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2745,63 +6114,57 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer24(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer24(out *jwriter.Writer, in FetchMessages) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer46(out *jwriter.Writer, in Channels) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
-		first = false
+	{
+		const prefix string = ",\"channels\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Channel != "" {
-		const prefix string = ",\"channel\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Channel))
-	}
-	if in.Next != "" {
-		const prefix string = ",\"next\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
+		if in.Channels == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
 		} else {
-			out.RawString(prefix)
+			out.RawByte('[')
+			for v78, v79 := range in.Channels {
+				if v78 > 0 {
+					out.RawByte(',')
+				}
+				if v79 == nil {
+					out.RawString("null")
+				} else {
+					(*v79).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
 		}
-		out.String(string(in.Next))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v FetchMessages) MarshalJSON() ([]byte, error) {
+func (v Channels) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer46(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v FetchMessages) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer24(w, v)
+func (v Channels) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer46(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *FetchMessages) UnmarshalJSON(data []byte) error {
+func (v *Channels) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer46(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *FetchMessages) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer24(l, v)
+func (v *Channels) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer46(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, out *Features) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer47(in *jlexer.Lexer, out *ChannelSearchResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2820,34 +6183,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "features":
+		case "results":
 			if in.IsNull() {
 				in.Skip()
+				out.Results = nil
 			} else {
-				in.Delim('{')
-				if !in.IsDelim('}') {
-					out.Features = make(map[string]interface{})
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]*storage.ChannelListItem, 0, 8)
+					} else {
+						out.Results = []*storage.ChannelListItem{}
+					}
 				} else {
-					out.Features = nil
+					out.Results = (out.Results)[:0]
 				}
-				for !in.IsDelim('}') {
-					key := string(in.String())
-					in.WantColon()
-					var v28 interface{}
-					if m, ok := v28.(easyjson.Unmarshaler); ok {
-						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v28.(json.Unmarshaler); ok {
-						_ = m.UnmarshalJSON(in.Raw())
+				for !in.IsDelim(']') {
+					var v80 *storage.ChannelListItem
+					if in.IsNull() {
+						in.Skip()
+						v80 = nil
 					} else {
-						v28 = in.Interface()
+						if v80 == nil {
+							v80 = new(storage.ChannelListItem)
+						}
+						easyjson42239ddeDecodeGithubComKhliengDispatchStorage5(in, v80)
 					}
-					(out.Features)[key] = v28
+					out.Results = append(out.Results, v80)
 					in.WantComma()
 				}
-				in.Delim('}')
+				in.Delim(']')
 			}
+		case "network":
+			out.Network = string(in.String())
+		case "q":
+			out.Q = string(in.String())
+		case "start":
+			out.Start = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -2858,73 +6230,86 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer25(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer25(out *jwriter.Writer, in Features) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer47(out *jwriter.Writer, in ChannelSearchResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if len(in.Results) != 0 {
+		const prefix string = ",\"results\":"
 		first = false
 		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v81, v82 := range in.Results {
+				if v81 > 0 {
+					out.RawByte(',')
+				}
+				if v82 == nil {
+					out.RawString("null")
+				} else {
+					easyjson42239ddeEncodeGithubComKhliengDispatchStorage5(out, *v82)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.Network))
 	}
-	if len(in.Features) != 0 {
-		const prefix string = ",\"features\":"
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		{
-			out.RawByte('{')
-			v29First := true
-			for v29Name, v29Value := range in.Features {
-				if v29First {
-					v29First = false
-				} else {
-					out.RawByte(',')
-				}
-				out.String(string(v29Name))
-				out.RawByte(':')
-				if m, ok := v29Value.(easyjson.Marshaler); ok {
-					m.MarshalEasyJSON(out)
-				} else if m, ok := v29Value.(json.Marshaler); ok {
-					out.Raw(m.MarshalJSON())
-				} else {
-					out.Raw(json.Marshal(v29Value))
-				}
-			}
-			out.RawByte('}')
+		out.String(string(in.Q))
+	}
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
+		out.Int(int(in.Start))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Features) MarshalJSON() ([]byte, error) {
+func (v ChannelSearchResult) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer47(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Features) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer25(w, v)
+func (v ChannelSearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer47(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Features) UnmarshalJSON(data []byte) error {
+func (v *ChannelSearchResult) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer47(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Features) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer25(l, v)
+func (v *ChannelSearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer47(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, out *Error) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage5(in *jlexer.Lexer, out *storage.ChannelListItem) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2943,10 +6328,12 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "userCount":
+			out.UserCount = int(in.Int())
+		case "topic":
+			out.Topic = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -2957,53 +6344,39 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer26(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer26(out *jwriter.Writer, in Error) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage5(out *jwriter.Writer, in storage.ChannelListItem) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
+		out.String(string(in.Name))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if in.UserCount != 0 {
+		const prefix string = ",\"userCount\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		out.Int(int(in.UserCount))
+	}
+	if in.Topic != "" {
+		const prefix string = ",\"topic\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Topic))
 	}
 	out.RawByte('}')
 }
-
-// MarshalJSON supports json.Marshaler interface
-func (v Error) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer26(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *Error) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer26(l, v)
-}
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, out *DCCSend) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer48(in *jlexer.Lexer, out *ChannelSearch) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3024,12 +6397,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "from":
-			out.From = string(in.String())
-		case "filename":
-			out.Filename = string(in.String())
-		case "url":
-			out.URL = string(in.String())
+		case "q":
+			out.Q = string(in.String())
+		case "start":
+			out.Start = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -3040,7 +6411,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer27(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer, in DCCSend) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer48(out *jwriter.Writer, in ChannelSearch) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3050,63 +6421,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer27(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.From != "" {
-		const prefix string = ",\"from\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.From))
-	}
-	if in.Filename != "" {
-		const prefix string = ",\"filename\":"
+	if in.Q != "" {
+		const prefix string = ",\"q\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Filename))
+		out.String(string(in.Q))
 	}
-	if in.URL != "" {
-		const prefix string = ",\"url\":"
+	if in.Start != 0 {
+		const prefix string = ",\"start\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.URL))
+		out.Int(int(in.Start))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v DCCSend) MarshalJSON() ([]byte, error) {
+func (v ChannelSearch) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer48(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v DCCSend) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer27(w, v)
+func (v ChannelSearch) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer48(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *DCCSend) UnmarshalJSON(data []byte) error {
+func (v *ChannelSearch) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer48(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *DCCSend) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer27(l, v)
+func (v *ChannelSearch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer48(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, out *ConnectionUpdate) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer49(in *jlexer.Lexer, out *ChannelForward) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3127,12 +6488,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, ou
 		switch key {
 		case "network":
 			out.Network = string(in.String())
-		case "connected":
-			out.Connected = bool(in.Bool())
-		case "error":
-			out.Error = string(in.String())
-		case "errorType":
-			out.ErrorType = string(in.String())
+		case "old":
+			out.Old = string(in.String())
+		case "new":
+			out.New = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3143,7 +6502,7 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer28(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer28(out *jwriter.Writer, in ConnectionUpdate) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer49(out *jwriter.Writer, in ChannelForward) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3153,63 +6512,53 @@ func easyjson42239ddeEncodeGithubComKhliengDispatchServer28(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.String(string(in.Network))
 	}
-	if in.Connected {
-		const prefix string = ",\"connected\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Bool(bool(in.Connected))
-	}
-	if in.Error != "" {
-		const prefix string = ",\"error\":"
+	if in.Old != "" {
+		const prefix string = ",\"old\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Error))
+		out.String(string(in.Old))
 	}
-	if in.ErrorType != "" {
-		const prefix string = ",\"errorType\":"
+	if in.New != "" {
+		const prefix string = ",\"new\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.ErrorType))
+		out.String(string(in.New))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ConnectionUpdate) MarshalJSON() ([]byte, error) {
+func (v ChannelForward) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer49(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ConnectionUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer28(w, v)
+func (v ChannelForward) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer49(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ConnectionUpdate) UnmarshalJSON(data []byte) error {
+func (v *ChannelForward) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer49(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ConnectionUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer28(l, v)
+func (v *ChannelForward) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer49(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, out *ClientCert) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer50(in *jlexer.Lexer, out *Away) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3228,10 +6577,10 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "cert":
-			out.Cert = string(in.String())
-		case "key":
-			out.Key = string(in.String())
+		case "network":
+			out.Network = string(in.String())
+		case "message":
+			out.Message = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3242,53 +6591,53 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer29(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer29(out *jwriter.Writer, in ClientCert) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer50(out *jwriter.Writer, in Away) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Cert != "" {
-		const prefix string = ",\"cert\":"
+	if in.Network != "" {
+		const prefix string = ",\"network\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Cert))
+		out.String(string(in.Network))
 	}
-	if in.Key != "" {
-		const prefix string = ",\"key\":"
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Key))
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ClientCert) MarshalJSON() ([]byte, error) {
+func (v Away) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer50(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ClientCert) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer29(w, v)
+func (v Away) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer50(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ClientCert) UnmarshalJSON(data []byte) error {
+func (v *Away) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer50(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ClientCert) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer29(l, v)
+func (v *Away) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer50(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, out *ChannelSearchResult) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer51(in *jlexer.Lexer, out *APITokens) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3307,43 +6656,37 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "results":
+		case "tokens":
 			if in.IsNull() {
 				in.Skip()
-				out.Results = nil
+				out.Tokens = nil
 			} else {
 				in.Delim('[')
-				if out.Results == nil {
+				if out.Tokens == nil {
 					if !in.IsDelim(']') {
-						out.Results = make([]*storage.ChannelListItem, 0, 8)
+						out.Tokens = make([]*storage.APIToken, 0, 8)
 					} else {
-						out.Results = []*storage.ChannelListItem{}
+						out.Tokens = []*storage.APIToken{}
 					}
 				} else {
-					out.Results = (out.Results)[:0]
+					out.Tokens = (out.Tokens)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v30 *storage.ChannelListItem
+					var v83 *storage.APIToken
 					if in.IsNull() {
 						in.Skip()
-						v30 = nil
+						v83 = nil
 					} else {
-						if v30 == nil {
-							v30 = new(storage.ChannelListItem)
+						if v83 == nil {
+							v83 = new(storage.APIToken)
 						}
-						easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in, v30)
+						easyjson42239ddeDecodeGithubComKhliengDispatchStorage6(in, v83)
 					}
-					out.Results = append(out.Results, v30)
+					out.Tokens = append(out.Tokens, v83)
 					in.WantComma()
 				}
 				in.Delim(']')
-			}
-		case "network":
-			out.Network = string(in.String())
-		case "q":
-			out.Q = string(in.String())
-		case "start":
-			out.Start = int(in.Int())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3354,86 +6697,56 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer30(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer30(out *jwriter.Writer, in ChannelSearchResult) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer51(out *jwriter.Writer, in APITokens) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if len(in.Results) != 0 {
-		const prefix string = ",\"results\":"
+	if len(in.Tokens) != 0 {
+		const prefix string = ",\"tokens\":"
 		first = false
 		out.RawString(prefix[1:])
 		{
 			out.RawByte('[')
-			for v31, v32 := range in.Results {
-				if v31 > 0 {
+			for v84, v85 := range in.Tokens {
+				if v84 > 0 {
 					out.RawByte(',')
 				}
-				if v32 == nil {
+				if v85 == nil {
 					out.RawString("null")
 				} else {
-					easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out, *v32)
+					easyjson42239ddeEncodeGithubComKhliengDispatchStorage6(out, *v85)
 				}
 			}
 			out.RawByte(']')
 		}
 	}
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Network))
-	}
-	if in.Q != "" {
-		const prefix string = ",\"q\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Q))
-	}
-	if in.Start != 0 {
-		const prefix string = ",\"start\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.Start))
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelSearchResult) MarshalJSON() ([]byte, error) {
+func (v APITokens) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer51(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelSearchResult) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer30(w, v)
+func (v APITokens) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer51(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelSearchResult) UnmarshalJSON(data []byte) error {
+func (v *APITokens) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer51(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelSearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer30(l, v)
+func (v *APITokens) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer51(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *storage.ChannelListItem) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchStorage6(in *jlexer.Lexer, out *storage.APIToken) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3452,12 +6765,37 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
+		case "id":
+			out.ID = string(in.String())
 		case "name":
 			out.Name = string(in.String())
-		case "userCount":
-			out.UserCount = int(in.Int())
-		case "topic":
-			out.Topic = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v86 string
+					v86 = string(in.String())
+					out.Scopes = append(out.Scopes, v86)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created":
+			out.Created = int64(in.Int64())
+		case "lastUsed":
+			out.LastUsed = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -3468,39 +6806,68 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in storage.ChannelListItem) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchStorage6(out *jwriter.Writer, in storage.APIToken) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
 		first = false
 		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.Name))
 	}
-	if in.UserCount != 0 {
-		const prefix string = ",\"userCount\":"
+	if len(in.Scopes) != 0 {
+		const prefix string = ",\"scopes\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.Int(int(in.UserCount))
+		{
+			out.RawByte('[')
+			for v87, v88 := range in.Scopes {
+				if v87 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v88))
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.Topic != "" {
-		const prefix string = ",\"topic\":"
+	if in.Created != 0 {
+		const prefix string = ",\"created\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Topic))
+		out.Int64(int64(in.Created))
+	}
+	if in.LastUsed != 0 {
+		const prefix string = ",\"lastUsed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.LastUsed))
 	}
 	out.RawByte('}')
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, out *ChannelSearch) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer52(in *jlexer.Lexer, out *APITokenRemove) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3519,12 +6886,8 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "q":
-			out.Q = string(in.String())
-		case "start":
-			out.Start = int(in.Int())
+		case "id":
+			out.ID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -3535,63 +6898,43 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer31(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer31(out *jwriter.Writer, in ChannelSearch) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer52(out *jwriter.Writer, in APITokenRemove) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
-	}
-	if in.Q != "" {
-		const prefix string = ",\"q\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Q))
-	}
-	if in.Start != 0 {
-		const prefix string = ",\"start\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.Start))
+		out.String(string(in.ID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelSearch) MarshalJSON() ([]byte, error) {
+func (v APITokenRemove) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer52(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelSearch) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer31(w, v)
+func (v APITokenRemove) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer52(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelSearch) UnmarshalJSON(data []byte) error {
+func (v *APITokenRemove) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer52(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelSearch) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer31(l, v)
+func (v *APITokenRemove) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer52(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, out *ChannelForward) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer53(in *jlexer.Lexer, out *APITokenAdded) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3610,12 +6953,39 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "old":
-			out.Old = string(in.String())
-		case "new":
-			out.New = string(in.String())
+		case "token":
+			out.Token = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v89 string
+					v89 = string(in.String())
+					out.Scopes = append(out.Scopes, v89)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created":
+			out.Created = int64(in.Int64())
+		case "lastUsed":
+			out.LastUsed = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -3626,63 +6996,102 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer32(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer32(out *jwriter.Writer, in ChannelForward) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer53(out *jwriter.Writer, in APITokenAdded) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Token != "" {
+		const prefix string = ",\"token\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
+		out.String(string(in.Token))
 	}
-	if in.Old != "" {
-		const prefix string = ",\"old\":"
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Old))
+		out.String(string(in.ID))
 	}
-	if in.New != "" {
-		const prefix string = ",\"new\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.New))
+		out.String(string(in.Name))
+	}
+	if len(in.Scopes) != 0 {
+		const prefix string = ",\"scopes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v90, v91 := range in.Scopes {
+				if v90 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v91))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Created != 0 {
+		const prefix string = ",\"created\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Created))
+	}
+	if in.LastUsed != 0 {
+		const prefix string = ",\"lastUsed\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.LastUsed))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChannelForward) MarshalJSON() ([]byte, error) {
+func (v APITokenAdded) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer53(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChannelForward) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer32(w, v)
+func (v APITokenAdded) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer53(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChannelForward) UnmarshalJSON(data []byte) error {
+func (v *APITokenAdded) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer53(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChannelForward) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer32(l, v)
+func (v *APITokenAdded) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer53(l, v)
 }
-func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, out *Away) {
+func easyjson42239ddeDecodeGithubComKhliengDispatchServer54(in *jlexer.Lexer, out *APITokenAdd) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3701,10 +7110,31 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "network":
-			out.Network = string(in.String())
-		case "message":
-			out.Message = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v92 string
+					v92 = string(in.String())
+					out.Scopes = append(out.Scopes, v92)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -3715,49 +7145,58 @@ func easyjson42239ddeDecodeGithubComKhliengDispatchServer33(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGithubComKhliengDispatchServer33(out *jwriter.Writer, in Away) {
+func easyjson42239ddeEncodeGithubComKhliengDispatchServer54(out *jwriter.Writer, in APITokenAdd) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Network != "" {
-		const prefix string = ",\"network\":"
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
 		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Network))
+		out.String(string(in.Name))
 	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
+	if len(in.Scopes) != 0 {
+		const prefix string = ",\"scopes\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.Message))
+		{
+			out.RawByte('[')
+			for v93, v94 := range in.Scopes {
+				if v93 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v94))
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Away) MarshalJSON() ([]byte, error) {
+func (v APITokenAdd) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(&w, v)
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer54(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Away) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGithubComKhliengDispatchServer33(w, v)
+func (v APITokenAdd) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGithubComKhliengDispatchServer54(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Away) UnmarshalJSON(data []byte) error {
+func (v *APITokenAdd) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(&r, v)
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer54(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Away) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGithubComKhliengDispatchServer33(l, v)
+func (v *APITokenAdd) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGithubComKhliengDispatchServer54(l, v)
 }
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+	"github.com/khlieng/dispatch/storage"
+)
+
+const (
+	linkMetaWorkers   = 4
+	linkMetaQueueSize = 256
+	// linkMetaMaxLinks is how many links in a message get previews
+	linkMetaMaxLinks = 3
+	// linkMetaTTL is how long fetched metadata gets used before the link
+	// gets fetched again, links that had nothing to show get retried sooner
+	linkMetaTTL       = 7 * 24 * time.Hour
+	linkMetaFailedTTL = time.Hour
+)
+
+var linkPattern = regexp.MustCompile(`https?://[^\s\x00-\x1f<>"]+`)
+
+// findLinks returns the first few http and https links in a message
+func findLinks(content string) []string {
+	var links []string
+
+	for _, link := range linkPattern.FindAllString(content, -1) {
+		link = trimLink(link)
+
+		u, err := url.Parse(link)
+		if err != nil || u.Host == "" || hasLink(links, link) {
+			continue
+		}
+
+		links = append(links, link)
+		if len(links) == linkMetaMaxLinks {
+			break
+		}
+	}
+
+	return links
+}
+
+// trimLink removes the punctuation that is more likely to end the sentence
+// than the link, closing parens are kept when the link opens them
+func trimLink(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+		if strings.IndexByte(".,:;!?'", last) >= 0 ||
+			(last == ')' && strings.Count(link, "(") < strings.Count(link, ")")) {
+			link = link[:len(link)-1]
+			continue
+		}
+		break
+	}
+	return link
+}
+
+func hasLink(links []string, link string) bool {
+	for _, l := range links {
+		if l == link {
+			return true
+		}
+	}
+	return false
+}
+
+// linkMetaFetcher fetches the metadata of links with a fixed number of
+// workers, links that are already being fetched do not get fetched again
+// and everything fetched gets stored
+type linkMetaFetcher struct {
+	store   storage.LinkMetaStore
+	fetch   func(url string) (*linkmeta.Meta, error)
+	queue   chan string
+	pending map[string][]chan *storage.LinkMeta
+	// thumbnails makes previews with images point to the thumbnail endpoint
+	thumbnails bool
+	lock       sync.Mutex
+}
+
+func newLinkMetaFetcher(store storage.LinkMetaStore, workers int) *linkMetaFetcher {
+	f := &linkMetaFetcher{
+		store:   store,
+		fetch:   linkmeta.Fetch,
+		queue:   make(chan string, linkMetaQueueSize),
+		pending: map[string][]chan *storage.LinkMeta{},
+	}
+
+	for i := 0; i < workers; i++ {
+		go f.work()
+	}
+
+	return f
+}
+
+// cached returns the stored metadata of a link if it is fresh enough
+func (f *linkMetaFetcher) cached(link string) (*storage.LinkMeta, bool) {
+	meta, err := f.store.LinkMeta(link)
+	if err != nil {
+		return nil, false
+	}
+
+	ttl := linkMetaTTL
+	if meta.Meta == nil {
+		ttl = linkMetaFailedTTL
+	}
+	if time.Since(time.Unix(meta.Time, 0)) > ttl {
+		return nil, false
+	}
+
+	return meta, true
+}
+
+// get returns the metadata of a link, it waits for the link to get fetched
+// if it has to be. It returns nil when the queue is full.
+func (f *linkMetaFetcher) get(link string) *storage.LinkMeta {
+	if meta, ok := f.cached(link); ok {
+		return meta
+	}
+
+	ch := make(chan *storage.LinkMeta, 1)
+
+	f.lock.Lock()
+	waiting, fetching := f.pending[link]
+	f.pending[link] = append(waiting, ch)
+
+	if !fetching {
+		select {
+		case f.queue <- link:
+		default:
+			delete(f.pending, link)
+			f.lock.Unlock()
+			return nil
+		}
+	}
+	f.lock.Unlock()
+
+	return <-ch
+}
+
+func (f *linkMetaFetcher) work() {
+	for link := range f.queue {
+		meta := &storage.LinkMeta{
+			URL:  link,
+			Time: time.Now().Unix(),
+		}
+
+		m, err := f.fetch(link)
+		if err == nil && (m.Title != "" || m.Description != "" || m.ImageURL != "" || m.Type != "") {
+			meta.Meta = m
+			if f.thumbnails && (m.ImageURL != "" || m.Type == "image") {
+				m.ThumbnailURL = thumbnailPath(link)
+			}
+		}
+
+		err = f.store.SaveLinkMeta(meta)
+		if err != nil {
+			log.Println("[Link meta]", err)
+		}
+
+		f.lock.Lock()
+		waiting := f.pending[link]
+		delete(f.pending, link)
+		f.lock.Unlock()
+
+		for _, ch := range waiting {
+			ch <- meta
+		}
+	}
+}
+
+// linkMetaClient returns the client that fetches links, links get fetched
+// through the proxy when it is a socks5 proxy
+func linkMetaClient(cfg *config.Config) *http.Client {
+	if !cfg.Proxy.Enabled || strings.ToLower(cfg.Proxy.Protocol) != "socks5" {
+		return linkmeta.Client
+	}
+
+	dialer, err := proxyDialer(cfg.Proxy)
+	if err != nil {
+		log.Println("[Link meta]", err)
+		return linkmeta.Client
+	}
+
+	return linkmeta.NewClient(linkmeta.Options{
+		Dialer: dialer,
+	})
+}
+
+// oembedFetcher returns a fetcher that uses the configured oEmbed
+// providers before the built in ones, it returns an error if one of them
+// is invalid
+func oembedFetcher(cfg *config.Config, client *http.Client) (*linkmeta.Fetcher, error) {
+	var providers []linkmeta.Provider
+	for _, p := range cfg.LinkPreviews.OEmbed {
+		provider, err := linkmeta.NewProvider(p.Name, p.Endpoint, p.Schemes)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	return &linkmeta.Fetcher{
+		Client:    client,
+		Providers: append(providers, linkmeta.DefaultProviders...),
+	}, nil
+}
+
+func (d *Dispatch) pruneLinkMeta() {
+	if d.LinkMetaStore == nil {
+		return
+	}
+
+	err := d.LinkMetaStore.PruneLinkMeta(time.Now().Add(-linkMetaTTL).Unix())
+	if err != nil {
+		log.Println("[Link meta]", err)
+	}
+
+	if d.thumbnails != nil {
+		d.thumbnails.prune(time.Now().Add(-linkMetaTTL))
+	}
+}
+
+func (s *State) linkPreviews() bool {
+	return s.srv.linkMeta != nil && s.user.ClientSettings().LinkPreviews
+}
+
+// sendLinkMeta fetches the metadata of the links in a message and sends it
+// to the user if there is any
+func (s *State) sendLinkMeta(network, to, id, content string) {
+	if !s.linkPreviews() {
+		return
+	}
+
+	links := findLinks(content)
+	if len(links) == 0 {
+		return
+	}
+
+	go func() {
+		var metas []*linkmeta.Meta
+		for _, link := range links {
+			if meta := s.srv.linkMeta.get(link); meta != nil && meta.Meta != nil {
+				metas = append(metas, meta.Meta)
+			}
+		}
+
+		if len(metas) > 0 {
+			s.sendJSON("link_meta", LinkMeta{
+				Network: network,
+				To:      to,
+				ID:      id,
+				Meta:    metas,
+			})
+		}
+	}()
+}
+
+// storedLinkMeta returns the metadata that has already been fetched for the
+// links in messages, keyed by message ID
+func (s *State) storedLinkMeta(messages []storage.Message) map[string][]*linkmeta.Meta {
+	if !s.linkPreviews() {
+		return nil
+	}
+
+	var result map[string][]*linkmeta.Meta
+	for _, message := range messages {
+		for _, link := range findLinks(message.Content) {
+			meta, err := s.srv.LinkMetaStore.LinkMeta(link)
+			if err != nil || meta.Meta == nil {
+				continue
+			}
+
+			if result == nil {
+				result = map[string][]*linkmeta.Meta{}
+			}
+			result[message.ID] = append(result[message.ID], meta.Meta)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/memory"
+)
+
+func TestFindLinks(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"no links here", nil},
+		{"see https://example.com", []string{"https://example.com"}},
+		{"see https://example.com/a?b=c#d.", []string{"https://example.com/a?b=c#d"}},
+		{"(http://example.com/page)", []string{"http://example.com/page"}},
+		{"https://en.wikipedia.org/wiki/Go_(programming_language)", []string{"https://en.wikipedia.org/wiki/Go_(programming_language)"}},
+		{"\x02https://example.com\x02 bold", []string{"https://example.com"}},
+		{"<https://example.com>", []string{"https://example.com"}},
+		{"https://a.com https://a.com https://b.com", []string{"https://a.com", "https://b.com"}},
+		{"https://a.com https://b.com https://c.com https://d.com", []string{"https://a.com", "https://b.com", "https://c.com"}},
+		{"ftp://example.com https://", nil},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, findLinks(tc.input), tc.input)
+	}
+}
+
+func TestLinkMetaFetcher(t *testing.T) {
+	store := memory.New()
+	f := &linkMetaFetcher{
+		store:   store,
+		queue:   make(chan string, 8),
+		pending: map[string][]chan *storage.LinkMeta{},
+	}
+
+	var fetches int32
+	release := make(chan struct{})
+	f.fetch = func(url string) (*linkmeta.Meta, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		if url == "https://example.com/404" {
+			return nil, linkmeta.ErrContentType
+		}
+		return &linkmeta.Meta{URL: url, Title: "Example"}, nil
+	}
+	go f.work()
+
+	// Everyone waiting on the same link gets the result of a single fetch
+	var wg sync.WaitGroup
+	results := make([]*storage.LinkMeta, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			results[i] = f.get("https://example.com")
+			wg.Done()
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	for _, meta := range results {
+		require.NotNil(t, meta)
+		assert.Equal(t, "Example", meta.Meta.Title)
+	}
+
+	// Then it comes from the store
+	meta := f.get("https://example.com")
+	require.NotNil(t, meta)
+	assert.Equal(t, "Example", meta.Meta.Title)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	stored, err := store.LinkMeta("https://example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "Example", stored.Meta.Title)
+
+	// Failures get stored without metadata
+	meta = f.get("https://example.com/404")
+	require.NotNil(t, meta)
+	assert.Nil(t, meta.Meta)
+	f.get("https://example.com/404")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// Old metadata gets fetched again
+	require.Nil(t, store.SaveLinkMeta(&storage.LinkMeta{
+		URL:  "https://example.com/404",
+		Time: time.Now().Add(-2 * linkMetaFailedTTL).Unix(),
+	}))
+	f.get("https://example.com/404")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fetches))
+}
+
+func TestLinkMetaFetcherQueueFull(t *testing.T) {
+	f := &linkMetaFetcher{
+		store:   memory.New(),
+		queue:   make(chan string),
+		pending: map[string][]chan *storage.LinkMeta{},
+	}
+
+	assert.Nil(t, f.get("https://example.com"))
+	assert.Len(t, f.pending, 0)
+}
+
+func TestStoredLinkMeta(t *testing.T) {
+	db := memory.New()
+	srv := &Dispatch{LinkMetaStore: db}
+	srv.linkMeta = &linkMetaFetcher{store: db}
+	s := NewState(user, srv)
+
+	require.Nil(t, db.SaveLinkMeta(&storage.LinkMeta{
+		URL:  "https://example.com",
+		Meta: &linkmeta.Meta{URL: "https://example.com", Title: "Example"},
+		Time: time.Now().Unix(),
+	}))
+	require.Nil(t, db.SaveLinkMeta(&storage.LinkMeta{
+		URL:  "https://example.com/404",
+		Time: time.Now().Unix(),
+	}))
+
+	messages := []storage.Message{
+		{ID: "1", Content: "https://example.com and https://example.com/404"},
+		{ID: "2", Content: "https://example.com/unknown"},
+		{ID: "3", Content: "nothing"},
+	}
+
+	assert.Equal(t, map[string][]*linkmeta.Meta{
+		"1": {{URL: "https://example.com", Title: "Example"}},
+	}, s.storedLinkMeta(messages))
+
+	settings := user.ClientSettings()
+	defer user.SetClientSettings(user.ClientSettings())
+	settings.LinkPreviews = false
+	require.Nil(t, user.SetClientSettings(settings))
+	assert.Nil(t, s.storedLinkMeta(messages))
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+	"github.com/khlieng/dispatch/pkg/webpush"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// pushTTL is how long push services hold on to pushes for browsers that
+// are offline
+const pushTTL = 24 * time.Hour
+
+// loadVAPID returns the configured VAPID key, the one in the data directory
+// gets used if none is configured and is generated the first time
+func loadVAPID(cfg config.Push) (*webpush.VAPID, error) {
+	if cfg.VAPIDKey != "" {
+		return webpush.ParseVAPID(cfg.VAPIDKey)
+	}
+
+	// The push subscriptions are gone after a restart anyway when nothing
+	// gets stored, so a new key is fine
+	if storage.InMemory {
+		return webpush.GenerateVAPID()
+	}
+
+	key, err := ioutil.ReadFile(storage.Path.VAPIDKey())
+	if err == nil {
+		return webpush.ParseVAPID(strings.TrimSpace(string(key)))
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	vapid, err := webpush.GenerateVAPID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(storage.Path.VAPIDKey(), []byte(vapid.PrivateKey()), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return vapid, nil
+}
+
+// newPushClient returns nil if the VAPID key can not be loaded, the push
+// endpoints come from browsers so private addresses are off limits
+func newPushClient(cfg *config.Config) *webpush.Client {
+	vapid, err := loadVAPID(cfg.Push)
+	if err != nil {
+		log.Println("[Push]", err)
+		return nil
+	}
+
+	return &webpush.Client{
+		HTTP:    linkmeta.NewClient(linkmeta.Options{}),
+		VAPID:   vapid,
+		Subject: cfg.Push.Subject,
+	}
+}
+
+// vapidPublicKey returns the key browsers have to subscribe with, it is
+// empty when push is turned off
+func (d *Dispatch) vapidPublicKey() string {
+	if d.push == nil {
+		return ""
+	}
+	return d.push.VAPID.PublicKey()
+}
+
+// sendPush pushes a message to the browsers of the user, it only happens
+// when none of them have a websocket open
+func (s *State) sendPush(t string, msg *storage.Message) {
+	if s.srv.push == nil || s.numWS() > 0 {
+		return
+	}
+
+	payload, err := PushMessage{
+		Type:    t,
+		ID:      msg.ID,
+		Network: msg.Network,
+		To:      msg.To,
+		From:    msg.From,
+		Content: msg.Content,
+	}.MarshalJSON()
+	if err != nil {
+		log.Println("[Push]", err)
+		return
+	}
+
+	go func() {
+		subs, err := s.user.PushSubscriptions()
+		if err != nil {
+			log.Println("[Push]", err)
+			return
+		}
+
+		for _, sub := range subs {
+			err = s.srv.push.Send(&webpush.Subscription{
+				Endpoint: sub.Endpoint,
+				P256dh:   sub.P256dh,
+				Auth:     sub.Auth,
+			}, payload, webpush.Options{
+				TTL:     pushTTL,
+				Urgency: "high",
+			})
+
+			if err == webpush.ErrGone {
+				err = s.user.RemovePushSubscription(sub.Endpoint)
+			}
+			if err != nil {
+				log.Println("[Push]", err)
+			}
+		}
+	}()
+}
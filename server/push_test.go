@@ -0,0 +1,179 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/pkg/webpush"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pushRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// newPushService starts a stand-in for a push service, pushes to /gone get
+// told that the subscription is gone
+func newPushService(t *testing.T) (*httptest.Server, chan pushRequest) {
+	pushes := make(chan pushRequest, 8)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		pushes <- pushRequest{r.URL.Path, r.Header, body}
+
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, pushes
+}
+
+func pushSubscriptionJSON(t *testing.T, endpoint string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	auth := make([]byte, 16)
+	rand.Read(auth)
+
+	b, err := PushSubscription{
+		Endpoint: endpoint,
+		Keys: PushKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), key.X, key.Y)),
+			Auth:   base64.RawURLEncoding.EncodeToString(auth),
+		},
+	}.MarshalJSON()
+	require.Nil(t, err)
+	return b
+}
+
+func TestPush(t *testing.T) {
+	srv, pushes := newPushService(t)
+
+	vapid, err := webpush.GenerateVAPID()
+	require.Nil(t, err)
+
+	s := NewState(user, &Dispatch{
+		push: &webpush.Client{HTTP: srv.Client(), VAPID: vapid},
+	})
+	h := &wsHandler{state: s}
+	i := newIRCHandler(irc.NewClient(&irc.Config{Nick: "nick", Username: "user", Host: "host.com"}), "host.com", s)
+
+	h.pushSubscribe(pushSubscriptionJSON(t, srv.URL+"/sub"))
+	h.pushSubscribe(pushSubscriptionJSON(t, srv.URL+"/gone"))
+	defer h.pushUnsubscribe([]byte(`{"endpoint":"` + srv.URL + `/sub"}`))
+
+	// Endpoints have to be https
+	h.pushSubscribe(pushSubscriptionJSON(t, strings.Replace(srv.URL, "https", "http", 1)))
+	res := <-s.broadcast
+	assert.Equal(t, "push_subscribe_fail", res.Type)
+
+	subs, err := user.PushSubscriptions()
+	require.Nil(t, err)
+	assert.Len(t, subs, 2)
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"nick", "are you there?"},
+	})
+
+	paths := map[string]bool{}
+	for n := 0; n < 2; n++ {
+		select {
+		case push := <-pushes:
+			paths[push.path] = true
+			assert.Equal(t, "aes128gcm", push.header.Get("Content-Encoding"))
+			assert.True(t, strings.HasPrefix(push.header.Get("Authorization"), "vapid t="))
+			assert.NotEmpty(t, push.body)
+
+		case <-time.After(time.Second):
+			t.Fatal("push did not arrive")
+		}
+	}
+	assert.Equal(t, map[string]bool{"/sub": true, "/gone": true}, paths)
+
+	// The subscription that is gone gets removed
+	assert.Eventually(t, func() bool {
+		subs, err := user.PushSubscriptions()
+		return err == nil && len(subs) == 1 && subs[0].Endpoint == srv.URL+"/sub"
+	}, time.Second, 10*time.Millisecond)
+
+	// Muted private chats do not get pushed
+	require.Nil(t, user.SetHighlightRules(&storage.HighlightRules{
+		Nick:  true,
+		Muted: []storage.Tab{{Network: "host.com", Name: "someone"}},
+	}))
+	defer user.SetHighlightRules(storage.DefaultHighlightRules())
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"nick", "still there?"},
+	})
+
+	select {
+	case <-pushes:
+		t.Fatal("pushed a muted private chat")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Nothing gets pushed while a websocket is connected
+	s.lock.Lock()
+	s.ws["addr"] = nil
+	s.lock.Unlock()
+
+	i.dispatchMessage(&irc.Message{
+		Command: irc.PRIVMSG,
+		Sender:  "someone",
+		Params:  []string{"nick", "hello again"},
+	})
+
+	select {
+	case <-pushes:
+		t.Fatal("pushed while a websocket was connected")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestLoadVAPIDInMemory(t *testing.T) {
+	file, err := ioutil.TempFile("", "")
+	require.Nil(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	path := storage.Path
+	defer func() {
+		storage.Path = path
+		storage.InMemory = false
+	}()
+
+	// Nothing can be written below a file
+	storage.Initialize(filepath.Join(file.Name(), "data"), "", "")
+
+	_, err = loadVAPID(config.Push{})
+	assert.NotNil(t, err)
+
+	storage.InMemory = true
+	vapid, err := loadVAPID(config.Push{})
+	require.Nil(t, err)
+	assert.NotEmpty(t, vapid.PublicKey())
+}
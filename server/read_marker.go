@@ -0,0 +1,27 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// setReadMarker moves a read marker forward and tells every open tab of the
+// user about it so they can clear their unread counts, markers that are set
+// in dispatch get synced to the network when sync is true
+func (s *State) setReadMarker(marker *storage.ReadMarker, sync bool) {
+	moved, err := s.user.SetReadMarker(marker)
+	if err != nil {
+		log.Println("[Read marker]", err)
+	}
+	if !moved {
+		return
+	}
+
+	s.sendJSON("read_marker", ReadMarker{*marker})
+
+	if i, ok := s.client(marker.Network); ok && sync {
+		i.MarkRead(marker.Channel, time.Unix(marker.Time, 0))
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMarker(t *testing.T) {
+	s := NewState(user, &Dispatch{})
+	h := &wsHandler{state: s}
+	i := newIRCHandler(irc.NewClient(&irc.Config{Nick: "nick", Username: "user", Host: "host.com"}), "host.com", s)
+
+	msg := &storage.Message{
+		ID:      betterguid.New(),
+		Network: "host.com",
+		From:    "someone",
+		To:      "#markers",
+		Content: "hi",
+		Time:    1000,
+	}
+	require.Nil(t, user.LogMessage(msg))
+
+	broadcast := func() (WSResponse, bool) {
+		select {
+		case res := <-s.broadcast:
+			return res, true
+		case <-time.After(100 * time.Millisecond):
+			return WSResponse{}, false
+		}
+	}
+
+	// Markers can only be moved to messages that exist
+	h.readMarker([]byte(`{"network":"host.com","channel":"#markers","id":"missing"}`))
+	_, ok := broadcast()
+	assert.False(t, ok)
+
+	h.readMarker([]byte(`{"network":"host.com","channel":"#markers","id":"` + msg.ID + `"}`))
+	res, ok := broadcast()
+	require.True(t, ok)
+	assert.Equal(t, "read_marker", res.Type)
+	assert.Equal(t, ReadMarker{storage.ReadMarker{
+		Network: "host.com",
+		Channel: "#markers",
+		ID:      msg.ID,
+		Time:    1000,
+	}}, res.Data)
+
+	counts, err := user.UnreadCounts([]storage.Tab{{Network: "host.com", Name: "#markers"}})
+	require.Nil(t, err)
+	assert.Equal(t, 0, counts[0].Messages)
+
+	// Markers from the network only move forward
+	i.dispatchMessage(irc.ParseMessage("MARKREAD #markers timestamp=1970-01-01T00:10:00.000Z"))
+	_, ok = broadcast()
+	assert.False(t, ok)
+
+	i.dispatchMessage(irc.ParseMessage("MARKREAD #markers timestamp=2020-01-01T00:00:00.000Z"))
+	res, ok = broadcast()
+	require.True(t, ok)
+	assert.Equal(t, ReadMarker{storage.ReadMarker{
+		Network: "host.com",
+		Channel: "#markers",
+		Time:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}}, res.Data)
+
+	// No ID marks everything as read, the last message is already covered
+	h.readMarker([]byte(`{"network":"host.com","channel":"#markers"}`))
+	_, ok = broadcast()
+	assert.False(t, ok)
+
+	last := &storage.Message{
+		ID:      betterguid.New(),
+		Network: "host.com",
+		From:    "someone",
+		To:      "#markers",
+		Content: "later",
+		Time:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	require.Nil(t, user.LogMessage(last))
+
+	h.readMarker([]byte(`{"network":"host.com","channel":"#markers"}`))
+	res, ok = broadcast()
+	require.True(t, ok)
+	assert.Equal(t, ReadMarker{storage.ReadMarker{
+		Network: "host.com",
+		Channel: "#markers",
+		ID:      last.ID,
+		Time:    last.Time,
+	}}, res.Data)
+
+	// Channels without messages have nothing to mark
+	h.readMarker([]byte(`{"network":"host.com","channel":"#empty"}`))
+	_, ok = broadcast()
+	assert.False(t, ok)
+}
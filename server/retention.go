@@ -0,0 +1,60 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+const pruneInterval = 24 * time.Hour
+
+func (d *Dispatch) runPruner() {
+	// Give the users some time to load before the first run
+	time.Sleep(time.Minute)
+
+	for {
+		d.pruneMessages()
+		d.pruneLinkMeta()
+		time.Sleep(pruneInterval)
+	}
+}
+
+// pruneMessages deletes the messages of all users that fall outside the
+// configured retention limits
+func (d *Dispatch) pruneMessages() {
+	retention := d.Config().Retention
+	if !retention.Enabled() {
+		return
+	}
+
+	now := time.Now()
+
+	for _, state := range d.states.list() {
+		networks, err := state.user.Networks()
+		if err != nil {
+			log.Println("[Retention]", state.user.ID, err)
+			continue
+		}
+
+		// Retention gets configured per host, the messages are stored per network ID
+		hosts := map[string]string{}
+		for _, network := range networks {
+			hosts[network.ID] = network.Host
+		}
+
+		n, err := state.user.PruneMessages(func(network, channel string) (int64, int) {
+			days, messages := retention.Limits(hosts[network], channel)
+
+			var before int64
+			if days > 0 {
+				before = now.AddDate(0, 0, -days).Unix()
+			}
+			return before, messages
+		})
+		if err != nil {
+			log.Println("[Retention]", state.user.ID, err)
+		}
+		if n > 0 {
+			log.Printf("[Retention] Deleted %d messages of user %d", n, state.user.ID)
+		}
+	}
+}
@@ -14,20 +14,25 @@ import (
 	"github.com/khlieng/dispatch/pkg/https"
 	"github.com/khlieng/dispatch/pkg/ident"
 	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/pkg/webpush"
 	"github.com/khlieng/dispatch/storage"
 )
 
 var channelIndexes = storage.NewChannelIndexManager()
 
 type Dispatch struct {
-	Store        storage.Store
-	SessionStore storage.SessionStore
-
-	cfg      *config.Config
-	upgrader websocket.Upgrader
-	states   *stateStore
-	identd   *ident.Server
-	lock     sync.Mutex
+	Store         storage.Store
+	SessionStore  storage.SessionStore
+	LinkMetaStore storage.LinkMetaStore
+
+	cfg        *config.Config
+	upgrader   websocket.Upgrader
+	states     *stateStore
+	identd     *ident.Server
+	linkMeta   *linkMetaFetcher
+	thumbnails *thumbnailCache
+	push       *webpush.Client
+	lock       sync.Mutex
 }
 
 func New(cfg *config.Config) *Dispatch {
@@ -71,10 +76,35 @@ func (d *Dispatch) Run() {
 
 	session.CookieName = "sid"
 
+	if d.LinkMetaStore != nil {
+		client := linkMetaClient(cfg)
+
+		fetcher, err := oembedFetcher(cfg, client)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		d.linkMeta = newLinkMetaFetcher(d.LinkMetaStore, linkMetaWorkers)
+		d.linkMeta.fetch = fetcher.Fetch
+
+		// Thumbnails are stored in the data directory
+		if cfg.LinkPreviews.Thumbnails && storage.InMemory {
+			log.Println("[Link previews] Thumbnails are turned off when keeping all data in memory")
+		} else if cfg.LinkPreviews.Thumbnails {
+			d.linkMeta.thumbnails = true
+			d.thumbnails = newThumbnailCache(storage.Path.Thumbnails(), client, cfg.LinkPreviews.ThumbnailSize)
+		}
+	}
+
+	if cfg.Push.Enabled {
+		d.push = newPushClient(cfg)
+	}
+
 	d.states = newStateStore(d.SessionStore)
 	go d.states.run()
 
 	d.loadUsers()
+	go d.runPruner()
 	d.initFileServer()
 	d.serveHTTP()
 }
@@ -109,10 +139,13 @@ func (d *Dispatch) loadUser(user *storage.User) {
 
 	for _, network := range networks {
 		i := connectIRC(network, state, user.GetLastIP())
+		if i == nil {
+			continue
+		}
 
 		var joining []string
 		for _, channel := range channels {
-			if channel.Network == network.Host {
+			if channel.Network == network.ID {
 				network.AddChannel(network.NewChannel(channel.Name))
 				joining = append(joining, channel.Name)
 			}
@@ -154,7 +187,7 @@ func (d *Dispatch) serveHTTP() {
 }
 
 func (d *Dispatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
+	if r.Method != "GET" && !strings.HasPrefix(r.URL.Path, "/api/") {
 		fail(w, http.StatusNotFound)
 		return
 	}
@@ -217,6 +250,38 @@ func (d *Dispatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		} else {
 			fail(w, http.StatusNotFound)
 		}
+	} else if r.URL.Path == "/export" {
+		state := d.handleAuth(w, r, false, false)
+		if state == nil {
+			fail(w, http.StatusUnauthorized)
+			return
+		}
+
+		d.serveExport(w, r, state)
+	} else if r.URL.Path == "/mentions" {
+		state := d.handleAuth(w, r, false, false)
+		if state == nil {
+			fail(w, http.StatusUnauthorized)
+			return
+		}
+
+		d.serveMentions(w, r, state)
+	} else if strings.HasPrefix(r.URL.Path, "/api/") {
+		state, token := d.apiAuth(w, r)
+		if state == nil {
+			apiError(w, r, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		d.serveAPI(w, r, state, token)
+	} else if r.URL.Path == "/thumbnail" {
+		state := d.handleAuth(w, r, false, false)
+		if state == nil {
+			fail(w, http.StatusUnauthorized)
+			return
+		}
+
+		d.serveThumbnail(w, r)
 	} else {
 		d.serveFiles(w, r)
 	}
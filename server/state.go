@@ -1,13 +1,16 @@
 package server
 
 import (
+	"errors"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/pkg/session"
 	"github.com/khlieng/dispatch/storage"
+	"github.com/kjk/betterguid"
 )
 
 const (
@@ -47,32 +50,59 @@ func NewState(user *storage.User, srv *Dispatch) *State {
 	}
 }
 
-func (s *State) network(host string) (*storage.Network, bool) {
+func (s *State) network(id string) (*storage.Network, bool) {
 	s.lock.Lock()
-	n, ok := s.networks[host]
+	n, ok := s.networks[id]
 	s.lock.Unlock()
 
 	return n, ok
 }
 
-func (s *State) client(host string) (*irc.Client, bool) {
-	if network, ok := s.network(host); ok {
+func (s *State) client(id string) (*irc.Client, bool) {
+	if network, ok := s.network(id); ok {
 		return network.Client(), true
 	}
 	return nil, false
 }
 
-func (s *State) setNetwork(host string, network *storage.Network) {
+// publicNetworks returns copies of the networks of the user without the
+// credentials that never get sent to clients
+func (s *State) publicNetworks() []*storage.Network {
 	s.lock.Lock()
-	s.networks[host] = network
+	networks := make([]*storage.Network, 0, len(s.networks))
+	for _, network := range s.networks {
+		network = network.Copy()
+		network.ServerPassword = ""
+		network.Password = ""
+		network.Username = ""
+		network.Realname = ""
+
+		networks = append(networks, network)
+	}
+	s.lock.Unlock()
+
+	return networks
+}
+
+// addNetwork adds network unless the ID is already in use, checking and
+// adding it happens under the same lock so only one of several connects
+// with the same ID gets it
+func (s *State) addNetwork(id string, network *storage.Network) bool {
+	s.lock.Lock()
+	if _, ok := s.networks[id]; ok {
+		s.lock.Unlock()
+		return false
+	}
+	s.networks[id] = network
 	s.lock.Unlock()
 
 	s.reset <- 0
+	return true
 }
 
-func (s *State) deleteNetwork(host string) {
+func (s *State) deleteNetwork(id string) {
 	s.lock.Lock()
-	delete(s.networks, host)
+	delete(s.networks, id)
 	s.lock.Unlock()
 
 	s.resetExpirationIfEmpty()
@@ -133,39 +163,173 @@ func (s *State) sendJSON(t string, v interface{}) {
 	s.broadcast <- WSResponse{t, v}
 }
 
+// messages returns the page of messages logged in channel before fromID,
+// the last page when fromID is empty
+func (s *State) messages(network, channel string, count int, fromID string) (*Messages, error) {
+	messages, hasMore, err := s.user.Messages(network, channel, count, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Messages{
+		Network:    network,
+		To:         channel,
+		Messages:   messages,
+		LinkMeta:   s.storedLinkMeta(messages),
+		Highlights: s.highlights(network, messages),
+	}
+
+	if hasMore && len(messages) > 0 {
+		res.Next = messages[0].ID
+	}
+
+	return res, nil
+}
+
 func (s *State) sendLastMessages(network, channel string, count int) {
-	messages, hasMore, err := s.user.LastMessages(network, channel, count)
-	if err == nil && len(messages) > 0 {
-		res := Messages{
-			Network:  network,
-			To:       channel,
-			Messages: messages,
+	res, err := s.messages(network, channel, count, "")
+	if err == nil && len(res.Messages) > 0 {
+		s.sendJSON("messages", *res)
+	}
+}
+
+func (s *State) sendMessages(network, channel string, count int, fromID string) {
+	res, err := s.messages(network, channel, count, fromID)
+	if err == nil && len(res.Messages) > 0 {
+		res.Prepend = true
+		s.sendJSON("messages", *res)
+	}
+}
+
+var (
+	errInvalidNetworkID = errors.New("Invalid network ID")
+	errNetworkIDInUse   = errors.New("This network ID is already in use")
+)
+
+// connect checks network against the configured presets and the network
+// allowlist before connecting to it, the error says why it was refused
+func (s *State) connect(network *storage.Network, ip []byte) error {
+	// The client picks the ID so it can refer to the network right away,
+	// one gets generated for clients that leave it out
+	if network.ID == "" {
+		network.ID = storage.NewNetworkID()
+	} else if !isValidNetworkID(network.ID) {
+		return errInvalidNetworkID
+	}
+
+	network.Host = strings.ToLower(network.Host)
+	for i := range network.Servers {
+		network.Servers[i].Host = strings.ToLower(network.Servers[i].Host)
+	}
+
+	cfg := s.srv.Config()
+	if preset := cfg.Preset(network.Host); preset != nil {
+		if preset.ReadOnly {
+			network.Port = preset.Port
+			network.TLS = preset.TLS
+			network.Servers = nil
+		}
+
+		if preset.RequireSASL && (network.Account == "" || network.Password == "") &&
+			s.user.GetCertificate() == nil {
+			return errors.New("This network requires a SASL account and password")
 		}
+	} else if cfg.NetworkAllowlist {
+		return errors.New("Connecting to this network is not allowed")
+	}
 
-		if hasMore {
-			res.Next = messages[0].ID
+	if cfg.NetworkAllowlist {
+		for _, server := range network.Servers {
+			if cfg.Preset(server.Host) == nil {
+				return errors.New("Connecting to " + server.Host + " is not allowed")
+			}
 		}
+	}
 
-		s.sendJSON("messages", res)
+	// The ID gets taken when the network gets added, it is in use if
+	// another connect got there first
+	if connectIRC(network, s, ip) == nil {
+		return errNetworkIDInUse
 	}
+
+	go network.Save()
+	return nil
 }
 
-func (s *State) sendMessages(network, channel string, count int, fromID string) {
-	messages, hasMore, err := s.user.Messages(network, channel, count, fromID)
-	if err == nil && len(messages) > 0 {
-		res := Messages{
-			Network:  network,
-			To:       channel,
-			Messages: messages,
-			Prepend:  true,
+func (s *State) part(network string, channels []string) {
+	if i, ok := s.client(network); ok {
+		i.Part(channels...)
+	}
+
+	go func() {
+		if n, ok := s.network(network); ok {
+			n.RemoveChannels(channels...)
 		}
 
-		if hasMore {
-			res.Next = messages[0].ID
+		for _, channel := range channels {
+			s.user.RemoveChannel(network, channel)
 		}
+	}()
+}
 
-		s.sendJSON("messages", res)
+// sendMessage sends a message to target and logs it, it returns the ID of
+// the logged message and false if there is no such network
+func (s *State) sendMessage(network, target, content string) (string, bool) {
+	i, ok := s.client(network)
+	if !ok {
+		return "", false
 	}
+
+	i.Privmsg(target, content)
+
+	id := betterguid.New()
+
+	go s.user.LogMessage(&storage.Message{
+		ID:      id,
+		Network: network,
+		From:    i.GetNick(),
+		To:      target,
+		Content: content,
+	})
+	// Sending a message means everything before it has been read
+	go s.setReadMarker(&storage.ReadMarker{
+		Network: network,
+		Channel: target,
+		ID:      id,
+		Time:    time.Now().Unix(),
+	}, true)
+
+	s.sendLinkMeta(network, target, id, content)
+
+	return id, true
+}
+
+func (s *State) search(query MessageSearch) MessageSearchResult {
+	result := MessageSearchResult{
+		MessageSearch: query,
+	}
+
+	res, err := s.user.Search(storage.SearchQuery(query))
+	if err != nil {
+		log.Println(err)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Total = res.Total
+	result.Results = make([]MessageSearchHit, len(res.Hits))
+
+	for i, hit := range res.Hits {
+		result.Results[i] = MessageSearchHit{
+			ID:        hit.ID,
+			Network:   hit.Network,
+			Channel:   hit.Channel,
+			Fragments: hit.Fragments,
+			Message:   hit.Message,
+		}
+	}
+
+	return result
 }
 
 func (s *State) resetExpirationIfEmpty() {
@@ -304,6 +468,16 @@ func (s *stateStore) get(id uint64) *State {
 	return state
 }
 
+func (s *stateStore) list() []*State {
+	s.lock.Lock()
+	states := make([]*State, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state)
+	}
+	s.lock.Unlock()
+	return states
+}
+
 func (s *stateStore) set(state *State) {
 	s.lock.Lock()
 	s.states[state.user.ID] = state
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/khlieng/dispatch/config"
+	"github.com/khlieng/dispatch/pkg/irc"
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectNetworkID(t *testing.T) {
+	s := NewState(user, &Dispatch{cfg: &config.Config{}})
+	h := &wsHandler{state: s, addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}}
+
+	network := user.NewNetwork(&storage.Network{ID: "taken", Host: "taken.com"},
+		irc.NewClient(&irc.Config{Nick: "nick", Username: "user", Host: "taken.com"}))
+	s.networks["taken"] = network
+
+	err := s.connect(&storage.Network{ID: "taken", Host: "other.com"}, nil)
+	assert.Equal(t, errNetworkIDInUse, err)
+
+	err = s.connect(&storage.Network{ID: "not valid", Host: "other.com"}, nil)
+	assert.Equal(t, errInvalidNetworkID, err)
+
+	// The client hears about it under the ID it picked
+	h.connect([]byte(`{"id":"taken","host":"other.com","nick":"nick"}`))
+	res := <-s.broadcast
+	assert.Equal(t, "connection_update", res.Type)
+	assert.Equal(t, "taken", res.Data.(ConnectionUpdate).Network)
+	assert.Equal(t, errNetworkIDInUse.Error(), res.Data.(ConnectionUpdate).Error)
+
+	n, ok := s.network("taken")
+	assert.True(t, ok)
+	assert.Equal(t, network, n)
+	assert.Equal(t, "taken.com", n.Host)
+	assert.Len(t, s.networks, 1)
+}
+
+func TestConnect(t *testing.T) {
+	// The user gets a store of its own to keep the networks out of the
+	// other tests, nothing of it has to be on disk
+	storage.InMemory = true
+	defer func() { storage.InMemory = false }()
+
+	u, err := storage.NewUser(memory.New())
+	require.Nil(t, err)
+
+	cfg := &config.Config{
+		Networks: []config.Preset{
+			{Host: "preset.test", Port: "6697", TLS: true, ReadOnly: true},
+			{Host: "sasl.test", RequireSASL: true},
+			{Host: "backup.test"},
+		},
+	}
+	s := NewState(u, &Dispatch{cfg: cfg})
+	defer s.kill()
+
+	connect := func(network *storage.Network) error {
+		err := s.connect(network, nil)
+		if err == nil {
+			<-s.reset
+		}
+		return err
+	}
+
+	// Networks without an ID get one
+	network := &storage.Network{Host: "Other.test", Port: "6667"}
+	require.Nil(t, connect(network))
+	assert.NotEmpty(t, network.ID)
+	assert.Equal(t, "other.test", network.Host)
+	_, ok := s.network(network.ID)
+	assert.True(t, ok)
+
+	// Read only presets decide where to connect
+	network = &storage.Network{
+		ID:      "preset",
+		Host:    "preset.test",
+		Port:    "1234",
+		Servers: []storage.Server{{Host: "elsewhere.test"}},
+	}
+	require.Nil(t, connect(network))
+	assert.Equal(t, "6697", network.Port)
+	assert.True(t, network.TLS)
+	assert.Nil(t, network.Servers)
+
+	err = connect(&storage.Network{ID: "sasl", Host: "sasl.test"})
+	assert.EqualError(t, err, "This network requires a SASL account and password")
+	_, ok = s.network("sasl")
+	assert.False(t, ok)
+	require.Nil(t, connect(&storage.Network{ID: "sasl", Host: "sasl.test", Account: "acc", Password: "pass"}))
+
+	cfg.NetworkAllowlist = true
+
+	err = connect(&storage.Network{ID: "unknown", Host: "unknown.test"})
+	assert.EqualError(t, err, "Connecting to this network is not allowed")
+
+	err = connect(&storage.Network{
+		ID:      "failover",
+		Host:    "backup.test",
+		Servers: []storage.Server{{Host: "unknown.test"}},
+	})
+	assert.EqualError(t, err, "Connecting to unknown.test is not allowed")
+
+	require.Nil(t, connect(&storage.Network{
+		ID:      "failover",
+		Host:    "backup.test",
+		Servers: []storage.Server{{Host: "Preset.test"}},
+	}))
+
+	assert.Equal(t, 4, s.numIRC())
+}
+
+func TestConnectSameIDConcurrently(t *testing.T) {
+	storage.InMemory = true
+	defer func() { storage.InMemory = false }()
+
+	u, err := storage.NewUser(memory.New())
+	require.Nil(t, err)
+
+	s := NewState(u, &Dispatch{cfg: &config.Config{}})
+	defer s.kill()
+
+	const n = 10
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- s.connect(&storage.Network{ID: "same", Host: "same.test"}, nil)
+		}()
+	}
+
+	// Only the connect that got the ID sends on reset, the rest have to be
+	// able to return while it is waiting
+	<-s.reset
+
+	connected := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			connected++
+		} else {
+			assert.Equal(t, errNetworkIDInUse, err)
+		}
+	}
+	assert.Equal(t, 1, connected)
+	assert.Equal(t, 1, s.numIRC())
+}
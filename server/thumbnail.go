@@ -0,0 +1,220 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+)
+
+const (
+	defaultThumbnailSize = 400
+	// Decoding images takes a lot of memory, only a few get made at a time
+	thumbnailWorkers   = 2
+	thumbnailQueueSize = 64
+)
+
+func thumbnailPath(link string) string {
+	return "/thumbnail?url=" + url.QueryEscape(link)
+}
+
+// thumbnailCache makes thumbnails of the images in link previews with a
+// fixed number of workers and keeps them on disk, images that could not be
+// fetched are not tried again for a while
+type thumbnailCache struct {
+	dir     string
+	size    int
+	fetch   func(url string, size int) ([]byte, string, error)
+	queue   chan string
+	pending map[string]chan struct{}
+	failed  map[string]time.Time
+	lock    sync.Mutex
+}
+
+func newThumbnailCache(dir string, client *http.Client, size int) *thumbnailCache {
+	if size <= 0 {
+		size = defaultThumbnailSize
+	}
+
+	c := &thumbnailCache{
+		dir:  dir,
+		size: size,
+		fetch: func(url string, size int) ([]byte, string, error) {
+			return linkmeta.Thumbnail(client, url, size)
+		},
+		queue:   make(chan string, thumbnailQueueSize),
+		pending: map[string]chan struct{}{},
+		failed:  map[string]time.Time{},
+	}
+
+	for i := 0; i < thumbnailWorkers; i++ {
+		go c.work()
+	}
+
+	return c
+}
+
+var thumbnailExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+func (c *thumbnailCache) file(imageURL string) string {
+	hash := sha256.Sum256([]byte(imageURL))
+	name := hex.EncodeToString(hash[:])
+
+	for _, ext := range thumbnailExtensions {
+		if _, err := os.Stat(filepath.Join(c.dir, name+ext)); err == nil {
+			return filepath.Join(c.dir, name+ext)
+		}
+	}
+	return ""
+}
+
+// get returns the path to the thumbnail of imageURL, it waits for it to
+// get made if it is not cached. It returns an empty string if there is no
+// thumbnail or the queue is full.
+func (c *thumbnailCache) get(imageURL string) string {
+	if file := c.file(imageURL); file != "" {
+		return file
+	}
+
+	c.lock.Lock()
+	if t, ok := c.failed[imageURL]; ok && time.Since(t) < linkMetaFailedTTL {
+		c.lock.Unlock()
+		return ""
+	}
+	done, ok := c.pending[imageURL]
+	if !ok {
+		select {
+		case c.queue <- imageURL:
+			done = make(chan struct{})
+			c.pending[imageURL] = done
+		default:
+			c.lock.Unlock()
+			return ""
+		}
+	}
+	c.lock.Unlock()
+
+	<-done
+	return c.file(imageURL)
+}
+
+func (c *thumbnailCache) work() {
+	for imageURL := range c.queue {
+		_, err := c.make(imageURL)
+		if err != nil {
+			log.Println("[Thumbnail]", err)
+		}
+
+		c.lock.Lock()
+		if err != nil {
+			c.failed[imageURL] = time.Now()
+		}
+		done := c.pending[imageURL]
+		delete(c.pending, imageURL)
+		c.lock.Unlock()
+		close(done)
+	}
+}
+
+func (c *thumbnailCache) make(imageURL string) (string, error) {
+	data, contentType, err := c.fetch(imageURL, c.size)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(imageURL))
+	file := filepath.Join(c.dir, hex.EncodeToString(hash[:])+thumbnailExtensions[contentType])
+
+	// Write it somewhere else first so nobody gets served half a thumbnail
+	tmp, err := ioutil.TempFile(c.dir, "tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return file, os.Rename(tmp.Name(), file)
+}
+
+// prune removes the thumbnails that have not been made since before
+func (c *thumbnailCache) prune(before time.Time) {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		if f.ModTime().Before(before) {
+			os.Remove(filepath.Join(c.dir, f.Name()))
+		}
+	}
+
+	c.lock.Lock()
+	for imageURL, t := range c.failed {
+		if time.Since(t) > linkMetaFailedTTL {
+			delete(c.failed, imageURL)
+		}
+	}
+	c.lock.Unlock()
+}
+
+// serveThumbnail serves the thumbnail of a link preview, only links that
+// have been previewed have thumbnails so this can not be used to fetch
+// anything else
+func (d *Dispatch) serveThumbnail(w http.ResponseWriter, r *http.Request) {
+	if d.thumbnails == nil {
+		fail(w, http.StatusNotFound)
+		return
+	}
+
+	link := r.URL.Query().Get("url")
+	meta, err := d.LinkMetaStore.LinkMeta(link)
+	if err != nil || meta.Meta == nil || meta.Meta.ThumbnailURL == "" {
+		fail(w, http.StatusNotFound)
+		return
+	}
+
+	imageURL := link
+	if meta.Meta.Type != "image" {
+		base, err := url.Parse(link)
+		if err != nil {
+			fail(w, http.StatusNotFound)
+			return
+		}
+		u, err := base.Parse(meta.Meta.ImageURL)
+		if err != nil {
+			fail(w, http.StatusNotFound)
+			return
+		}
+		imageURL = u.String()
+	}
+
+	file := d.thumbnails.get(imageURL)
+	if file == "" {
+		fail(w, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=604800")
+	http.ServeFile(w, r, file)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThumbnailCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var fetches int32
+	c := newThumbnailCache(dir, nil, 0)
+	c.fetch = func(url string, size int) ([]byte, string, error) {
+		atomic.AddInt32(&fetches, 1)
+		assert.Equal(t, defaultThumbnailSize, size)
+		if url == "https://example.com/broken.png" {
+			return nil, "", errors.New("broken")
+		}
+		return []byte("thumbnail"), "image/jpeg", nil
+	}
+
+	file := c.get("https://example.com/cat.png")
+	require.NotEqual(t, "", file)
+	assert.Equal(t, ".jpg", filepath.Ext(file))
+	data, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Equal(t, "thumbnail", string(data))
+
+	// It comes from disk the second time
+	assert.Equal(t, file, c.get("https://example.com/cat.png"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Failures do not get retried right away
+	assert.Equal(t, "", c.get("https://example.com/broken.png"))
+	assert.Equal(t, "", c.get("https://example.com/broken.png"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	c.prune(time.Now().Add(time.Minute))
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestThumbnailWorkers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var running, max int32
+	c := newThumbnailCache(dir, nil, 0)
+	c.fetch = func(url string, size int) ([]byte, string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return []byte("thumbnail"), "image/png", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NotEqual(t, "", c.get("https://example.com/"+strconv.Itoa(i)+".png"))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, atomic.LoadInt32(&max) <= thumbnailWorkers)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gorilla/websocket"
+	"github.com/khlieng/dispatch/pkg/webpush"
 	"github.com/khlieng/dispatch/storage"
 )
 
@@ -86,20 +87,20 @@ func (h *wsHandler) sendData(r *http.Request) {
 	h.state.lock.Lock()
 	for _, network := range h.state.networks {
 		for _, channel := range network.ChannelNames() {
-			if network.Host == tab.Network && channel == tab.Name {
+			if network.ID == tab.Network && channel == tab.Name {
 				// Userlist and messages for this channel gets embedded in the index page
 				continue
 			}
 
 			if users := network.Client().ChannelUsers(channel); len(users) > 0 {
 				h.state.sendJSON("users", Userlist{
-					Network: network.Host,
+					Network: network.ID,
 					Channel: channel,
 					Users:   users,
 				})
 			}
 
-			h.state.sendLastMessages(network.Host, channel, 50)
+			h.state.sendLastMessages(network.ID, channel, 50)
 		}
 
 	}
@@ -123,17 +124,22 @@ func (h *wsHandler) connect(b []byte) {
 	var network storage.Network
 	network.UnmarshalJSON(b)
 
-	network.Host = strings.ToLower(network.Host)
+	err := h.state.connect(&network, addrToIPBytes(h.addr))
+	if err != nil {
+		h.connectFailed(&network, err.Error())
+		return
+	}
 
-	if _, ok := h.state.network(network.Host); !ok {
-		log.Println(h.addr, "[IRC] Add server", network.Host)
+	log.Println(h.addr, "[IRC] Add server", network.Host)
+}
 
-		connectIRC(&network, h.state, addrToIPBytes(h.addr))
+func (h *wsHandler) connectFailed(network *storage.Network, reason string) {
+	log.Println(h.addr, "[IRC] Refused to add server", network.Host+":", reason)
 
-		go network.Save()
-	} else {
-		log.Println(h.addr, "[IRC]", network.Host, "already added")
-	}
+	h.state.sendJSON("connection_update", ConnectionUpdate{
+		Network: network.ID,
+		Error:   reason,
+	})
 }
 
 func (h *wsHandler) reconnect(b []byte) {
@@ -141,7 +147,7 @@ func (h *wsHandler) reconnect(b []byte) {
 	data.UnmarshalJSON(b)
 
 	if i, ok := h.state.client(data.Network); ok && !i.Connected() {
-		if i.Config.TLS {
+		if i.Config.TLSConfig != nil {
 			i.Config.TLSConfig.InsecureSkipVerify = data.SkipVerify
 		}
 		i.Reconnect()
@@ -161,19 +167,7 @@ func (h *wsHandler) part(b []byte) {
 	var data Part
 	data.UnmarshalJSON(b)
 
-	if i, ok := h.state.client(data.Network); ok {
-		i.Part(data.Channels...)
-	}
-
-	go func() {
-		if network, ok := h.state.network(data.Network); ok {
-			network.RemoveChannels(data.Channels...)
-		}
-
-		for _, channel := range data.Channels {
-			h.state.user.RemoveChannel(data.Network, channel)
-		}
-	}()
+	h.state.part(data.Network, data.Channels)
 }
 
 func (h *wsHandler) quit(b []byte) {
@@ -193,16 +187,7 @@ func (h *wsHandler) message(b []byte) {
 	var data Message
 	data.UnmarshalJSON(b)
 
-	if i, ok := h.state.client(data.Network); ok {
-		i.Privmsg(data.To, data.Content)
-
-		go h.state.user.LogMessage(&storage.Message{
-			Network: data.Network,
-			From:    i.GetNick(),
-			To:      data.To,
-			Content: data.Content,
-		})
-	}
+	h.state.sendMessage(data.Network, data.To, data.Content)
 }
 
 func (h *wsHandler) nick(b []byte) {
@@ -287,6 +272,15 @@ func (h *wsHandler) search(b []byte) {
 	}()
 }
 
+func (h *wsHandler) messageSearch(b []byte) {
+	go func() {
+		var data MessageSearch
+		data.UnmarshalJSON(b)
+
+		h.state.sendJSON("message_search", h.state.search(data))
+	}()
+}
+
 func (h *wsHandler) cert(b []byte) {
 	var data ClientCert
 	data.UnmarshalJSON(b)
@@ -330,7 +324,12 @@ func (h *wsHandler) channelSearch(b []byte) {
 	var data ChannelSearch
 	data.UnmarshalJSON(b)
 
-	index, needsUpdate := channelIndexes.Get(data.Network)
+	network, ok := h.state.network(data.Network)
+	if !ok {
+		return
+	}
+
+	index, needsUpdate := channelIndexes.Get(network.Host)
 	if index != nil {
 		n := 10
 		if data.Start > 0 {
@@ -343,9 +342,9 @@ func (h *wsHandler) channelSearch(b []byte) {
 		})
 	}
 
-	if i, ok := h.state.client(data.Network); ok && needsUpdate {
+	if needsUpdate {
 		h.state.Set("update_chanlist_"+data.Network, true)
-		i.List()
+		network.Client().List()
 	}
 }
 
@@ -364,6 +363,163 @@ func (h *wsHandler) closeDM(b []byte) {
 	h.state.user.RemoveOpenDM(data.Network, data.Name)
 }
 
+func (h *wsHandler) setHighlights(b []byte) {
+	var data HighlightRules
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.SetHighlightRules(&data.HighlightRules)
+	if err != nil {
+		h.state.sendJSON("highlights_fail", Error{Message: err.Error()})
+		return
+	}
+
+	h.state.sendJSON("highlights", data)
+}
+
+// readMarker moves the read marker of a channel to the message with the
+// given ID, or past every message when there is no ID
+func (h *wsHandler) readMarker(b []byte) {
+	var data ReadMarker
+	data.UnmarshalJSON(b)
+
+	// Markers without an ID mark everything in the channel as read
+	var messages []storage.Message
+	var err error
+	marker := &data.ReadMarker
+	if marker.ID == "" {
+		messages, _, err = h.state.user.LastMessages(marker.Network, marker.Channel, 1)
+	} else {
+		messages, err = h.state.user.MessagesByID(marker.Network, marker.Channel, []string{marker.ID})
+	}
+	if err != nil || len(messages) == 0 {
+		return
+	}
+	marker.ID = messages[0].ID
+	marker.Time = messages[0].Time
+
+	go h.state.setReadMarker(marker, true)
+}
+
+func (h *wsHandler) mentionsRead(b []byte) {
+	var data MentionsRead
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.MarkMentionsRead(data.IDs...)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *wsHandler) addIgnore(b []byte) {
+	var data IgnoreRule
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.AddIgnoreRule(&data.IgnoreRule)
+	if err != nil {
+		h.state.sendJSON("ignore_add_fail", Error{Message: err.Error()})
+		return
+	}
+
+	h.sendIgnores()
+}
+
+func (h *wsHandler) removeIgnore(b []byte) {
+	var data IgnoreRemove
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.RemoveIgnoreRule(data.ID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.sendIgnores()
+}
+
+func (h *wsHandler) sendIgnores() {
+	rules, err := h.state.user.IgnoreRules()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("ignores", Ignores{Rules: rules})
+}
+
+func (h *wsHandler) pushSubscribe(b []byte) {
+	var data PushSubscription
+	data.UnmarshalJSON(b)
+
+	sub := &webpush.Subscription{
+		Endpoint: data.Endpoint,
+		P256dh:   data.Keys.P256dh,
+		Auth:     data.Keys.Auth,
+	}
+
+	err := sub.Validate()
+	if err == nil {
+		err = h.state.user.AddPushSubscription(&storage.PushSubscription{
+			Endpoint: sub.Endpoint,
+			P256dh:   sub.P256dh,
+			Auth:     sub.Auth,
+		})
+	}
+	if err != nil {
+		h.state.sendJSON("push_subscribe_fail", Error{Message: err.Error()})
+	}
+}
+
+func (h *wsHandler) pushUnsubscribe(b []byte) {
+	var data PushUnsubscribe
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.RemovePushSubscription(data.Endpoint)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *wsHandler) addAPIToken(b []byte) {
+	var data APITokenAdd
+	data.UnmarshalJSON(b)
+
+	token, secret, err := h.state.user.AddAPIToken(data.Name, data.Scopes)
+	if err != nil {
+		h.state.sendJSON("api_token_add_fail", Error{Message: err.Error()})
+		return
+	}
+
+	// Only the hash gets stored, so this is the one chance to see the token
+	h.state.sendJSON("api_token_added", APITokenAdded{
+		APIToken: *token,
+		Token:    secret,
+	})
+	h.sendAPITokens()
+}
+
+func (h *wsHandler) removeAPIToken(b []byte) {
+	var data APITokenRemove
+	data.UnmarshalJSON(b)
+
+	err := h.state.user.RemoveAPIToken(data.ID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.sendAPITokens()
+}
+
+func (h *wsHandler) sendAPITokens() {
+	tokens, err := h.state.user.APITokens()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.state.sendJSON("api_tokens", APITokens{Tokens: tokens})
+}
+
 func (h *wsHandler) initHandlers() {
 	h.handlers = map[string]func([]byte){
 		"connect":          h.connect,
@@ -380,6 +536,7 @@ func (h *wsHandler) initHandlers() {
 		"away":             h.away,
 		"raw":              h.raw,
 		"search":           h.search,
+		"message_search":   h.messageSearch,
 		"cert":             h.cert,
 		"fetch_messages":   h.fetchMessages,
 		"set_network_name": h.setNetworkName,
@@ -387,9 +544,22 @@ func (h *wsHandler) initHandlers() {
 		"channel_search":   h.channelSearch,
 		"open_dm":          h.openDM,
 		"close_dm":         h.closeDM,
+		"highlights_set":   h.setHighlights,
+		"mentions_read":    h.mentionsRead,
+		"push_subscribe":   h.pushSubscribe,
+		"push_unsubscribe": h.pushUnsubscribe,
+		"ignore_add":       h.addIgnore,
+		"ignore_remove":    h.removeIgnore,
+		"read_marker":      h.readMarker,
+		"api_token_add":    h.addAPIToken,
+		"api_token_remove": h.removeAPIToken,
 	}
 }
 
 func isValidNetworkName(name string) bool {
 	return strings.TrimSpace(name) != ""
 }
+
+func isValidNetworkID(id string) bool {
+	return id != "" && len(id) <= 255 && !strings.ContainsAny(id, "\x00/ ")
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/kjk/betterguid"
+)
+
+// The scopes an API token can have
+const (
+	// ScopeReadLogs lets a token read and search the logs and list the
+	// networks and channels they belong to
+	ScopeReadLogs     = "read_logs"
+	ScopeSendMessages = "send_messages"
+	// ScopeManageNetworks lets a token add networks and join and part
+	// channels
+	ScopeManageNetworks = "manage_networks"
+)
+
+var APIScopes = []string{ScopeReadLogs, ScopeSendMessages, ScopeManageNetworks}
+
+var (
+	ErrAPITokenNoScopes = errors.New("An API token needs at least one scope")
+	ErrAPITokenScope    = errors.New("Unknown API token scope")
+)
+
+// apiTokenTouchInterval is how often LastUsed gets updated at most, it saves
+// a write on every request
+const apiTokenTouchInterval = time.Minute
+
+// APIToken is a long-lived credential for scripts and bots, only a hash of
+// the token itself is stored
+type APIToken struct {
+	ID     string
+	Name   string
+	Hash   []byte `json:"-"`
+	Scopes []string
+	// Created and LastUsed are unix times, LastUsed is 0 for tokens that
+	// have never been used
+	Created  int64
+	LastUsed int64
+}
+
+// HasScopes returns true if the token has all of scopes
+func (t *APIToken) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !containsString(t.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) APITokens() ([]*APIToken, error) {
+	return u.store.APITokens(u)
+}
+
+// AddAPIToken creates and stores a new API token, the token is returned
+// along with what gets stored since it can not be recovered later
+func (u *User) AddAPIToken(name string, scopes []string) (*APIToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", ErrAPITokenNoScopes
+	}
+	for _, scope := range scopes {
+		if !containsString(APIScopes, scope) {
+			return nil, "", ErrAPITokenScope
+		}
+	}
+
+	token, err := session.NewToken(u.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	t := &APIToken{
+		ID:      betterguid.New(),
+		Name:    name,
+		Hash:    session.HashToken(token),
+		Scopes:  scopes,
+		Created: time.Now().Unix(),
+	}
+
+	err = u.store.SaveAPIToken(u, t)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return t, token, nil
+}
+
+func (u *User) RemoveAPIToken(id string) error {
+	return u.store.RemoveAPIToken(u, id)
+}
+
+// APIToken returns the stored API token that token hashes to, it returns
+// ErrNotFound if it has been revoked or never existed. The token is returned
+// along with the error if updating LastUsed fails.
+func (u *User) APIToken(token string) (*APIToken, error) {
+	tokens, err := u.store.APITokens(u)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := session.HashToken(token)
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare(t.Hash, hash) == 1 {
+			now := time.Now()
+			if now.Sub(time.Unix(t.LastUsed, 0)) > apiTokenTouchInterval {
+				t.LastUsed = now.Unix()
+				err = u.store.SaveAPIToken(u, t)
+			}
+			return t, err
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,58 @@
+package storage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+)
+
+func TestUserAPITokens(t *testing.T) {
+	forEachBackend(t, testUserAPITokens)
+}
+
+func testUserAPITokens(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	_, _, err = user.AddAPIToken("ci", nil)
+	assert.Equal(t, storage.ErrAPITokenNoScopes, err)
+	_, _, err = user.AddAPIToken("ci", []string{"everything"})
+	assert.Equal(t, storage.ErrAPITokenScope, err)
+
+	stored, token, err := user.AddAPIToken("ci", []string{storage.ScopeSendMessages})
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(token, session.TokenPrefix))
+	assert.Equal(t, session.HashToken(token), stored.Hash)
+
+	id, ok := session.ParseToken(token)
+	assert.True(t, ok)
+	assert.Equal(t, user.ID, id)
+
+	found, err := user.APIToken(token)
+	require.Nil(t, err)
+	assert.Equal(t, stored.ID, found.ID)
+	assert.NotZero(t, found.LastUsed)
+	assert.True(t, found.HasScopes(storage.ScopeSendMessages))
+	assert.False(t, found.HasScopes(storage.ScopeSendMessages, storage.ScopeReadLogs))
+
+	_, err = user.APIToken(token + "x")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	tokens, err := user.APITokens()
+	require.Nil(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "ci", tokens[0].Name)
+
+	require.Nil(t, user.RemoveAPIToken(stored.ID))
+	_, err = user.APIToken(token)
+	assert.Equal(t, storage.ErrNotFound, err)
+}
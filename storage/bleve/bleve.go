@@ -2,50 +2,182 @@ package bleve
 
 import (
 	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
 	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/search"
 
 	"github.com/khlieng/dispatch/storage"
 )
 
 // Bleve implements storage.MessageSearchProvider
 type Bleve struct {
-	index bleve.Index
+	index         bleve.Index
+	outdated      bool
+	storesContent bool
+}
+
+// Options are used when a new index gets created, existing indexes keep
+// what they were created with
+type Options struct {
+	// NoContent leaves the message content out of the index, it is still
+	// searchable but hits come with the positions of the matches instead
+	// of fragments. It is for message logs that are encrypted.
+	NoContent bool
 }
 
 func New(path string) (*Bleve, error) {
+	return NewWithOptions(path, Options{})
+}
+
+func NewWithOptions(path string, opts Options) (*Bleve, error) {
 	index, err := bleve.Open(path)
 	if err == bleve.ErrorIndexPathDoesNotExist {
-		keywordMapping := bleve.NewTextFieldMapping()
-		keywordMapping.Analyzer = keyword.Name
-		keywordMapping.Store = false
-		keywordMapping.IncludeTermVectors = false
-		keywordMapping.IncludeInAll = false
+		index, err = bleve.New(path, newMapping(opts))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Bleve{
+		index:         index,
+		outdated:      outdatedMapping(index.Mapping()),
+		storesContent: contentStored(index.Mapping()),
+	}, nil
+}
+
+// Outdated returns true if the index was created by an older version with a
+// mapping that does not store where messages belong or when they were sent,
+// Search needs it to be rebuilt with dispatch reindex
+func (b *Bleve) Outdated() bool {
+	return b.outdated
+}
 
-		contentMapping := bleve.NewTextFieldMapping()
-		contentMapping.Analyzer = "en"
-		contentMapping.Store = false
-		contentMapping.IncludeTermVectors = false
-		contentMapping.IncludeInAll = false
+func outdatedMapping(m mapping.IndexMapping) bool {
+	indexMapping, ok := m.(*mapping.IndexMappingImpl)
+	if !ok {
+		return false
+	}
+	messageMapping, ok := indexMapping.TypeMapping["message"]
+	if !ok {
+		return true
+	}
 
-		messageMapping := bleve.NewDocumentMapping()
-		messageMapping.StructTagKey = "bleve"
-		messageMapping.AddFieldMappingsAt("server", keywordMapping)
-		messageMapping.AddFieldMappingsAt("to", keywordMapping)
-		messageMapping.AddFieldMappingsAt("content", contentMapping)
+	for _, name := range []string{"server", "to", "time"} {
+		field, ok := messageMapping.Properties[name]
+		if !ok || len(field.Fields) == 0 {
+			return true
+		}
+		if name != "time" && !field.Fields[0].Store {
+			return true
+		}
+	}
+	return false
+}
 
-		mapping := bleve.NewIndexMapping()
-		mapping.AddDocumentMapping("message", messageMapping)
+// StoresContent returns true if the index has the content of messages in
+// it, indexes created with NoContent do not
+func (b *Bleve) StoresContent() bool {
+	return b.storesContent
+}
 
-		index, err = bleve.New(path, mapping)
+func contentStored(m mapping.IndexMapping) bool {
+	indexMapping, ok := m.(*mapping.IndexMappingImpl)
+	if !ok {
+		return true
 	}
-	if err != nil {
-		return nil, err
+	messageMapping, ok := indexMapping.TypeMapping["message"]
+	if !ok {
+		return true
 	}
-	return &Bleve{index: index}, nil
+	field, ok := messageMapping.Properties["content"]
+	if !ok || len(field.Fields) == 0 {
+		return true
+	}
+	return field.Fields[0].Store
+}
+
+func newMapping(opts Options) mapping.IndexMapping {
+	keywordMapping := bleve.NewTextFieldMapping()
+	keywordMapping.Analyzer = keyword.Name
+	keywordMapping.Store = true
+	keywordMapping.IncludeTermVectors = false
+	keywordMapping.IncludeInAll = false
+
+	nickMapping := bleve.NewTextFieldMapping()
+	nickMapping.Analyzer = "nick"
+	nickMapping.Store = false
+	nickMapping.IncludeTermVectors = false
+	nickMapping.IncludeInAll = false
+
+	// Content gets stored with term vectors to be able to highlight it,
+	// without it the term vectors still say where the matches are
+	contentMapping := bleve.NewTextFieldMapping()
+	contentMapping.Analyzer = "en"
+	contentMapping.Store = !opts.NoContent
+	contentMapping.IncludeTermVectors = true
+	contentMapping.IncludeInAll = false
+
+	eventTextMapping := bleve.NewTextFieldMapping()
+	eventTextMapping.Analyzer = "en"
+	eventTextMapping.Store = false
+	eventTextMapping.IncludeTermVectors = false
+	eventTextMapping.IncludeInAll = false
+
+	timeMapping := bleve.NewNumericFieldMapping()
+	timeMapping.Store = false
+	timeMapping.IncludeInAll = false
+
+	eventMapping := bleve.NewDocumentMapping()
+	eventMapping.AddFieldMappingsAt("type", keywordMapping)
+	eventMapping.AddFieldMappingsAt("nicks", nickMapping)
+	eventMapping.AddFieldMappingsAt("text", eventTextMapping)
+
+	messageMapping := bleve.NewDocumentMapping()
+	messageMapping.StructTagKey = "bleve"
+	messageMapping.AddFieldMappingsAt("server", keywordMapping)
+	messageMapping.AddFieldMappingsAt("to", keywordMapping)
+	messageMapping.AddFieldMappingsAt("from", nickMapping)
+	messageMapping.AddFieldMappingsAt("content", contentMapping)
+	messageMapping.AddFieldMappingsAt("time", timeMapping)
+	messageMapping.AddSubDocumentMapping("events", eventMapping)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.AddCustomAnalyzer("nick", map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+	indexMapping.AddDocumentMapping("message", messageMapping)
+	indexMapping.DefaultField = "content"
+
+	return indexMapping
 }
 
 func (b *Bleve) Index(id string, message *storage.Message) error {
-	return b.index.Index(id, message)
+	return b.index.Index(id, newDocument(message))
+}
+
+// IndexBatch indexes several messages at once, which is a lot faster than
+// indexing them one by one
+func (b *Bleve) IndexBatch(messages []*storage.Message) error {
+	batch := b.index.NewBatch()
+	for _, message := range messages {
+		err := batch.Index(message.ID, newDocument(message))
+		if err != nil {
+			return err
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+func (b *Bleve) DeleteMessages(ids []string) error {
+	batch := b.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(id)
+	}
+	return b.index.Batch(batch)
 }
 
 func (b *Bleve) SearchMessages(network, channel, q string) ([]string, error) {
@@ -74,6 +206,136 @@ func (b *Bleve) SearchMessages(network, channel, q string) ([]string, error) {
 	return ids, nil
 }
 
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (b *Bleve) Search(q storage.SearchQuery) (*storage.SearchResult, error) {
+	// Outdated indexes do not know where hits belong or when they were sent,
+	// SearchMessages still works with them until they get rebuilt
+	if b.outdated {
+		return nil, storage.ErrOutdatedIndex
+	}
+
+	query := bleve.NewBooleanQuery()
+
+	if q.Q != "" {
+		query.AddMust(bleve.NewQueryStringQuery(q.Q))
+	} else {
+		query.AddMust(bleve.NewMatchAllQuery())
+	}
+
+	if q.Network != "" {
+		serverQuery := bleve.NewTermQuery(q.Network)
+		serverQuery.SetField("server")
+		query.AddMust(serverQuery)
+	}
+
+	if q.Channel != "" {
+		channelQuery := bleve.NewTermQuery(q.Channel)
+		channelQuery.SetField("to")
+		query.AddMust(channelQuery)
+	}
+
+	if q.From != "" {
+		fromQuery := bleve.NewMatchQuery(q.From)
+		fromQuery.SetField("from")
+		query.AddMust(fromQuery)
+	}
+
+	if q.Nick != "" {
+		fromQuery := bleve.NewMatchQuery(q.Nick)
+		fromQuery.SetField("from")
+		nicksQuery := bleve.NewMatchQuery(q.Nick)
+		nicksQuery.SetField("events.nicks")
+		query.AddMust(bleve.NewDisjunctionQuery(fromQuery, nicksQuery))
+	}
+
+	if q.Event != "" {
+		eventQuery := bleve.NewTermQuery(q.Event)
+		eventQuery.SetField("events.type")
+		query.AddMust(eventQuery)
+	}
+
+	if q.Start > 0 || q.End > 0 {
+		var start, end *float64
+		if q.Start > 0 {
+			v := float64(q.Start)
+			start = &v
+		}
+		if q.End > 0 {
+			v := float64(q.End)
+			end = &v
+		}
+
+		inclusive := true
+		timeQuery := bleve.NewNumericRangeInclusiveQuery(start, end, &inclusive, &inclusive)
+		timeQuery.SetField("time")
+		query.AddMust(timeQuery)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	} else if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	search := bleve.NewSearchRequestOptions(query, limit, q.Offset, false)
+	search.Fields = []string{"server", "to"}
+	if b.storesContent {
+		search.Highlight = bleve.NewHighlightWithStyle(highlighterName)
+		search.Highlight.AddField("content")
+	} else {
+		search.IncludeLocations = true
+	}
+
+	searchResults, err := b.index.Search(search)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &storage.SearchResult{
+		Total: searchResults.Total,
+		Hits:  make([]storage.SearchHit, len(searchResults.Hits)),
+	}
+
+	for i, hit := range searchResults.Hits {
+		result.Hits[i] = storage.SearchHit{
+			ID:        hit.ID,
+			Network:   stringField(hit.Fields, "server", q.Network),
+			Channel:   stringField(hit.Fields, "to", q.Channel),
+			Fragments: hit.Fragments["content"],
+			Matches:   contentMatches(hit.Locations["content"]),
+		}
+	}
+
+	return result, nil
+}
+
+func contentMatches(locations search.TermLocationMap) []storage.SearchMatch {
+	var matches []storage.SearchMatch
+	for _, locs := range locations {
+		for _, loc := range locs {
+			matches = append(matches, storage.SearchMatch{
+				Start: int(loc.Start),
+				End:   int(loc.End),
+			})
+		}
+	}
+	return matches
+}
+
+// stringField returns the stored value of a field in a hit, or def if it
+// is missing
+func stringField(fields map[string]interface{}, name, def string) string {
+	if v, ok := fields[name].(string); ok {
+		return v
+	}
+	return def
+}
+
 func (b *Bleve) Close() {
 	b.index.Close()
 }
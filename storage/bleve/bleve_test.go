@@ -0,0 +1,69 @@
+package bleve
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// oldMapping is the mapping indexes got before server and to were stored
+func oldMapping() *mapping.IndexMappingImpl {
+	keywordMapping := bleve.NewTextFieldMapping()
+	keywordMapping.Analyzer = keyword.Name
+	keywordMapping.Store = false
+
+	contentMapping := bleve.NewTextFieldMapping()
+	contentMapping.Analyzer = "en"
+	contentMapping.Store = false
+
+	messageMapping := bleve.NewDocumentMapping()
+	messageMapping.StructTagKey = "bleve"
+	messageMapping.AddFieldMappingsAt("server", keywordMapping)
+	messageMapping.AddFieldMappingsAt("to", keywordMapping)
+	messageMapping.AddFieldMappingsAt("content", contentMapping)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.AddDocumentMapping("message", messageMapping)
+	return indexMapping
+}
+
+func TestOutdated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	index, err := New(filepath.Join(dir, "new"))
+	require.Nil(t, err)
+	assert.False(t, index.Outdated())
+	index.Close()
+
+	path := filepath.Join(dir, "old")
+	old, err := bleve.New(path, oldMapping())
+	require.Nil(t, err)
+	old.Close()
+
+	index, err = New(path)
+	require.Nil(t, err)
+	defer index.Close()
+	assert.True(t, index.Outdated())
+
+	err = index.Index("1", &storage.Message{Network: "freenode", To: "#go-nuts", Content: "gophers"})
+	require.Nil(t, err)
+
+	_, err = index.Search(storage.SearchQuery{Q: "gophers"})
+	assert.Equal(t, storage.ErrOutdatedIndex, err)
+
+	// Searching a single tab still works
+	ids, err := index.SearchMessages("freenode", "#go-nuts", "gophers")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+}
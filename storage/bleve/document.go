@@ -0,0 +1,61 @@
+package bleve
+
+import (
+	"github.com/khlieng/dispatch/storage"
+)
+
+// document is what gets indexed for a message
+type document struct {
+	Server string  `bleve:"server"`
+	To     string  `bleve:"to"`
+	From   string  `bleve:"from"`
+	Time   int64   `bleve:"time"`
+	Events []event `bleve:"events"`
+	// Content includes the text of the events, that way searches that do not
+	// specify a field find them
+	Content []string `bleve:"content"`
+}
+
+type event struct {
+	Type string `bleve:"type"`
+	// Nicks are the users involved in the event
+	Nicks []string `bleve:"nicks"`
+	// Text is the free form part of the event, like a topic or a reason
+	Text string `bleve:"text"`
+}
+
+func (document) Type() string {
+	return "message"
+}
+
+func newDocument(message *storage.Message) *document {
+	doc := &document{
+		Server: message.Network,
+		To:     message.To,
+		From:   message.From,
+		Time:   message.Time,
+		Events: make([]event, len(message.Events)),
+	}
+
+	if message.Content != "" {
+		doc.Content = append(doc.Content, message.Content)
+	}
+
+	for i, e := range message.Events {
+		doc.Events[i] = newEvent(e)
+
+		if doc.Events[i].Text != "" {
+			doc.Content = append(doc.Content, doc.Events[i].Text)
+		}
+	}
+
+	return doc
+}
+
+func newEvent(e storage.Event) event {
+	return event{
+		Type:  e.Type,
+		Nicks: e.Nicks(),
+		Text:  e.Text(),
+	}
+}
@@ -0,0 +1,57 @@
+package bleve
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/blevesearch/bleve/registry"
+	"github.com/blevesearch/bleve/search/highlight"
+	"github.com/blevesearch/bleve/search/highlight/fragmenter/simple"
+	simpleHighlighter "github.com/blevesearch/bleve/search/highlight/highlighter/simple"
+)
+
+// The html highlighter that comes with bleve does not escape the message
+// content, this one does so the fragments are safe to render
+const highlighterName = "dispatch"
+
+type fragmentFormatter struct{}
+
+func (fragmentFormatter) Format(f *highlight.Fragment, locations highlight.TermLocations) string {
+	var sb strings.Builder
+	curr := f.Start
+
+	for _, location := range locations {
+		if location == nil ||
+			!location.ArrayPositions.Equals(f.ArrayPositions) ||
+			location.Start < curr {
+			continue
+		}
+		if location.End > f.End {
+			break
+		}
+
+		sb.WriteString(html.EscapeString(string(f.Orig[curr:location.Start])))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(string(f.Orig[location.Start:location.End])))
+		sb.WriteString("</mark>")
+		curr = location.End
+	}
+	sb.WriteString(html.EscapeString(string(f.Orig[curr:f.End])))
+
+	return sb.String()
+}
+
+func newHighlighter(config map[string]interface{}, cache *registry.Cache) (highlight.Highlighter, error) {
+	fragmenter, err := cache.FragmenterNamed(simple.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error building fragmenter: %v", err)
+	}
+
+	return simpleHighlighter.NewHighlighter(fragmenter, fragmentFormatter{},
+		simpleHighlighter.DefaultSeparator), nil
+}
+
+func init() {
+	registry.RegisterHighlighter(highlighterName, newHighlighter)
+}
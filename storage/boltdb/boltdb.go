@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"strconv"
+	"sync"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 
@@ -12,21 +14,36 @@ import (
 )
 
 var (
-	bucketUsers    = []byte("Users")
-	bucketNetworks = []byte("Networks")
-	bucketChannels = []byte("Channels")
-	bucketOpenDMs  = []byte("OpenDMs")
-	bucketMessages = []byte("Messages")
-	bucketSessions = []byte("Sessions")
+	bucketUsers      = []byte("Users")
+	bucketNetworks   = []byte("Networks")
+	bucketChannels   = []byte("Channels")
+	bucketOpenDMs    = []byte("OpenDMs")
+	bucketMessages   = []byte("Messages")
+	bucketSessions   = []byte("Sessions")
+	bucketLinkMeta   = []byte("LinkMeta")
+	bucketHighlights = []byte("Highlights")
+	bucketMentions   = []byte("Mentions")
+	bucketPush       = []byte("PushSubscriptions")
+	bucketIgnores    = []byte("Ignores")
+	bucketMarkers    = []byte("ReadMarkers")
+	bucketTokens     = []byte("APITokens")
+	bucketRenames    = []byte("NetworkRenames")
 )
 
-// BoltStore implements storage.Store, storage.MessageStore and storage.SessionStore
+// openTimeout is how long to wait for the lock on a database that
+// another process has open
+const openTimeout = 5 * time.Second
+
+// BoltStore implements storage.Store, storage.MessageStore, storage.SessionStore
+// and storage.LinkMetaStore
 type BoltStore struct {
 	db *bolt.DB
+	// lock is held for writing while the database file gets replaced
+	lock sync.RWMutex
 }
 
 func New(path string) (*BoltStore, error) {
-	db, err := bolt.Open(path, 0600, nil)
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
 	if err != nil {
 		return nil, err
 	}
@@ -38,22 +55,56 @@ func New(path string) (*BoltStore, error) {
 		tx.CreateBucketIfNotExists(bucketOpenDMs)
 		tx.CreateBucketIfNotExists(bucketMessages)
 		tx.CreateBucketIfNotExists(bucketSessions)
+		tx.CreateBucketIfNotExists(bucketLinkMeta)
+		tx.CreateBucketIfNotExists(bucketHighlights)
+		tx.CreateBucketIfNotExists(bucketMentions)
+		tx.CreateBucketIfNotExists(bucketPush)
+		tx.CreateBucketIfNotExists(bucketIgnores)
+		tx.CreateBucketIfNotExists(bucketMarkers)
+		tx.CreateBucketIfNotExists(bucketTokens)
+		tx.CreateBucketIfNotExists(bucketRenames)
 		return nil
 	})
 
+	err = migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return &BoltStore{
-		db,
+		db: db,
 	}, nil
 }
 
 func (s *BoltStore) Close() {
+	s.lock.Lock()
 	s.db.Close()
+	s.lock.Unlock()
+}
+
+func (s *BoltStore) view(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.View(fn)
+}
+
+func (s *BoltStore) update(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.Update(fn)
+}
+
+func (s *BoltStore) batch(fn func(*bolt.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db.Batch(fn)
 }
 
 func (s *BoltStore) Users() ([]*storage.User, error) {
 	var users []*storage.User
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketUsers)
 
 		return b.ForEach(func(k, v []byte) error {
@@ -73,12 +124,19 @@ func (s *BoltStore) Users() ([]*storage.User, error) {
 }
 
 func (s *BoltStore) SaveUser(user *storage.User) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketUsers)
 
 		if user.ID == 0 {
 			user.ID, _ = b.NextSequence()
 			user.IDBytes = idToBytes(user.ID)
+		} else if user.ID > b.Sequence() {
+			// Users that come from another store keep their ID, the
+			// sequence has to move past it
+			err := b.SetSequence(user.ID)
+			if err != nil {
+				return err
+			}
 		}
 		user.Username = strconv.FormatUint(user.ID, 10)
 
@@ -92,7 +150,7 @@ func (s *BoltStore) SaveUser(user *storage.User) error {
 }
 
 func (s *BoltStore) DeleteUser(user *storage.User) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		err := tx.Bucket(bucketUsers).Delete(user.IDBytes)
 		if err != nil {
 			return err
@@ -102,18 +160,25 @@ func (s *BoltStore) DeleteUser(user *storage.User) error {
 			tx.Bucket(bucketNetworks),
 			tx.Bucket(bucketChannels),
 			tx.Bucket(bucketOpenDMs),
+			tx.Bucket(bucketHighlights),
+			tx.Bucket(bucketMentions),
+			tx.Bucket(bucketPush),
+			tx.Bucket(bucketIgnores),
+			tx.Bucket(bucketMarkers),
+			tx.Bucket(bucketTokens),
+			tx.Bucket(bucketRenames),
 		)
 	})
 }
 
-func (s *BoltStore) Network(user *storage.User, address string) (*storage.Network, error) {
+func (s *BoltStore) Network(user *storage.User, id string) (*storage.Network, error) {
 	var network *storage.Network
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketNetworks)
-		id := networkID(user, address)
+		key := networkID(user, id)
 
-		v := b.Get(id)
+		v := b.Get(key)
 		if v == nil {
 			return storage.ErrNotFound
 		} else {
@@ -129,7 +194,7 @@ func (s *BoltStore) Network(user *storage.User, address string) (*storage.Networ
 func (s *BoltStore) Networks(user *storage.User) ([]*storage.Network, error) {
 	var networks []*storage.Network
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketNetworks).Cursor()
 
 		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
@@ -145,61 +210,90 @@ func (s *BoltStore) Networks(user *storage.User) ([]*storage.Network, error) {
 }
 
 func (s *BoltStore) SaveNetwork(user *storage.User, network *storage.Network) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketNetworks)
 		data, _ := network.Marshal(nil)
 
-		return b.Put(networkID(user, network.Host), data)
+		return b.Put(networkID(user, network.ID), data)
 	})
 }
 
-func (s *BoltStore) RemoveNetwork(user *storage.User, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
-		networkID := networkID(user, address)
+func (s *BoltStore) RemoveNetwork(user *storage.User, id string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		networkID := networkID(user, id)
 		err := tx.Bucket(bucketNetworks).Delete(networkID)
 		if err != nil {
 			return err
 		}
+		err = tx.Bucket(bucketRenames).Delete(networkID)
+		if err != nil {
+			return err
+		}
 
-		return deletePrefix(networkID,
+		// Channel keys end the network ID with a 0 byte, without it the
+		// channels of networks with IDs starting with this one would match
+		return deletePrefix(append(networkID, 0),
 			tx.Bucket(bucketChannels),
 			tx.Bucket(bucketOpenDMs),
+			tx.Bucket(bucketMarkers),
 		)
 	})
 }
 
-func (s *BoltStore) SetNick(user *storage.User, nick, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+func (s *BoltStore) NetworkRenames(user *storage.User) (map[string]string, error) {
+	renames := map[string]string{}
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketRenames).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			renames[string(k[8:])] = string(v)
+		}
+
+		return nil
+	})
+
+	return renames, err
+}
+
+func (s *BoltStore) SaveNetworkRename(user *storage.User, from, to string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRenames).Put(networkID(user, from), []byte(to))
+	})
+}
+
+func (s *BoltStore) SetNick(user *storage.User, nick, id string) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketNetworks)
-		id := networkID(user, address)
+		key := networkID(user, id)
 
 		network := storage.Network{}
-		v := b.Get(id)
+		v := b.Get(key)
 		if v != nil {
 			network.Unmarshal(v)
 			network.Nick = nick
 
 			data, _ := network.Marshal(nil)
-			return b.Put(id, data)
+			return b.Put(key, data)
 		}
 
 		return nil
 	})
 }
 
-func (s *BoltStore) SetNetworkName(user *storage.User, name, address string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+func (s *BoltStore) SetNetworkName(user *storage.User, name, id string) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketNetworks)
-		id := networkID(user, address)
+		key := networkID(user, id)
 
 		network := storage.Network{}
-		v := b.Get(id)
+		v := b.Get(key)
 		if v != nil {
 			network.Unmarshal(v)
 			network.Name = name
 
 			data, _ := network.Marshal(nil)
-			return b.Put(id, data)
+			return b.Put(key, data)
 		}
 
 		return nil
@@ -209,7 +303,7 @@ func (s *BoltStore) SetNetworkName(user *storage.User, name, address string) err
 func (s *BoltStore) Channels(user *storage.User) ([]*storage.Channel, error) {
 	var channels []*storage.Channel
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketChannels).Cursor()
 
 		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
@@ -225,7 +319,7 @@ func (s *BoltStore) Channels(user *storage.User) ([]*storage.Channel, error) {
 }
 
 func (s *BoltStore) SaveChannel(user *storage.User, channel *storage.Channel) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketChannels)
 		data, _ := channel.Marshal(nil)
 
@@ -234,7 +328,7 @@ func (s *BoltStore) SaveChannel(user *storage.User, channel *storage.Channel) er
 }
 
 func (s *BoltStore) RemoveChannel(user *storage.User, network, channel string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketChannels)
 		id := channelID(user, network, channel)
 
@@ -244,7 +338,7 @@ func (s *BoltStore) RemoveChannel(user *storage.User, network, channel string) e
 
 func (s *BoltStore) HasChannel(user *storage.User, network, channel string) bool {
 	has := false
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketChannels)
 		has = b.Get(channelID(user, network, channel)) != nil
 
@@ -256,7 +350,7 @@ func (s *BoltStore) HasChannel(user *storage.User, network, channel string) bool
 func (s *BoltStore) OpenDMs(user *storage.User) ([]storage.Tab, error) {
 	var openDMs []storage.Tab
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		c := tx.Bucket(bucketOpenDMs).Cursor()
 
 		for k, _ := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, _ = c.Next() {
@@ -274,7 +368,7 @@ func (s *BoltStore) OpenDMs(user *storage.User) ([]storage.Tab, error) {
 }
 
 func (s *BoltStore) AddOpenDM(user *storage.User, network, nick string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketOpenDMs)
 
 		return b.Put(channelID(user, network, nick), nil)
@@ -282,13 +376,253 @@ func (s *BoltStore) AddOpenDM(user *storage.User, network, nick string) error {
 }
 
 func (s *BoltStore) RemoveOpenDM(user *storage.User, network, nick string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketOpenDMs)
 
 		return b.Delete(channelID(user, network, nick))
 	})
 }
 
+func (s *BoltStore) HighlightRules(user *storage.User) (*storage.HighlightRules, error) {
+	rules := &storage.HighlightRules{}
+
+	err := s.view(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketHighlights).Get(user.IDBytes)
+		if v == nil {
+			return storage.ErrNotFound
+		}
+		_, err := rules.Unmarshal(v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *BoltStore) SaveHighlightRules(user *storage.User, rules *storage.HighlightRules) error {
+	data, err := rules.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketHighlights).Put(user.IDBytes, data)
+	})
+}
+
+func (s *BoltStore) Mentions(user *storage.User) ([]*storage.Mention, error) {
+	var mentions []*storage.Mention
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketMentions).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			mention := storage.Mention{}
+			_, err := mention.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+			mentions = append(mentions, &mention)
+		}
+
+		return nil
+	})
+
+	return mentions, err
+}
+
+func (s *BoltStore) SaveMention(user *storage.User, mention *storage.Mention) error {
+	data, err := mention.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMentions).Put(userKey(user, mention.ID), data)
+	})
+}
+
+func (s *BoltStore) RemoveMentions(user *storage.User, ids []string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMentions)
+
+		for _, id := range ids {
+			err := b.Delete(userKey(user, id))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *BoltStore) PushSubscriptions(user *storage.User) ([]*storage.PushSubscription, error) {
+	var subs []*storage.PushSubscription
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketPush).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			sub := storage.PushSubscription{}
+			_, err := sub.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+			subs = append(subs, &sub)
+		}
+
+		return nil
+	})
+
+	return subs, err
+}
+
+func (s *BoltStore) SavePushSubscription(user *storage.User, sub *storage.PushSubscription) error {
+	data, err := sub.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPush).Put(userKey(user, sub.Endpoint), data)
+	})
+}
+
+func (s *BoltStore) RemovePushSubscription(user *storage.User, endpoint string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPush).Delete(userKey(user, endpoint))
+	})
+}
+
+func (s *BoltStore) IgnoreRules(user *storage.User) ([]*storage.IgnoreRule, error) {
+	var rules []*storage.IgnoreRule
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketIgnores).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			rule := storage.IgnoreRule{}
+			_, err := rule.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, &rule)
+		}
+
+		return nil
+	})
+
+	return rules, err
+}
+
+func (s *BoltStore) SaveIgnoreRule(user *storage.User, rule *storage.IgnoreRule) error {
+	data, err := rule.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketIgnores).Put(userKey(user, rule.ID), data)
+	})
+}
+
+func (s *BoltStore) RemoveIgnoreRule(user *storage.User, id string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketIgnores).Delete(userKey(user, id))
+	})
+}
+
+func (s *BoltStore) ReadMarker(user *storage.User, network, channel string) (*storage.ReadMarker, error) {
+	marker := &storage.ReadMarker{}
+
+	err := s.view(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketMarkers).Get(channelID(user, network, channel))
+		if v == nil {
+			return storage.ErrNotFound
+		}
+		_, err := marker.Unmarshal(v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marker, nil
+}
+
+func (s *BoltStore) ReadMarkers(user *storage.User) ([]*storage.ReadMarker, error) {
+	var markers []*storage.ReadMarker
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketMarkers).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			marker := storage.ReadMarker{}
+			_, err := marker.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+			markers = append(markers, &marker)
+		}
+
+		return nil
+	})
+
+	return markers, err
+}
+
+func (s *BoltStore) SaveReadMarker(user *storage.User, marker *storage.ReadMarker) error {
+	data, err := marker.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMarkers).Put(channelID(user, marker.Network, marker.Channel), data)
+	})
+}
+
+func (s *BoltStore) APITokens(user *storage.User) ([]*storage.APIToken, error) {
+	var tokens []*storage.APIToken
+
+	err := s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketTokens).Cursor()
+
+		for k, v := c.Seek(user.IDBytes); bytes.HasPrefix(k, user.IDBytes); k, v = c.Next() {
+			token := storage.APIToken{}
+			_, err := token.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, &token)
+		}
+
+		return nil
+	})
+
+	return tokens, err
+}
+
+func (s *BoltStore) SaveAPIToken(user *storage.User, token *storage.APIToken) error {
+	data, err := token.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTokens).Put(userKey(user, token.ID), data)
+	})
+}
+
+func (s *BoltStore) RemoveAPIToken(user *storage.User, id string) error {
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTokens).Delete(userKey(user, id))
+	})
+}
+
 func (s *BoltStore) logMessage(tx *bolt.Tx, message *storage.Message) error {
 	b, err := tx.Bucket(bucketMessages).CreateBucketIfNotExists([]byte(message.Network + ":" + message.To))
 	if err != nil {
@@ -304,13 +638,13 @@ func (s *BoltStore) logMessage(tx *bolt.Tx, message *storage.Message) error {
 }
 
 func (s *BoltStore) LogMessage(message *storage.Message) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		return s.logMessage(tx, message)
 	})
 }
 
 func (s *BoltStore) LogMessages(messages []*storage.Message) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		for _, message := range messages {
 			err := s.logMessage(tx, message)
 			if err != nil {
@@ -326,7 +660,7 @@ func (s *BoltStore) Messages(network, channel string, count int, fromID string)
 	messages := make([]storage.Message, count)
 	hasMore := false
 
-	s.db.View(func(tx *bolt.Tx) error {
+	s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketMessages).Bucket([]byte(network + ":" + channel))
 		if b == nil {
 			return nil
@@ -367,21 +701,282 @@ func (s *BoltStore) Messages(network, channel string, count int, fromID string)
 func (s *BoltStore) MessagesByID(network, channel string, ids []string) ([]storage.Message, error) {
 	messages := make([]storage.Message, len(ids))
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketMessages).Bucket([]byte(network + ":" + channel))
+		if b == nil {
+			messages = nil
+			return nil
+		}
 
-		for i, id := range ids {
-			messages[i].Unmarshal(b.Get([]byte(id)))
+		n := 0
+		for _, id := range ids {
+			if v := b.Get([]byte(id)); v != nil {
+				messages[n].Unmarshal(v)
+				n++
+			}
 		}
+		messages = messages[:n]
 		return nil
 	})
 	return messages, err
 }
 
+// CountUnread walks back from the last message in the channel until it
+// reaches the read marker, only the unread messages get decoded
+func (s *BoltStore) CountUnread(network, channel string, marker *storage.ReadMarker, max int) (int, error) {
+	count := 0
+
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages).Bucket([]byte(network + ":" + channel))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil && count < max; k, v = c.Prev() {
+			if marker != nil && marker.ID != "" && string(k) <= marker.ID {
+				break
+			}
+
+			message := storage.Message{}
+			_, err := message.Unmarshal(v)
+			if err != nil {
+				return err
+			}
+
+			if marker != nil && marker.Covers(string(k), message.Time) {
+				break
+			}
+			if message.Content != "" {
+				count++
+			}
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// MessageCount returns the number of messages logged in all channels
+func (s *BoltStore) MessageCount() (int, error) {
+	count := 0
+
+	err := s.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages)
+
+		return b.ForEach(func(k, _ []byte) error {
+			count += b.Bucket(k).Stats().KeyN
+			return nil
+		})
+	})
+
+	return count, err
+}
+
+// forEachMessageBatch is how many messages ForEachMessage reads in each
+// transaction
+const forEachMessageBatch = 1000
+
+// ForEachMessage calls fn with every message logged in all channels. The
+// messages get read in batches and fn is called outside of the transactions,
+// a slow fn, like an export streaming to a client, does not keep writes or
+// Compact waiting.
+func (s *BoltStore) ForEachMessage(fn func(*storage.Message) error) error {
+	var names [][]byte
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMessages).ForEach(func(name, _ []byte) error {
+			names = append(names, append([]byte{}, name...))
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		network, channel, ok := splitMessageBucketName(name)
+		if !ok {
+			continue
+		}
+
+		var last []byte
+		for {
+			messages := make([]storage.Message, 0, forEachMessageBatch)
+
+			err = s.view(func(tx *bolt.Tx) error {
+				b := tx.Bucket(bucketMessages).Bucket(name)
+				if b == nil {
+					return nil
+				}
+
+				c := b.Cursor()
+				k, v := c.First()
+				if last != nil {
+					k, v = c.Seek(last)
+					if bytes.Equal(k, last) {
+						k, v = c.Next()
+					}
+				}
+
+				for ; k != nil && len(messages) < forEachMessageBatch; k, v = c.Next() {
+					message := storage.Message{
+						Network: network,
+						To:      channel,
+					}
+					_, err := message.Unmarshal(v)
+					if err != nil {
+						return err
+					}
+
+					messages = append(messages, message)
+					last = append(last[:0], k...)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for i := range messages {
+				err = fn(&messages[i])
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(messages) < forEachMessageBatch {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// PruneMessages deletes the oldest messages in each channel, limits returns
+// the time messages have to be logged before and the number of messages to
+// keep, 0 disables a limit. Message IDs sort by the time they were logged.
+func (s *BoltStore) PruneMessages(limits func(network, channel string) (before int64, keep int)) ([]string, error) {
+	var deleted []string
+
+	err := s.update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages)
+
+		var names [][]byte
+		err := b.ForEach(func(name, _ []byte) error {
+			names = append(names, name)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			network, channel, ok := splitMessageBucketName(name)
+			if !ok {
+				continue
+			}
+
+			before, keep := limits(network, channel)
+			if before == 0 && keep == 0 {
+				continue
+			}
+
+			mb := b.Bucket(name)
+
+			overLimit := 0
+			if keep > 0 {
+				if n := mb.Stats().KeyN; n > keep {
+					overLimit = n - keep
+				}
+			}
+
+			var ids [][]byte
+			c := mb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if len(ids) >= overLimit {
+					if before == 0 {
+						break
+					}
+
+					message := storage.Message{}
+					_, err = message.Unmarshal(v)
+					if err != nil {
+						return err
+					}
+
+					if message.Time >= before {
+						break
+					}
+				}
+
+				ids = append(ids, append([]byte{}, k...))
+			}
+
+			for _, id := range ids {
+				err = mb.Delete(id)
+				if err != nil {
+					return err
+				}
+				deleted = append(deleted, string(id))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// RenameNetwork moves the message buckets of the network from over to the
+// network to
+func (s *BoltStore) RenameNetwork(from, to string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketMessages)
+		prefix := []byte(from + ":")
+
+		var names [][]byte
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			// Network IDs can contain colons, from:x:#chan belongs to from:x
+			if network, _, ok := splitMessageBucketName(k); ok && network == from {
+				names = append(names, append([]byte{}, k...))
+			}
+		}
+
+		for _, name := range names {
+			_, channel, _ := splitMessageBucketName(name)
+
+			dst, err := b.CreateBucketIfNotExists([]byte(to + ":" + channel))
+			if err != nil {
+				return err
+			}
+
+			err = b.Bucket(name).ForEach(func(k, v []byte) error {
+				return dst.Put(k, v)
+			})
+			if err != nil {
+				return err
+			}
+
+			err = b.DeleteBucket(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (s *BoltStore) Sessions() ([]*session.Session, error) {
 	var sessions []*session.Session
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketSessions)
 
 		return b.ForEach(func(_ []byte, v []byte) error {
@@ -400,7 +995,7 @@ func (s *BoltStore) Sessions() ([]*session.Session, error) {
 }
 
 func (s *BoltStore) SaveSession(session *session.Session) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket(bucketSessions)
 
 		data, err := session.Marshal(nil)
@@ -413,11 +1008,67 @@ func (s *BoltStore) SaveSession(session *session.Session) error {
 }
 
 func (s *BoltStore) DeleteSession(key string) error {
-	return s.db.Batch(func(tx *bolt.Tx) error {
+	return s.batch(func(tx *bolt.Tx) error {
 		return tx.Bucket(bucketSessions).Delete([]byte(key))
 	})
 }
 
+func (s *BoltStore) LinkMeta(url string) (*storage.LinkMeta, error) {
+	meta := &storage.LinkMeta{}
+
+	err := s.view(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketLinkMeta).Get([]byte(url))
+		if v == nil {
+			return storage.ErrNotFound
+		}
+		return meta.Unmarshal(v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+func (s *BoltStore) SaveLinkMeta(meta *storage.LinkMeta) error {
+	data, err := meta.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return s.batch(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketLinkMeta).Put([]byte(meta.URL), data)
+	})
+}
+
+func (s *BoltStore) PruneLinkMeta(before int64) error {
+	return s.update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketLinkMeta)
+		var expired [][]byte
+
+		err := b.ForEach(func(k, v []byte) error {
+			meta := storage.LinkMeta{}
+			err := meta.Unmarshal(v)
+			if err != nil || meta.Time < before {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			err = b.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func deletePrefix(prefix []byte, buckets ...*bolt.Bucket) error {
 	for _, b := range buckets {
 		c := b.Cursor()
@@ -433,13 +1084,30 @@ func deletePrefix(prefix []byte, buckets ...*bolt.Bucket) error {
 	return nil
 }
 
-func networkID(user *storage.User, address string) []byte {
-	id := make([]byte, 8+len(address))
+// splitMessageBucketName splits the name of a message bucket into the
+// network and channel, channel names can not contain colons but network IDs can
+func splitMessageBucketName(name []byte) (string, string, bool) {
+	i := bytes.LastIndexByte(name, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	return string(name[:i]), string(name[i+1:]), true
+}
+
+func networkID(user *storage.User, network string) []byte {
+	id := make([]byte, 8+len(network))
 	copy(id, user.IDBytes)
-	copy(id[8:], address)
+	copy(id[8:], network)
 	return id
 }
 
+func userKey(user *storage.User, id string) []byte {
+	key := make([]byte, 8+len(id))
+	copy(key, user.IDBytes)
+	copy(key[8:], id)
+	return key
+}
+
 func channelID(user *storage.User, network, channel string) []byte {
 	id := make([]byte, 8+len(network)+1+len(channel))
 	copy(id, user.IDBytes)
@@ -0,0 +1,129 @@
+package boltdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/storagetest"
+)
+
+func open(t *testing.T) *BoltStore {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+
+	db, err := New(filepath.Join(dir, "dispatch.db"))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+	return db
+}
+
+func TestStore(t *testing.T) {
+	storagetest.TestStore(t, func(t *testing.T) storage.Store {
+		return open(t)
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	storagetest.TestSessionStore(t, func(t *testing.T) storage.SessionStore {
+		return open(t)
+	})
+}
+
+func TestLinkMetaStore(t *testing.T) {
+	storagetest.TestLinkMetaStore(t, func(t *testing.T) storage.LinkMetaStore {
+		return open(t)
+	})
+}
+
+func TestMessageStore(t *testing.T) {
+	storagetest.TestMessageStore(t, func(t *testing.T) storage.MessageStore {
+		return open(t)
+	})
+}
+
+func TestForEachMessageBatches(t *testing.T) {
+	db := open(t)
+
+	messages := make([]*storage.Message, forEachMessageBatch+10)
+	for i := range messages {
+		messages[i] = &storage.Message{
+			ID:      betterguid.New(),
+			Network: "freenode",
+			To:      "#go-nuts",
+			Content: "message",
+		}
+	}
+	require.Nil(t, db.LogMessages(messages))
+
+	// Writes and compaction do not have to wait for fn
+	count := 0
+	err := db.ForEachMessage(func(message *storage.Message) error {
+		if count == 0 {
+			require.Nil(t, db.Compact())
+			require.Nil(t, db.LogMessage(&storage.Message{
+				ID:      betterguid.New(),
+				Network: "freenode",
+				To:      "#rust",
+			}))
+		}
+		assert.Equal(t, messages[count].ID, message.ID)
+		count++
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, len(messages), count)
+}
+
+func TestPlanNetworkIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dispatch.db")
+
+	db, err := New(path)
+	require.Nil(t, err)
+
+	user := &storage.User{}
+	require.Nil(t, db.SaveUser(user))
+	require.Nil(t, db.SaveNetwork(user, &storage.Network{ID: "irc.freenode.net", Host: "irc.freenode.net"}))
+
+	// Go back to the version before the networks got new IDs
+	require.Nil(t, db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put(keyVersion, idToBytes(uint64(len(migrations)-1)))
+	}))
+	db.Close()
+
+	db, err = New(path)
+	require.Nil(t, err)
+
+	renames, err := db.NetworkRenames(user)
+	require.Nil(t, err)
+	require.Len(t, renames, 1)
+	id := renames["irc.freenode.net"]
+	assert.NotEmpty(t, id)
+	assert.NotEqual(t, "irc.freenode.net", id)
+
+	// Networks added after the upgrade keep their ID
+	require.Nil(t, db.SaveNetwork(user, &storage.Network{ID: "irc.libera.chat", Host: "irc.libera.chat"}))
+	db.Close()
+
+	db, err = New(path)
+	require.Nil(t, err)
+	defer db.Close()
+
+	renames, err = db.NetworkRenames(user)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"irc.freenode.net": id}, renames)
+}
@@ -0,0 +1,82 @@
+package boltdb
+
+import (
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Compact rewrites the database to a new file and replaces the current one
+// with it, bolt reuses the space of deleted data but never gives it back
+func (s *BoltStore) Compact() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := s.db.Path()
+	tmpPath := path + ".compact"
+
+	dst, err := bolt.Open(tmpPath, 0600, nil)
+	if err != nil {
+		return err
+	}
+
+	err = compact(dst, s.db)
+	dst.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	err = s.db.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// The current file gets opened again even if replacing it failed
+	renameErr := os.Rename(tmpPath, path)
+	if renameErr != nil {
+		os.Remove(tmpPath)
+	}
+
+	s.db, err = bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return err
+	}
+	return renameErr
+}
+
+func compact(dst, src *bolt.DB) error {
+	return src.View(func(stx *bolt.Tx) error {
+		return dst.Update(func(dtx *bolt.Tx) error {
+			return stx.ForEach(func(name []byte, b *bolt.Bucket) error {
+				db, err := dtx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(db, b)
+			})
+		})
+	})
+}
+
+func copyBucket(dst, src *bolt.Bucket) error {
+	// The keys get added in order, so the pages can be filled completely
+	dst.FillPercent = 1
+
+	err := dst.SetSequence(src.Sequence())
+	if err != nil {
+		return err
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		if v == nil {
+			child, err := dst.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+			return copyBucket(child, src.Bucket(k))
+		}
+		return dst.Put(k, v)
+	})
+}
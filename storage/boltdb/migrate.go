@@ -0,0 +1,170 @@
+package boltdb
+
+import (
+	"github.com/khlieng/dispatch/storage"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketMeta = []byte("Meta")
+
+	keyVersion = []byte("version")
+)
+
+// migrations upgrade the data in the database, they get run in order
+// starting from the version stored in the database
+var migrations = []func(tx *bolt.Tx) error{
+	addNetworkServers,
+	addNetworkIDs,
+	addLinkPreviewsSetting,
+	planNetworkIDs,
+}
+
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucketMeta)
+		if err != nil {
+			return err
+		}
+
+		version := uint64(0)
+		if v := b.Get(keyVersion); v != nil {
+			version = idFromBytes(v)
+		}
+
+		for ; version < uint64(len(migrations)); version++ {
+			err = migrations[version](tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return b.Put(keyVersion, idToBytes(version))
+	})
+}
+
+// addNetworkServers upgrades networks stored before Network.Servers got added,
+// appending a zero length byte encodes an empty list of servers
+func addNetworkServers(tx *bolt.Tx) error {
+	return appendToValues(tx.Bucket(bucketNetworks), 0)
+}
+
+// addNetworkIDs gives networks stored before Network.ID got added their host
+// as ID, everything stored for a network was keyed by its host so the keys
+// stay valid. The messages are in a database per user, so the generated IDs
+// get handed out when the users are loaded.
+func addNetworkIDs(tx *bolt.Tx) error {
+	err := appendToValues(tx.Bucket(bucketNetworks), 0)
+	if err != nil {
+		return err
+	}
+
+	b := tx.Bucket(bucketNetworks)
+	networks := map[string]*storage.Network{}
+
+	err = b.ForEach(func(k, v []byte) error {
+		network := &storage.Network{}
+		_, err := network.Unmarshal(v)
+		if err != nil {
+			return err
+		}
+
+		network.ID = network.Host
+		networks[string(k)] = network
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, network := range networks {
+		data, _ := network.Marshal(nil)
+
+		err = b.Put([]byte(k), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addLinkPreviewsSetting upgrades users stored before
+// ClientSettings.LinkPreviews got added
+func addLinkPreviewsSetting(tx *bolt.Tx) error {
+	b := tx.Bucket(bucketUsers)
+	users := map[string][]byte{}
+
+	err := b.ForEach(func(k, v []byte) error {
+		data, err := storage.AddLinkPreviewsSetting(v)
+		if err != nil {
+			return err
+		}
+		users[string(k)] = data
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, data := range users {
+		err = b.Put([]byte(k), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planNetworkIDs picks the generated IDs the networks that got their host
+// as ID get moved to, they all did before this. The users get their networks
+// moved when they are loaded, the IDs are stored first so a move that fails
+// gets finished with the same ID.
+func planNetworkIDs(tx *bolt.Tx) error {
+	renames, err := tx.CreateBucketIfNotExists(bucketRenames)
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	err = tx.Bucket(bucketNetworks).ForEach(func(k, _ []byte) error {
+		keys = append(keys, append([]byte{}, k...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		err = renames.Put(k, []byte(storage.NewNetworkID()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendToValues(b *bolt.Bucket, suffix ...byte) error {
+	values := map[string][]byte{}
+
+	err := b.ForEach(func(k, v []byte) error {
+		value := make([]byte, len(v), len(v)+len(suffix))
+		copy(value, v)
+		values[string(k)] = append(value, suffix...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, v := range values {
+		err = b.Put([]byte(k), v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -60,6 +60,14 @@ func (d directory) DownloadedFile(username string, file string) string {
 	return filepath.Join(d.Downloads(username), file)
 }
 
+func (d directory) Thumbnails() string {
+	return filepath.Join(d.DataRoot(), "thumbnails")
+}
+
+func (d directory) VAPIDKey() string {
+	return filepath.Join(d.DataRoot(), "vapid.key")
+}
+
 func (d directory) Config() string {
 	return filepath.Join(d.ConfigRoot(), "config.toml")
 }
@@ -67,3 +75,11 @@ func (d directory) Config() string {
 func (d directory) Database() string {
 	return filepath.Join(d.DataRoot(), "dispatch.db")
 }
+
+func (d directory) SQLite() string {
+	return filepath.Join(d.DataRoot(), "dispatch.sqlite")
+}
+
+func (d directory) Lock() string {
+	return filepath.Join(d.DataRoot(), "dispatch.lock")
+}
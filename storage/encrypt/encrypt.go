@@ -0,0 +1,176 @@
+// Package encrypt encrypts the sensitive parts of what dispatch stores.
+//
+// Encrypted values are strings that start with a prefix followed by the ID
+// of the key they were encrypted with, values without the prefix are read
+// as plaintext so data stored before encryption got enabled keeps working.
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+var (
+	ErrUnknownKey = errors.New("encrypt: value was encrypted with a key that is not configured")
+	ErrInvalid    = errors.New("encrypt: invalid encrypted value")
+)
+
+// Key is an AES-256-GCM key
+type Key struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func newKey(b []byte) (*Key, error) {
+	block, err := aes.NewCipher(b)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(b)
+	return &Key{
+		id:   hex.EncodeToString(sum[:4]),
+		aead: aead,
+	}, nil
+}
+
+// ParseKey parses a base64 encoded 32 byte key, the format GenerateKey
+// returns
+func ParseKey(s string) (*Key, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt: key is not valid base64: %v", err)
+	}
+	if len(b) != keySize {
+		return nil, fmt.Errorf("encrypt: key has to be %d bytes, it is %d", keySize, len(b))
+	}
+	return newKey(b)
+}
+
+// GenerateKey returns a new random key encoded as base64
+func GenerateKey() (string, error) {
+	b := make([]byte, keySize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// ID identifies the key in the values it encrypts
+func (k *Key) ID() string {
+	return k.id
+}
+
+// Keyring encrypts with its current key and decrypts with any of its keys,
+// keeping the old keys around while rotating lets values encrypted with
+// them still be read
+type Keyring struct {
+	current *Key
+	keys    map[string]*Key
+}
+
+// NewKeyring returns a Keyring that encrypts with current, a Keyring
+// without a current key stores values as plaintext
+func NewKeyring(current *Key, old ...*Key) *Keyring {
+	k := &Keyring{
+		current: current,
+		keys:    map[string]*Key{},
+	}
+	for _, key := range old {
+		k.keys[key.id] = key
+	}
+	if current != nil {
+		k.keys[current.id] = current
+	}
+	return k
+}
+
+// Enabled reports whether the keyring encrypts values
+func (k *Keyring) Enabled() bool {
+	return k.current != nil
+}
+
+// Encrypt encrypts s with the current key, empty strings are left as they
+// are so it is still possible to tell that nothing was stored
+func (k *Keyring) Encrypt(s string) (string, error) {
+	if k.current == nil || s == "" {
+		return s, nil
+	}
+
+	aead := k.current.aead
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(s)+aead.Overhead())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(s), []byte(k.current.id))
+
+	return prefix + k.current.id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of s, values that are not encrypted are
+// returned as they are
+func (k *Keyring) Decrypt(s string) (string, error) {
+	if !IsEncrypted(s) {
+		return s, nil
+	}
+
+	parts := strings.SplitN(s[len(prefix):], ":", 2)
+	if len(parts) != 2 {
+		return "", ErrInvalid
+	}
+
+	key, ok := k.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	nonceSize := key.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", ErrInvalid
+	}
+
+	b, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(key.id))
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return string(b), nil
+}
+
+// Current reports whether s is stored the way the keyring would store it
+// now, values that are not need to be rotated
+func (k *Keyring) Current(s string) bool {
+	if s == "" {
+		return true
+	}
+	if k.current == nil {
+		return !IsEncrypted(s)
+	}
+	return strings.HasPrefix(s, prefix+k.current.id+":")
+}
+
+// IsEncrypted reports whether s is an encrypted value
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
@@ -0,0 +1,269 @@
+package encrypt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/khlieng/dispatch/storage/storagetest"
+)
+
+func newTestKey(t *testing.T) *Key {
+	s, err := GenerateKey()
+	require.Nil(t, err)
+	key, err := ParseKey(s)
+	require.Nil(t, err)
+	return key
+}
+
+func TestKeyring(t *testing.T) {
+	oldKey := newTestKey(t)
+	newKey := newTestKey(t)
+
+	old := NewKeyring(oldKey)
+	assert.True(t, old.Enabled())
+
+	encrypted, err := old.Encrypt("hunter2")
+	require.Nil(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "hunter2")
+	assert.True(t, old.Current(encrypted))
+
+	other, err := old.Encrypt("hunter2")
+	require.Nil(t, err)
+	assert.NotEqual(t, encrypted, other)
+
+	plain, err := old.Decrypt(encrypted)
+	require.Nil(t, err)
+	assert.Equal(t, "hunter2", plain)
+
+	empty, err := old.Encrypt("")
+	require.Nil(t, err)
+	assert.Equal(t, "", empty)
+
+	plain, err = old.Decrypt("not encrypted")
+	require.Nil(t, err)
+	assert.Equal(t, "not encrypted", plain)
+	assert.False(t, old.Current("not encrypted"))
+
+	rotating := NewKeyring(newKey, oldKey)
+	assert.False(t, rotating.Current(encrypted))
+	plain, err = rotating.Decrypt(encrypted)
+	require.Nil(t, err)
+	assert.Equal(t, "hunter2", plain)
+
+	_, err = NewKeyring(newKey).Decrypt(encrypted)
+	assert.Equal(t, ErrUnknownKey, err)
+
+	_, err = NewKeyring(nil).Decrypt(encrypted)
+	assert.Equal(t, ErrUnknownKey, err)
+
+	_, err = old.Decrypt(encrypted[:len(encrypted)-2])
+	assert.Equal(t, ErrInvalid, err)
+
+	disabled := NewKeyring(nil, oldKey)
+	assert.False(t, disabled.Enabled())
+	plain, err = disabled.Encrypt("hunter2")
+	require.Nil(t, err)
+	assert.Equal(t, "hunter2", plain)
+	assert.True(t, disabled.Current("hunter2"))
+	assert.False(t, disabled.Current(encrypted))
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("not base64!")
+	assert.NotNil(t, err)
+
+	_, err = ParseKey("c2hvcnQ=")
+	assert.NotNil(t, err)
+
+	s, err := GenerateKey()
+	require.Nil(t, err)
+	a, err := ParseKey(s)
+	require.Nil(t, err)
+	b, err := ParseKey(" " + s + "\n")
+	require.Nil(t, err)
+	assert.Equal(t, a.ID(), b.ID())
+}
+
+func TestStoreConformance(t *testing.T) {
+	keys := NewKeyring(newTestKey(t))
+
+	storagetest.TestStore(t, func(t *testing.T) storage.Store {
+		return NewStore(memory.New(), keys)
+	})
+	storagetest.TestMessageStore(t, func(t *testing.T) storage.MessageStore {
+		return NewMessageStore(memory.NewMessageStore(), keys)
+	})
+}
+
+func TestStore(t *testing.T) {
+	db := memory.New()
+	oldKey := newTestKey(t)
+	store := NewStore(db, NewKeyring(oldKey))
+
+	user := &storage.User{}
+	require.Nil(t, db.SaveUser(user))
+
+	network := &storage.Network{
+		ID:             "freenode",
+		Host:           "irc.freenode.net",
+		ServerPassword: "serverpass",
+		Account:        "account",
+		Password:       "saslpass",
+	}
+	require.Nil(t, store.SaveNetwork(user, network))
+	assert.Equal(t, "saslpass", network.Password)
+
+	raw, err := db.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.True(t, IsEncrypted(raw.ServerPassword))
+	assert.True(t, IsEncrypted(raw.Password))
+	assert.Equal(t, "account", raw.Account)
+
+	stored, err := store.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.Equal(t, network, stored)
+
+	networks, err := store.Networks(user)
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.Network{network}, networks)
+
+	// Networks stored before encryption got enabled get encrypted when
+	// rotating
+	require.Nil(t, db.SaveNetwork(user, &storage.Network{ID: "oftc", Password: "plain"}))
+
+	n, err := store.Rotate(user)
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	raw, err = db.Network(user, "oftc")
+	require.Nil(t, err)
+	assert.True(t, IsEncrypted(raw.Password))
+
+	newKey := newTestKey(t)
+	store = NewStore(db, NewKeyring(newKey, oldKey))
+
+	n, err = store.Rotate(user)
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = store.Rotate(user)
+	require.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	// The old key is no longer needed
+	store = NewStore(db, NewKeyring(newKey))
+	stored, err = store.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.Equal(t, "saslpass", stored.Password)
+
+	// Rotating without a current key decrypts everything
+	store = NewStore(db, NewKeyring(nil, newKey))
+	n, err = store.Rotate(user)
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	raw, err = db.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.Equal(t, "serverpass", raw.ServerPassword)
+	assert.Equal(t, "saslpass", raw.Password)
+}
+
+func TestMessageStore(t *testing.T) {
+	db := memory.NewMessageStore()
+	store := NewMessageStore(db, NewKeyring(newTestKey(t)))
+
+	require.Nil(t, store.LogMessage(&storage.Message{
+		ID:      "a",
+		Network: "freenode",
+		From:    "nick",
+		To:      "#go",
+		Content: "secret",
+	}))
+	events := []storage.Event{{Type: "quit", Params: []string{"bob", "bye"}}}
+	require.Nil(t, store.LogMessage(&storage.Message{
+		ID:      "b",
+		Network: "freenode",
+		To:      "#go",
+		Events:  events,
+	}))
+	assert.Equal(t, "bye", events[0].Params[1])
+
+	raw, _, err := db.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	require.Len(t, raw, 2)
+	assert.Equal(t, "nick", raw[0].From)
+	assert.True(t, IsEncrypted(raw[0].Content))
+	assert.True(t, IsEncrypted(raw[1].Events[0].Params[1]))
+
+	messages, _, err := store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "secret", messages[0].Content)
+	assert.Equal(t, events, messages[1].Events)
+}
+
+func TestMessageStoreRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Rotating needs a store that replaces messages that are logged again
+	db, err := boltdb.New(filepath.Join(dir, "log.db"))
+	require.Nil(t, err)
+	defer db.Close()
+
+	require.Nil(t, db.LogMessage(&storage.Message{ID: "a", Network: "freenode", To: "#go", Content: "plain"}))
+
+	oldKey := newTestKey(t)
+	store := NewMessageStore(db, NewKeyring(oldKey))
+	require.Nil(t, store.LogMessage(&storage.Message{
+		ID:      "b",
+		Network: "freenode",
+		To:      "#go",
+		Events:  []storage.Event{{Type: "quit", Params: []string{"bob", "bye"}}},
+	}))
+
+	n, err := store.Rotate()
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	newKey := newTestKey(t)
+	store = NewMessageStore(db, NewKeyring(newKey, oldKey))
+	n, err = store.Rotate()
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = store.Rotate()
+	require.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	count, err := store.MessageCount()
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	// The old key is no longer needed
+	messages, _, err := NewMessageStore(db, NewKeyring(newKey)).Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "plain", messages[0].Content)
+	assert.Equal(t, "bye", messages[1].Events[0].Params[1])
+
+	// Rotating without a current key decrypts everything
+	n, err = NewMessageStore(db, NewKeyring(nil, newKey)).Rotate()
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	raw, _, err := db.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, "plain", raw[0].Content)
+	assert.Equal(t, "bye", raw[1].Events[0].Params[1])
+}
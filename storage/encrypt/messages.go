@@ -0,0 +1,203 @@
+package encrypt
+
+import (
+	"github.com/khlieng/dispatch/storage"
+)
+
+// MessageStore encrypts the content of messages and the params of events
+// before they reach the storage.MessageStore it wraps, the IDs, senders and
+// timestamps are left as they are so the store can still page and prune.
+// Messages get logged while users are away, so it uses the same keys as
+// Store. The search index can not be encrypted, it has to be created
+// without the content of messages when this is used.
+type MessageStore struct {
+	storage.MessageStore
+	keys *Keyring
+}
+
+func NewMessageStore(store storage.MessageStore, keys *Keyring) *MessageStore {
+	return &MessageStore{
+		MessageStore: store,
+		keys:         keys,
+	}
+}
+
+func (s *MessageStore) LogMessage(message *storage.Message) error {
+	encrypted, err := s.encryptMessage(message)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.LogMessage(encrypted)
+}
+
+func (s *MessageStore) LogMessages(messages []*storage.Message) error {
+	encrypted := make([]*storage.Message, len(messages))
+	for i, message := range messages {
+		var err error
+		encrypted[i], err = s.encryptMessage(message)
+		if err != nil {
+			return err
+		}
+	}
+	return s.MessageStore.LogMessages(encrypted)
+}
+
+func (s *MessageStore) Messages(network, channel string, count int, fromID string) ([]storage.Message, bool, error) {
+	messages, hasMore, err := s.MessageStore.Messages(network, channel, count, fromID)
+	if err != nil {
+		return nil, false, err
+	}
+	return messages, hasMore, s.decryptMessages(messages)
+}
+
+func (s *MessageStore) MessagesByID(network, channel string, ids []string) ([]storage.Message, error) {
+	messages, err := s.MessageStore.MessagesByID(network, channel, ids)
+	if err != nil {
+		return nil, err
+	}
+	return messages, s.decryptMessages(messages)
+}
+
+func (s *MessageStore) ForEachMessage(fn func(*storage.Message) error) error {
+	return s.MessageStore.ForEachMessage(func(message *storage.Message) error {
+		err := s.decryptMessage(message)
+		if err != nil {
+			return err
+		}
+		return fn(message)
+	})
+}
+
+type messageCounter interface {
+	MessageCount() (int, error)
+}
+
+// MessageCount returns the number of messages in the wrapped store, it is 0
+// if the store can not count them
+func (s *MessageStore) MessageCount() (int, error) {
+	if counter, ok := s.MessageStore.(messageCounter); ok {
+		return counter.MessageCount()
+	}
+	return 0, nil
+}
+
+// rotateBatch is how many messages Rotate logs again at once
+const rotateBatch = 1000
+
+// Rotate logs the messages that are not stored with the current key again,
+// or as plaintext when there is none, and returns how many were changed.
+// The wrapped store has to replace messages that get logged with an ID it
+// already has, which the bolt and sqlite stores do.
+func (s *MessageStore) Rotate() (int, error) {
+	rotated := 0
+	var batch []*storage.Message
+
+	flush := func() error {
+		err := s.LogMessages(batch)
+		if err != nil {
+			return err
+		}
+		rotated += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err := s.MessageStore.ForEachMessage(func(message *storage.Message) error {
+		if s.current(message) {
+			return nil
+		}
+
+		m := *message
+		err := s.decryptMessage(&m)
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, &m)
+		if len(batch) == rotateBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+
+	return rotated, err
+}
+
+func (s *MessageStore) current(message *storage.Message) bool {
+	if !s.keys.Current(message.Content) {
+		return false
+	}
+	for _, event := range message.Events {
+		for _, param := range event.Params {
+			if !s.keys.Current(param) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *MessageStore) Compact() error {
+	if compacter, ok := s.MessageStore.(storage.MessageStoreCompacter); ok {
+		return compacter.Compact()
+	}
+	return nil
+}
+
+func (s *MessageStore) encryptMessage(message *storage.Message) (*storage.Message, error) {
+	encrypted := *message
+
+	var err error
+	encrypted.Content, err = s.keys.Encrypt(message.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(message.Events) > 0 {
+		encrypted.Events = make([]storage.Event, len(message.Events))
+		for i, event := range message.Events {
+			encrypted.Events[i] = event
+			encrypted.Events[i].Params = make([]string, len(event.Params))
+			for j, param := range event.Params {
+				encrypted.Events[i].Params[j], err = s.keys.Encrypt(param)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return &encrypted, nil
+}
+
+func (s *MessageStore) decryptMessages(messages []storage.Message) error {
+	for i := range messages {
+		err := s.decryptMessage(&messages[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MessageStore) decryptMessage(message *storage.Message) error {
+	var err error
+	message.Content, err = s.keys.Decrypt(message.Content)
+	if err != nil {
+		return err
+	}
+
+	for i := range message.Events {
+		params := message.Events[i].Params
+		for j := range params {
+			params[j], err = s.keys.Decrypt(params[j])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package encrypt
+
+import (
+	"github.com/khlieng/dispatch/storage"
+)
+
+// Store encrypts the passwords of networks before they reach the
+// storage.Store it wraps and decrypts them on the way out
+type Store struct {
+	storage.Store
+	keys *Keyring
+}
+
+func NewStore(store storage.Store, keys *Keyring) *Store {
+	return &Store{
+		Store: store,
+		keys:  keys,
+	}
+}
+
+func (s *Store) Network(user *storage.User, id string) (*storage.Network, error) {
+	network, err := s.Store.Network(user, id)
+	if err != nil {
+		return nil, err
+	}
+	return network, s.decryptNetwork(network)
+}
+
+func (s *Store) Networks(user *storage.User) ([]*storage.Network, error) {
+	networks, err := s.Store.Networks(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, network := range networks {
+		err = s.decryptNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return networks, nil
+}
+
+func (s *Store) SaveNetwork(user *storage.User, network *storage.Network) error {
+	encrypted, err := s.encryptNetwork(network)
+	if err != nil {
+		return err
+	}
+	return s.Store.SaveNetwork(user, encrypted)
+}
+
+// Rotate stores the passwords of the networks of user with the current key,
+// or as plaintext when there is none, and returns how many networks were
+// changed
+func (s *Store) Rotate(user *storage.User) (int, error) {
+	networks, err := s.Store.Networks(user)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, network := range networks {
+		if s.keys.Current(network.ServerPassword) && s.keys.Current(network.Password) {
+			continue
+		}
+
+		err = s.decryptNetwork(network)
+		if err != nil {
+			return rotated, err
+		}
+
+		err = s.SaveNetwork(user, network)
+		if err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+func (s *Store) encryptNetwork(network *storage.Network) (*storage.Network, error) {
+	encrypted := *network
+
+	var err error
+	encrypted.ServerPassword, err = s.keys.Encrypt(network.ServerPassword)
+	if err != nil {
+		return nil, err
+	}
+	encrypted.Password, err = s.keys.Encrypt(network.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encrypted, nil
+}
+
+func (s *Store) decryptNetwork(network *storage.Network) error {
+	var err error
+	network.ServerPassword, err = s.keys.Decrypt(network.ServerPassword)
+	if err != nil {
+		return err
+	}
+	network.Password, err = s.keys.Decrypt(network.Password)
+	return err
+}
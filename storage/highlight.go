@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxMentions is how many mentions get kept per user, the oldest ones get
+// deleted first
+var MaxMentions = 500
+
+// HighlightRules decide which messages highlight the user
+type HighlightRules struct {
+	// Nick highlights messages that contain the nick of the user
+	Nick bool
+	// Keywords highlight messages that contain them as words, case does
+	// not matter
+	Keywords []string
+	// Patterns are regular expressions that highlight the messages they match
+	Patterns []string
+	// Muted channels and private chats never highlight
+	Muted []Tab
+}
+
+func DefaultHighlightRules() *HighlightRules {
+	return &HighlightRules{
+		Nick: true,
+	}
+}
+
+// IsMuted returns true if network and channel do not highlight
+func (r *HighlightRules) IsMuted(network, channel string) bool {
+	for _, tab := range r.Muted {
+		if tab.Network == network && strings.EqualFold(tab.Name, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Highlighter matches messages against a set of highlight rules
+type Highlighter struct {
+	rules    *HighlightRules
+	patterns []*regexp.Regexp
+}
+
+func NewHighlighter(rules *HighlightRules) (*Highlighter, error) {
+	h := &Highlighter{
+		rules: rules,
+	}
+
+	for _, pattern := range rules.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid highlight pattern %q: %v", pattern, err)
+		}
+		h.patterns = append(h.patterns, re)
+	}
+
+	return h, nil
+}
+
+// Match returns true if a message from from to channel on network
+// highlights a user with the nick nick
+func (h *Highlighter) Match(nick, network, channel, from, content string) bool {
+	if strings.EqualFold(from, nick) || h.rules.IsMuted(network, channel) {
+		return false
+	}
+
+	if h.rules.Nick && nick != "" && containsWord(content, nick) {
+		return true
+	}
+
+	for _, keyword := range h.rules.Keywords {
+		if keyword != "" && containsWord(content, keyword) {
+			return true
+		}
+	}
+
+	for _, re := range h.patterns {
+		if re.MatchString(content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsWord returns true if word is in s without letters or digits
+// right next to it, case does not matter
+func containsWord(s, word string) bool {
+	s = strings.ToLower(s)
+	word = strings.ToLower(word)
+
+	for i := 0; i < len(s); {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(s[start:])
+		i = start + size
+	}
+
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// Mention is a message that highlighted the user
+type Mention struct {
+	ID      string
+	Network string
+	To      string
+	From    string
+	Content string
+	Time    int64
+	Read    bool
+}
+
+func (u *User) highlighter() (*Highlighter, error) {
+	u.lock.Lock()
+	h := u.highlights
+	u.lock.Unlock()
+	if h != nil {
+		return h, nil
+	}
+
+	rules, err := u.HighlightRules()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err = NewHighlighter(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	u.lock.Lock()
+	u.highlights = h
+	u.lock.Unlock()
+
+	return h, nil
+}
+
+// HighlightRules returns the highlight rules of the user, the default
+// rules are used until the user saves some
+func (u *User) HighlightRules() (*HighlightRules, error) {
+	rules, err := u.store.HighlightRules(u)
+	if err == ErrNotFound {
+		return DefaultHighlightRules(), nil
+	}
+	return rules, err
+}
+
+func (u *User) SetHighlightRules(rules *HighlightRules) error {
+	h, err := NewHighlighter(rules)
+	if err != nil {
+		return err
+	}
+
+	err = u.store.SaveHighlightRules(u, rules)
+	if err != nil {
+		return err
+	}
+
+	u.lock.Lock()
+	u.highlights = h
+	u.lock.Unlock()
+
+	return nil
+}
+
+// IsHighlight returns true if the message highlights the user, nick is the
+// nick of the user on the network the message is from
+func (u *User) IsHighlight(nick string, msg *Message) bool {
+	h, err := u.highlighter()
+	if err != nil {
+		return false
+	}
+	return h.Match(nick, msg.Network, msg.To, msg.From, msg.Content)
+}
+
+// IsMuted returns true if the user muted channel on network, private chats
+// are muted by the nick of the other user
+func (u *User) IsMuted(network, channel string) bool {
+	h, err := u.highlighter()
+	if err != nil {
+		return false
+	}
+	return h.rules.IsMuted(network, channel)
+}
+
+// AddMention stores a message that highlighted the user, the oldest
+// mentions get deleted when there are more than MaxMentions
+func (u *User) AddMention(msg *Message) error {
+	mention := &Mention{
+		ID:      msg.ID,
+		Network: msg.Network,
+		To:      msg.To,
+		From:    msg.From,
+		Content: msg.Content,
+		Time:    msg.Time,
+	}
+	if mention.Time == 0 {
+		mention.Time = time.Now().Unix()
+	}
+
+	err := u.store.SaveMention(u, mention)
+	if err != nil {
+		return err
+	}
+
+	mentions, err := u.store.Mentions(u)
+	if err != nil || len(mentions) <= MaxMentions {
+		return err
+	}
+
+	var ids []string
+	for _, m := range mentions[:len(mentions)-MaxMentions] {
+		ids = append(ids, m.ID)
+	}
+	return u.store.RemoveMentions(u, ids)
+}
+
+// Mentions returns the mentions of the user across all networks, oldest
+// first. Only the ones that have not been read are returned when unread
+// is true.
+func (u *User) Mentions(unread bool) ([]*Mention, error) {
+	mentions, err := u.store.Mentions(u)
+	if err != nil || !unread {
+		return mentions, err
+	}
+
+	result := []*Mention{}
+	for _, m := range mentions {
+		if !m.Read {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// MarkMentionsRead marks the mentions with the given IDs as read, all of
+// them get marked if no IDs are passed
+func (u *User) MarkMentionsRead(ids ...string) error {
+	mentions, err := u.store.Mentions(u)
+	if err != nil {
+		return err
+	}
+
+	marking := map[string]bool{}
+	for _, id := range ids {
+		marking[id] = true
+	}
+
+	for _, m := range mentions {
+		if !m.Read && (len(ids) == 0 || marking[m.ID]) {
+			m.Read = true
+
+			err = u.store.SaveMention(u, m)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
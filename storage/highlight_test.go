@@ -0,0 +1,113 @@
+package storage_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+func TestHighlighter(t *testing.T) {
+	h, err := storage.NewHighlighter(&storage.HighlightRules{
+		Nick:     true,
+		Keywords: []string{"dispatch", "Go Team"},
+		Patterns: []string{`\bdeploy(ed|ing)?\b`},
+		Muted:    []storage.Tab{{Network: "freenode", Name: "#spam"}},
+	})
+	require.Nil(t, err)
+
+	cases := []struct {
+		channel string
+		from    string
+		content string
+		match   bool
+	}{
+		{"#go", "bob", "hi alice", true},
+		{"#go", "bob", "ALICE: ping", true},
+		{"#go", "bob", "alice_ are you there", true},
+		{"#go", "bob", "malice and alices", false},
+		{"#go", "bob", "using dispatch today", true},
+		{"#go", "bob", "dispatcher", false},
+		{"#go", "bob", "go team!", true},
+		{"#go", "bob", "we deployed it", true},
+		{"#go", "bob", "redeployment", false},
+		{"#go", "bob", "nothing to see", false},
+		{"#spam", "bob", "hi alice", false},
+		{"#SPAM", "bob", "hi alice", false},
+		{"#go", "Alice", "I am alice", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.match, h.Match("alice", "freenode", tc.channel, tc.from, tc.content), tc.content)
+	}
+
+	// The nick only highlights when it is turned on
+	h, err = storage.NewHighlighter(&storage.HighlightRules{})
+	require.Nil(t, err)
+	assert.False(t, h.Match("alice", "freenode", "#go", "bob", "hi alice"))
+
+	_, err = storage.NewHighlighter(&storage.HighlightRules{Patterns: []string{"("}})
+	assert.NotNil(t, err)
+}
+
+func TestUserHighlights(t *testing.T) {
+	forEachBackend(t, testUserHighlights)
+}
+
+func testUserHighlights(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	rules, err := user.HighlightRules()
+	require.Nil(t, err)
+	assert.Equal(t, storage.DefaultHighlightRules(), rules)
+
+	msg := &storage.Message{Network: "freenode", To: "#go", From: "bob", Content: "dispatch"}
+	assert.False(t, user.IsHighlight("alice", msg))
+
+	assert.NotNil(t, user.SetHighlightRules(&storage.HighlightRules{Patterns: []string{"("}}))
+
+	require.Nil(t, user.SetHighlightRules(&storage.HighlightRules{Keywords: []string{"dispatch"}}))
+	assert.True(t, user.IsHighlight("alice", msg))
+
+	defer func(max int) { storage.MaxMentions = max }(storage.MaxMentions)
+	storage.MaxMentions = 5
+
+	for i := 0; i < storage.MaxMentions+2; i++ {
+		require.Nil(t, user.AddMention(&storage.Message{
+			ID:      betterguid.New(),
+			Network: "freenode",
+			To:      "#go",
+			From:    "bob",
+			Content: strconv.Itoa(i),
+		}))
+	}
+
+	mentions, err := user.Mentions(true)
+	require.Nil(t, err)
+	require.Len(t, mentions, storage.MaxMentions)
+	assert.Equal(t, "2", mentions[0].Content)
+	assert.NotZero(t, mentions[0].Time)
+
+	require.Nil(t, user.MarkMentionsRead(mentions[0].ID, mentions[1].ID))
+	mentions, err = user.Mentions(true)
+	require.Nil(t, err)
+	assert.Len(t, mentions, storage.MaxMentions-2)
+
+	require.Nil(t, user.MarkMentionsRead())
+	mentions, err = user.Mentions(true)
+	require.Nil(t, err)
+	assert.Len(t, mentions, 0)
+
+	mentions, err = user.Mentions(false)
+	require.Nil(t, err)
+	assert.Len(t, mentions, storage.MaxMentions)
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kjk/betterguid"
+)
+
+// The kinds of messages an ignore rule can drop
+const (
+	IgnoreMessages = "messages"
+	// IgnoreCTCP stops CTCP requests from getting automatic replies
+	IgnoreCTCP = "ctcp"
+	// IgnoreJoins covers joins, parts and quits
+	IgnoreJoins = "joins"
+	IgnoreDCC   = "dcc"
+)
+
+var IgnoreTypes = []string{IgnoreMessages, IgnoreCTCP, IgnoreJoins, IgnoreDCC}
+
+var (
+	ErrIgnoreNoTarget = errors.New("An ignore rule needs a nick or a mask")
+	ErrIgnoreType     = errors.New("Unknown ignore type")
+)
+
+// IgnoreRule drops messages from a nick or from users matching a hostmask
+type IgnoreRule struct {
+	ID   string
+	Nick string
+	// Mask is matched against nick!ident@host, it is a glob where * matches
+	// anything and ? matches a single character unless Regex is set. Case
+	// does not matter.
+	Mask  string
+	Regex bool
+	// Network and Channel limit the rule to a network, or a channel or
+	// private chat on it
+	Network string
+	Channel string
+	// Types are the kinds of messages that get dropped, all of them when
+	// it is empty
+	Types []string
+}
+
+// Validate returns an error if the rule can not be used
+func (r *IgnoreRule) Validate() error {
+	_, err := r.compile()
+	return err
+}
+
+func (r *IgnoreRule) compile() (*regexp.Regexp, error) {
+	if r.Nick == "" && r.Mask == "" {
+		return nil, ErrIgnoreNoTarget
+	}
+
+	for _, t := range r.Types {
+		if !isIgnoreType(t) {
+			return nil, ErrIgnoreType
+		}
+	}
+
+	if r.Mask == "" {
+		return nil, nil
+	}
+
+	pattern := r.Mask
+	if !r.Regex {
+		pattern = globToRegexp(pattern)
+	}
+
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore mask %q: %v", r.Mask, err)
+	}
+	return re, nil
+}
+
+func (r *IgnoreRule) drops(t string) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, typ := range r.Types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+func isIgnoreType(t string) bool {
+	for _, typ := range IgnoreTypes {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteByte('.')
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteByte('$')
+	return sb.String()
+}
+
+// Ignorer matches messages against a set of ignore rules
+type Ignorer struct {
+	rules []*IgnoreRule
+	masks []*regexp.Regexp
+}
+
+func NewIgnorer(rules []*IgnoreRule) (*Ignorer, error) {
+	i := &Ignorer{
+		rules: rules,
+		masks: make([]*regexp.Regexp, len(rules)),
+	}
+
+	for n, rule := range rules {
+		re, err := rule.compile()
+		if err != nil {
+			return nil, err
+		}
+		i.masks[n] = re
+	}
+
+	return i, nil
+}
+
+// Match returns true if a message of type t from nick!ident@host to channel
+// on network should be dropped, channel is empty for messages that are not
+// sent to a channel or private chat
+func (i *Ignorer) Match(t, network, channel, nick, ident, host string) bool {
+	mask := nick + "!" + ident + "@" + host
+
+	for n, rule := range i.rules {
+		if !rule.drops(t) ||
+			(rule.Network != "" && rule.Network != network) ||
+			(rule.Channel != "" && !strings.EqualFold(rule.Channel, channel)) {
+			continue
+		}
+
+		if rule.Nick != "" && !strings.EqualFold(rule.Nick, nick) {
+			continue
+		}
+		if i.masks[n] != nil && !i.masks[n].MatchString(mask) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (u *User) ignorer() (*Ignorer, error) {
+	u.lock.Lock()
+	i := u.ignores
+	u.lock.Unlock()
+	if i != nil {
+		return i, nil
+	}
+
+	rules, err := u.store.IgnoreRules(u)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err = NewIgnorer(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	u.lock.Lock()
+	u.ignores = i
+	u.lock.Unlock()
+
+	return i, nil
+}
+
+func (u *User) IgnoreRules() ([]*IgnoreRule, error) {
+	return u.store.IgnoreRules(u)
+}
+
+// AddIgnoreRule stores a rule, it gets an ID if it has none, rules with an
+// ID that is already in use replace the rule with that ID
+func (u *User) AddIgnoreRule(rule *IgnoreRule) error {
+	err := rule.Validate()
+	if err != nil {
+		return err
+	}
+
+	if rule.ID == "" {
+		rule.ID = betterguid.New()
+	}
+
+	err = u.store.SaveIgnoreRule(u, rule)
+	if err != nil {
+		return err
+	}
+
+	u.resetIgnorer()
+	return nil
+}
+
+func (u *User) RemoveIgnoreRule(id string) error {
+	err := u.store.RemoveIgnoreRule(u, id)
+	if err != nil {
+		return err
+	}
+
+	u.resetIgnorer()
+	return nil
+}
+
+func (u *User) resetIgnorer() {
+	u.lock.Lock()
+	u.ignores = nil
+	u.lock.Unlock()
+}
+
+// IsIgnored returns true if a message of type t from nick!ident@host to
+// channel on network should be dropped
+func (u *User) IsIgnored(t, network, channel, nick, ident, host string) bool {
+	i, err := u.ignorer()
+	if err != nil {
+		return false
+	}
+	return i.Match(t, network, channel, nick, ident, host)
+}
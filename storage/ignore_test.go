@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+func TestIgnorer(t *testing.T) {
+	i, err := storage.NewIgnorer([]*storage.IgnoreRule{
+		{Nick: "Troll"},
+		{Mask: "*!*@*.spam.example.com"},
+		{Mask: `^bot\d+!`, Regex: true, Types: []string{storage.IgnoreJoins}},
+		{Nick: "chatty", Network: "freenode", Channel: "#go"},
+		{Nick: "sender", Types: []string{storage.IgnoreDCC, storage.IgnoreCTCP}},
+	})
+	require.Nil(t, err)
+
+	cases := []struct {
+		t       string
+		network string
+		channel string
+		nick    string
+		host    string
+		match   bool
+	}{
+		{storage.IgnoreMessages, "freenode", "#go", "troll", "example.com", true},
+		{storage.IgnoreJoins, "efnet", "#other", "TROLL", "example.com", true},
+		{storage.IgnoreMessages, "freenode", "#go", "trolling", "example.com", false},
+		{storage.IgnoreMessages, "freenode", "#go", "bob", "a.spam.example.com", true},
+		{storage.IgnoreMessages, "freenode", "#go", "bob", "A.SPAM.example.com", true},
+		{storage.IgnoreMessages, "freenode", "#go", "bob", "spam.example.com", false},
+		{storage.IgnoreMessages, "freenode", "#go", "bob", "aspam.example.com", false},
+		{storage.IgnoreJoins, "freenode", "#go", "bot12", "example.com", true},
+		{storage.IgnoreMessages, "freenode", "#go", "bot12", "example.com", false},
+		{storage.IgnoreJoins, "freenode", "#go", "robot12", "example.com", false},
+		{storage.IgnoreMessages, "freenode", "#go", "chatty", "example.com", true},
+		{storage.IgnoreMessages, "freenode", "#GO", "chatty", "example.com", true},
+		{storage.IgnoreMessages, "freenode", "#rust", "chatty", "example.com", false},
+		{storage.IgnoreMessages, "efnet", "#go", "chatty", "example.com", false},
+		{storage.IgnoreDCC, "freenode", "", "sender", "example.com", true},
+		{storage.IgnoreCTCP, "freenode", "", "sender", "example.com", true},
+		{storage.IgnoreMessages, "freenode", "sender", "sender", "example.com", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.match, i.Match(tc.t, tc.network, tc.channel, tc.nick, "ident", tc.host),
+			"%s %s!ident@%s in %s", tc.t, tc.nick, tc.host, tc.channel)
+	}
+}
+
+func TestIgnoreRuleValidate(t *testing.T) {
+	assert.Nil(t, (&storage.IgnoreRule{Nick: "troll"}).Validate())
+	assert.Nil(t, (&storage.IgnoreRule{Mask: "troll!*@*"}).Validate())
+	assert.Equal(t, storage.ErrIgnoreNoTarget, (&storage.IgnoreRule{Network: "freenode"}).Validate())
+	assert.Equal(t, storage.ErrIgnoreType, (&storage.IgnoreRule{Nick: "troll", Types: []string{"nope"}}).Validate())
+	assert.NotNil(t, (&storage.IgnoreRule{Mask: "(", Regex: true}).Validate())
+	// Globs never fail to compile
+	assert.Nil(t, (&storage.IgnoreRule{Mask: "(*"}).Validate())
+}
+
+func TestUserIgnores(t *testing.T) {
+	forEachBackend(t, testUserIgnores)
+}
+
+func testUserIgnores(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	assert.False(t, user.IsIgnored(storage.IgnoreMessages, "freenode", "#go", "troll", "ident", "host"))
+
+	assert.NotNil(t, user.AddIgnoreRule(&storage.IgnoreRule{}))
+
+	rule := &storage.IgnoreRule{Nick: "troll"}
+	require.Nil(t, user.AddIgnoreRule(rule))
+	assert.NotEmpty(t, rule.ID)
+	assert.True(t, user.IsIgnored(storage.IgnoreMessages, "freenode", "#go", "troll", "ident", "host"))
+
+	rules, err := user.IgnoreRules()
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.IgnoreRule{rule}, rules)
+
+	require.Nil(t, user.RemoveIgnoreRule(rule.ID))
+	assert.False(t, user.IsIgnored(storage.IgnoreMessages, "freenode", "#go", "troll", "ident", "host"))
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+)
+
+// LinkMeta is what got fetched for a link, Meta is nil when the link could
+// not be fetched or had no metadata, that way it does not get fetched again
+type LinkMeta struct {
+	URL  string
+	Meta *linkmeta.Meta
+	// Time is the unix time the link was fetched
+	Time int64
+}
+
+func (m *LinkMeta) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m *LinkMeta) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, m)
+}
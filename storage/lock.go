@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+// ErrLocked is returned by LockDataDir when another process holds the lock
+var ErrLocked = errors.New("the data directory is in use by another dispatch process")
+
+// dataDirLock keeps the lock file open, closing it releases the lock
+var dataDirLock *os.File
+
+// LockDataDir takes the lock on the data directory, dispatch and the
+// commands that work on the stored data take it so only one of them uses
+// the data at a time, whatever the storage backend. It is held until the
+// process exits.
+func LockDataDir() error {
+	if dataDirLock != nil {
+		return nil
+	}
+
+	f, err := lockFile(Path.Lock())
+	if err != nil {
+		return err
+	}
+	dataDirLock = f
+	return nil
+}
+
+// lockFile creates the file at path if needed and locks it, the PID of
+// the process holding the lock gets written to it
+func lockFile(path string) (*os.File, error) {
+	f, err := openLocked(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.Truncate(0)
+	if err == nil {
+		_, err = f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dispatch")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dispatch.lock")
+
+	f, err := lockFile(path)
+	require.Nil(t, err)
+
+	// Windows does not let anything else open the file while it is locked
+	data, err := ioutil.ReadAll(f)
+	require.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid()), string(data))
+
+	_, err = lockFile(path)
+	assert.Equal(t, ErrLocked, err)
+
+	// It is free again once the holder lets go of it
+	f.Close()
+	f, err = lockFile(path)
+	require.Nil(t, err)
+	f.Close()
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"os"
+	"syscall"
+)
+
+const errSharingViolation syscall.Errno = 32
+
+// openLocked opens the file at path without sharing it, other processes
+// can not open it until this one closes it or exits
+func openLocked(path string) (*os.File, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := syscall.CreateFile(p, syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if err == errSharingViolation {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
@@ -0,0 +1,694 @@
+// Package memory keeps everything in memory, nothing survives a restart.
+// It is meant for tests and deployments that should not write to disk.
+package memory
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// Memory implements storage.Store, storage.SessionStore and
+// storage.LinkMetaStore, MessageStore and
+// MessageSearchProvider return the message log and search index of a user.
+// Everything is kept marshaled the same way the other stores keep it on
+// disk, that way callers never share data with the store.
+type Memory struct {
+	lock     sync.RWMutex
+	userSeq  uint64
+	users    map[uint64][]byte
+	networks map[uint64]map[string][]byte
+	channels map[uint64]map[storage.Tab][]byte
+	openDMs  map[uint64]map[storage.Tab]bool
+	sessions map[string][]byte
+	linkMeta map[string][]byte
+	// highlights and mentions are kept by user ID, mentions by their ID
+	highlights map[uint64][]byte
+	mentions   map[uint64]map[string][]byte
+	// push subscriptions are kept by their endpoint
+	push map[uint64]map[string][]byte
+	// ignore rules are kept by their ID
+	ignores map[uint64]map[string][]byte
+	markers map[uint64]map[storage.Tab][]byte
+	// API tokens are kept by their ID
+	tokens map[uint64]map[string][]byte
+	// renames are the new IDs of networks, kept by their current ID
+	renames map[uint64]map[string]string
+
+	messageStores map[uint64]*MessageStore
+	indexes       map[uint64]*Index
+}
+
+func New() *Memory {
+	return &Memory{
+		users:         map[uint64][]byte{},
+		networks:      map[uint64]map[string][]byte{},
+		channels:      map[uint64]map[storage.Tab][]byte{},
+		openDMs:       map[uint64]map[storage.Tab]bool{},
+		sessions:      map[string][]byte{},
+		linkMeta:      map[string][]byte{},
+		highlights:    map[uint64][]byte{},
+		mentions:      map[uint64]map[string][]byte{},
+		push:          map[uint64]map[string][]byte{},
+		ignores:       map[uint64]map[string][]byte{},
+		markers:       map[uint64]map[storage.Tab][]byte{},
+		tokens:        map[uint64]map[string][]byte{},
+		renames:       map[uint64]map[string]string{},
+		messageStores: map[uint64]*MessageStore{},
+		indexes:       map[uint64]*Index{},
+	}
+}
+
+// Close does nothing, it is there to match the other stores
+func (m *Memory) Close() {}
+
+func (m *Memory) Users() ([]*storage.User, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	users := make([]*storage.User, 0, len(m.users))
+	for id, data := range m.users {
+		user := storage.User{}
+		_, err := user.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		user.ID = id
+		user.IDBytes = idToBytes(id)
+
+		users = append(users, &user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users, nil
+}
+
+func (m *Memory) SaveUser(user *storage.User) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if user.ID == 0 {
+		m.userSeq++
+		user.ID = m.userSeq
+		user.IDBytes = idToBytes(user.ID)
+	} else if user.ID > m.userSeq {
+		m.userSeq = user.ID
+	}
+	user.Username = strconv.FormatUint(user.ID, 10)
+
+	data, err := user.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.users[user.ID] = data
+	return nil
+}
+
+func (m *Memory) DeleteUser(user *storage.User) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.users, user.ID)
+	delete(m.networks, user.ID)
+	delete(m.channels, user.ID)
+	delete(m.openDMs, user.ID)
+	delete(m.highlights, user.ID)
+	delete(m.mentions, user.ID)
+	delete(m.push, user.ID)
+	delete(m.ignores, user.ID)
+	delete(m.markers, user.ID)
+	delete(m.tokens, user.ID)
+	delete(m.renames, user.ID)
+	delete(m.messageStores, user.ID)
+	delete(m.indexes, user.ID)
+	return nil
+}
+
+func (m *Memory) Network(user *storage.User, id string) (*storage.Network, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	data, ok := m.networks[user.ID][id]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	network := &storage.Network{}
+	_, err := network.Unmarshal(data)
+	return network, err
+}
+
+func (m *Memory) Networks(user *storage.User) ([]*storage.Network, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var networks []*storage.Network
+	for _, data := range m.networks[user.ID] {
+		network := storage.Network{}
+		_, err := network.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, &network)
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].ID < networks[j].ID
+	})
+
+	return networks, nil
+}
+
+func (m *Memory) SaveNetwork(user *storage.User, network *storage.Network) error {
+	data, err := network.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.networks[user.ID] == nil {
+		m.networks[user.ID] = map[string][]byte{}
+	}
+	m.networks[user.ID][network.ID] = data
+	return nil
+}
+
+func (m *Memory) RemoveNetwork(user *storage.User, id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.networks[user.ID], id)
+	delete(m.renames[user.ID], id)
+
+	for tab := range m.channels[user.ID] {
+		if tab.Network == id {
+			delete(m.channels[user.ID], tab)
+		}
+	}
+	for tab := range m.openDMs[user.ID] {
+		if tab.Network == id {
+			delete(m.openDMs[user.ID], tab)
+		}
+	}
+	for tab := range m.markers[user.ID] {
+		if tab.Network == id {
+			delete(m.markers[user.ID], tab)
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) NetworkRenames(user *storage.User) (map[string]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	renames := map[string]string{}
+	for from, to := range m.renames[user.ID] {
+		renames[from] = to
+	}
+	return renames, nil
+}
+
+func (m *Memory) SaveNetworkRename(user *storage.User, from, to string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.renames[user.ID] == nil {
+		m.renames[user.ID] = map[string]string{}
+	}
+	m.renames[user.ID][from] = to
+	return nil
+}
+
+func (m *Memory) Channels(user *storage.User) ([]*storage.Channel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var channels []*storage.Channel
+	for _, data := range m.channels[user.ID] {
+		channel := storage.Channel{}
+		_, err := channel.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, &channel)
+	}
+
+	sort.Slice(channels, func(i, j int) bool {
+		return tabLess(
+			storage.Tab{Network: channels[i].Network, Name: channels[i].Name},
+			storage.Tab{Network: channels[j].Network, Name: channels[j].Name},
+		)
+	})
+
+	return channels, nil
+}
+
+func (m *Memory) SaveChannel(user *storage.User, channel *storage.Channel) error {
+	data, err := channel.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.channels[user.ID] == nil {
+		m.channels[user.ID] = map[storage.Tab][]byte{}
+	}
+	m.channels[user.ID][storage.Tab{Network: channel.Network, Name: channel.Name}] = data
+	return nil
+}
+
+func (m *Memory) RemoveChannel(user *storage.User, network, channel string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.channels[user.ID], storage.Tab{Network: network, Name: channel})
+	return nil
+}
+
+func (m *Memory) HasChannel(user *storage.User, network, channel string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, ok := m.channels[user.ID][storage.Tab{Network: network, Name: channel}]
+	return ok
+}
+
+func (m *Memory) OpenDMs(user *storage.User) ([]storage.Tab, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var openDMs []storage.Tab
+	for tab := range m.openDMs[user.ID] {
+		openDMs = append(openDMs, tab)
+	}
+
+	sort.Slice(openDMs, func(i, j int) bool {
+		return tabLess(openDMs[i], openDMs[j])
+	})
+
+	return openDMs, nil
+}
+
+func (m *Memory) AddOpenDM(user *storage.User, network, nick string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.openDMs[user.ID] == nil {
+		m.openDMs[user.ID] = map[storage.Tab]bool{}
+	}
+	m.openDMs[user.ID][storage.Tab{Network: network, Name: nick}] = true
+	return nil
+}
+
+func (m *Memory) RemoveOpenDM(user *storage.User, network, nick string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.openDMs[user.ID], storage.Tab{Network: network, Name: nick})
+	return nil
+}
+
+func (m *Memory) HighlightRules(user *storage.User) (*storage.HighlightRules, error) {
+	m.lock.RLock()
+	data, ok := m.highlights[user.ID]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	rules := &storage.HighlightRules{}
+	_, err := rules.Unmarshal(data)
+	return rules, err
+}
+
+func (m *Memory) SaveHighlightRules(user *storage.User, rules *storage.HighlightRules) error {
+	data, err := rules.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.highlights[user.ID] = data
+	return nil
+}
+
+func (m *Memory) Mentions(user *storage.User) ([]*storage.Mention, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var mentions []*storage.Mention
+	for _, data := range m.mentions[user.ID] {
+		mention := storage.Mention{}
+		_, err := mention.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, &mention)
+	}
+
+	sort.Slice(mentions, func(i, j int) bool {
+		return mentions[i].ID < mentions[j].ID
+	})
+
+	return mentions, nil
+}
+
+func (m *Memory) SaveMention(user *storage.User, mention *storage.Mention) error {
+	data, err := mention.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.mentions[user.ID] == nil {
+		m.mentions[user.ID] = map[string][]byte{}
+	}
+	m.mentions[user.ID][mention.ID] = data
+	return nil
+}
+
+func (m *Memory) RemoveMentions(user *storage.User, ids []string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, id := range ids {
+		delete(m.mentions[user.ID], id)
+	}
+	return nil
+}
+
+func (m *Memory) PushSubscriptions(user *storage.User) ([]*storage.PushSubscription, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var subs []*storage.PushSubscription
+	for _, data := range m.push[user.ID] {
+		sub := storage.PushSubscription{}
+		_, err := sub.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, &sub)
+	}
+
+	return subs, nil
+}
+
+func (m *Memory) SavePushSubscription(user *storage.User, sub *storage.PushSubscription) error {
+	data, err := sub.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.push[user.ID] == nil {
+		m.push[user.ID] = map[string][]byte{}
+	}
+	m.push[user.ID][sub.Endpoint] = data
+	return nil
+}
+
+func (m *Memory) RemovePushSubscription(user *storage.User, endpoint string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.push[user.ID], endpoint)
+	return nil
+}
+
+func (m *Memory) IgnoreRules(user *storage.User) ([]*storage.IgnoreRule, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var rules []*storage.IgnoreRule
+	for _, data := range m.ignores[user.ID] {
+		rule := storage.IgnoreRule{}
+		_, err := rule.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return rules, nil
+}
+
+func (m *Memory) SaveIgnoreRule(user *storage.User, rule *storage.IgnoreRule) error {
+	data, err := rule.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.ignores[user.ID] == nil {
+		m.ignores[user.ID] = map[string][]byte{}
+	}
+	m.ignores[user.ID][rule.ID] = data
+	return nil
+}
+
+func (m *Memory) RemoveIgnoreRule(user *storage.User, id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.ignores[user.ID], id)
+	return nil
+}
+
+func (m *Memory) ReadMarker(user *storage.User, network, channel string) (*storage.ReadMarker, error) {
+	m.lock.RLock()
+	data, ok := m.markers[user.ID][storage.Tab{Network: network, Name: channel}]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	marker := &storage.ReadMarker{}
+	_, err := marker.Unmarshal(data)
+	return marker, err
+}
+
+func (m *Memory) ReadMarkers(user *storage.User) ([]*storage.ReadMarker, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var markers []*storage.ReadMarker
+	for _, data := range m.markers[user.ID] {
+		marker := storage.ReadMarker{}
+		_, err := marker.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		markers = append(markers, &marker)
+	}
+
+	return markers, nil
+}
+
+func (m *Memory) SaveReadMarker(user *storage.User, marker *storage.ReadMarker) error {
+	data, err := marker.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.markers[user.ID] == nil {
+		m.markers[user.ID] = map[storage.Tab][]byte{}
+	}
+	m.markers[user.ID][storage.Tab{Network: marker.Network, Name: marker.Channel}] = data
+	return nil
+}
+
+func (m *Memory) APITokens(user *storage.User) ([]*storage.APIToken, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var tokens []*storage.APIToken
+	for _, data := range m.tokens[user.ID] {
+		token := storage.APIToken{}
+		_, err := token.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ID < tokens[j].ID
+	})
+
+	return tokens, nil
+}
+
+func (m *Memory) SaveAPIToken(user *storage.User, token *storage.APIToken) error {
+	data, err := token.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.tokens[user.ID] == nil {
+		m.tokens[user.ID] = map[string][]byte{}
+	}
+	m.tokens[user.ID][token.ID] = data
+	return nil
+}
+
+func (m *Memory) RemoveAPIToken(user *storage.User, id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.tokens[user.ID], id)
+	return nil
+}
+
+func (m *Memory) Sessions() ([]*session.Session, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var sessions []*session.Session
+	for _, data := range m.sessions {
+		session := session.Session{}
+		_, err := session.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, nil
+}
+
+func (m *Memory) SaveSession(session *session.Session) error {
+	data, err := session.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.sessions[session.Key()] = data
+	return nil
+}
+
+func (m *Memory) DeleteSession(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.sessions, key)
+	return nil
+}
+
+func (m *Memory) LinkMeta(url string) (*storage.LinkMeta, error) {
+	m.lock.RLock()
+	data, ok := m.linkMeta[url]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	meta := &storage.LinkMeta{}
+	return meta, meta.Unmarshal(data)
+}
+
+func (m *Memory) SaveLinkMeta(meta *storage.LinkMeta) error {
+	data, err := meta.Marshal()
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.linkMeta[meta.URL] = data
+	return nil
+}
+
+func (m *Memory) PruneLinkMeta(before int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for url, data := range m.linkMeta {
+		meta := storage.LinkMeta{}
+		err := meta.Unmarshal(data)
+		if err != nil || meta.Time < before {
+			delete(m.linkMeta, url)
+		}
+	}
+	return nil
+}
+
+// MessageStore returns the message log of a user, it lives until the user
+// gets deleted
+func (m *Memory) MessageStore(user *storage.User) *MessageStore {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	store, ok := m.messageStores[user.ID]
+	if !ok {
+		store = NewMessageStore()
+		m.messageStores[user.ID] = store
+	}
+	return store
+}
+
+// MessageSearchProvider returns the search index of a user, it lives until
+// the user gets deleted
+func (m *Memory) MessageSearchProvider(user *storage.User) *Index {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	index, ok := m.indexes[user.ID]
+	if !ok {
+		index = NewIndex()
+		m.indexes[user.ID] = index
+	}
+	return index
+}
+
+func tabLess(a, b storage.Tab) bool {
+	if a.Network != b.Network {
+		return a.Network < b.Network
+	}
+	return a.Name < b.Name
+}
+
+func idToBytes(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return b
+}
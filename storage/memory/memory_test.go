@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/storagetest"
+)
+
+func TestStore(t *testing.T) {
+	storagetest.TestStore(t, func(t *testing.T) storage.Store {
+		return New()
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	storagetest.TestSessionStore(t, func(t *testing.T) storage.SessionStore {
+		return New()
+	})
+}
+
+func TestLinkMetaStore(t *testing.T) {
+	storagetest.TestLinkMetaStore(t, func(t *testing.T) storage.LinkMetaStore {
+		return New()
+	})
+}
+
+func TestMessageStore(t *testing.T) {
+	storagetest.TestMessageStore(t, func(t *testing.T) storage.MessageStore {
+		return NewMessageStore()
+	})
+}
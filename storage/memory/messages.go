@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// MessageStore implements storage.MessageStore, the messages of each
+// channel are kept sorted by ID
+type MessageStore struct {
+	lock     sync.RWMutex
+	channels map[storage.Tab][]storage.Message
+}
+
+func NewMessageStore() *MessageStore {
+	return &MessageStore{
+		channels: map[storage.Tab][]storage.Message{},
+	}
+}
+
+// Close does nothing, the messages are kept until the user gets deleted
+func (s *MessageStore) Close() {}
+
+func (s *MessageStore) LogMessage(message *storage.Message) error {
+	return s.LogMessages([]*storage.Message{message})
+}
+
+func (s *MessageStore) LogMessages(messages []*storage.Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, message := range messages {
+		tab := storage.Tab{Network: message.Network, Name: message.To}
+		msgs := s.channels[tab]
+
+		msg := copyMessage(message)
+		msg.Network = ""
+		msg.To = ""
+
+		i := sort.Search(len(msgs), func(i int) bool {
+			return msgs[i].ID >= msg.ID
+		})
+
+		if i < len(msgs) && msgs[i].ID == msg.ID {
+			msgs[i] = msg
+		} else {
+			msgs = append(msgs, storage.Message{})
+			copy(msgs[i+1:], msgs[i:])
+			msgs[i] = msg
+		}
+
+		s.channels[tab] = msgs
+	}
+
+	return nil
+}
+
+func (s *MessageStore) Messages(network, channel string, count int, fromID string) ([]storage.Message, bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	msgs := s.channels[storage.Tab{Network: network, Name: channel}]
+
+	end := len(msgs)
+	if fromID != "" {
+		end = sort.Search(len(msgs), func(i int) bool {
+			return msgs[i].ID >= fromID
+		})
+	}
+
+	start := end - count
+	if start < 0 {
+		start = 0
+	}
+
+	if start == end {
+		return nil, false, nil
+	}
+
+	messages := make([]storage.Message, 0, end-start)
+	for _, msg := range msgs[start:end] {
+		messages = append(messages, copyMessage(&msg))
+	}
+
+	return messages, start > 0, nil
+}
+
+func (s *MessageStore) MessagesByID(network, channel string, ids []string) ([]storage.Message, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	msgs := s.channels[storage.Tab{Network: network, Name: channel}]
+
+	var messages []storage.Message
+	for _, id := range ids {
+		i := sort.Search(len(msgs), func(i int) bool {
+			return msgs[i].ID >= id
+		})
+
+		if i < len(msgs) && msgs[i].ID == id {
+			messages = append(messages, copyMessage(&msgs[i]))
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *MessageStore) CountUnread(network, channel string, marker *storage.ReadMarker, max int) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	msgs := s.channels[storage.Tab{Network: network, Name: channel}]
+
+	count := 0
+	for i := len(msgs) - 1; i >= 0 && count < max; i-- {
+		if marker != nil && marker.Covers(msgs[i].ID, msgs[i].Time) {
+			break
+		}
+		if msgs[i].Content != "" {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *MessageStore) ForEachMessage(fn func(*storage.Message) error) error {
+	s.lock.RLock()
+	tabs := make([]storage.Tab, 0, len(s.channels))
+	for tab := range s.channels {
+		tabs = append(tabs, tab)
+	}
+	s.lock.RUnlock()
+
+	sort.Slice(tabs, func(i, j int) bool {
+		return tabLess(tabs[i], tabs[j])
+	})
+
+	for _, tab := range tabs {
+		// The messages get copied so fn can log messages without deadlocking
+		s.lock.RLock()
+		msgs := make([]storage.Message, len(s.channels[tab]))
+		for i := range s.channels[tab] {
+			msgs[i] = copyMessage(&s.channels[tab][i])
+		}
+		s.lock.RUnlock()
+
+		for i := range msgs {
+			msgs[i].Network = tab.Network
+			msgs[i].To = tab.Name
+
+			err := fn(&msgs[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// PruneMessages deletes the oldest messages in each channel, limits returns
+// the time messages have to be logged before and the number of messages to
+// keep, 0 disables a limit. Message IDs sort by the time they were logged.
+func (s *MessageStore) PruneMessages(limits func(network, channel string) (before int64, keep int)) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var deleted []string
+
+	for tab, msgs := range s.channels {
+		before, keep := limits(tab.Network, tab.Name)
+		if before == 0 && keep == 0 {
+			continue
+		}
+
+		overLimit := 0
+		if keep > 0 && len(msgs) > keep {
+			overLimit = len(msgs) - keep
+		}
+
+		n := 0
+		for n < len(msgs) && (n < overLimit || (before != 0 && msgs[n].Time < before)) {
+			deleted = append(deleted, msgs[n].ID)
+			n++
+		}
+
+		if n == len(msgs) {
+			delete(s.channels, tab)
+		} else if n > 0 {
+			s.channels[tab] = append([]storage.Message{}, msgs[n:]...)
+		}
+	}
+
+	return deleted, nil
+}
+
+func (s *MessageStore) RenameNetwork(from, to string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for tab, msgs := range s.channels {
+		if tab.Network == from {
+			delete(s.channels, tab)
+			s.channels[storage.Tab{Network: to, Name: tab.Name}] = msgs
+		}
+	}
+
+	return nil
+}
+
+// MessageCount returns the number of messages logged in all channels
+func (s *MessageStore) MessageCount() (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	count := 0
+	for _, msgs := range s.channels {
+		count += len(msgs)
+	}
+	return count, nil
+}
+
+// copyMessage returns a copy of message that shares no memory with it
+func copyMessage(message *storage.Message) storage.Message {
+	msg := *message
+
+	if message.Events != nil {
+		msg.Events = make([]storage.Event, len(message.Events))
+		for i, event := range message.Events {
+			msg.Events[i] = event
+			msg.Events[i].Params = append([]string(nil), event.Params...)
+		}
+	}
+
+	return msg
+}
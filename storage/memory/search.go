@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Index implements storage.MessageSearchProvider, it matches the words of
+// a query as case insensitive substrings of the message content and the
+// text of events, all of them have to match
+type Index struct {
+	lock     sync.RWMutex
+	messages map[string]storage.Message
+}
+
+func NewIndex() *Index {
+	return &Index{
+		messages: map[string]storage.Message{},
+	}
+}
+
+// Close does nothing, the index is kept until the user gets deleted
+func (i *Index) Close() {}
+
+func (i *Index) Index(id string, message *storage.Message) error {
+	i.lock.Lock()
+	i.messages[id] = copyMessage(message)
+	i.lock.Unlock()
+	return nil
+}
+
+func (i *Index) DeleteMessages(ids []string) error {
+	i.lock.Lock()
+	for _, id := range ids {
+		delete(i.messages, id)
+	}
+	i.lock.Unlock()
+	return nil
+}
+
+func (i *Index) SearchMessages(network, channel, q string) ([]string, error) {
+	terms := parseTerms(q)
+
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	var ids []string
+	for id, msg := range i.messages {
+		if msg.Network == network && msg.To == channel && matchTerms(texts(&msg), terms) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Search returns the newest messages that match query first
+func (i *Index) Search(query storage.SearchQuery) (*storage.SearchResult, error) {
+	terms := parseTerms(query.Q)
+
+	i.lock.RLock()
+	var matches []storage.Message
+	for _, msg := range i.messages {
+		if match(&msg, &query, terms) {
+			matches = append(matches, msg)
+		}
+	}
+	i.lock.RUnlock()
+
+	sort.Slice(matches, func(a, b int) bool {
+		return matches[a].ID > matches[b].ID
+	})
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	} else if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	result := &storage.SearchResult{
+		Total: uint64(len(matches)),
+	}
+
+	if query.Offset < len(matches) && query.Offset >= 0 {
+		matches = matches[query.Offset:]
+		if len(matches) > limit {
+			matches = matches[:limit]
+		}
+
+		for _, msg := range matches {
+			result.Hits = append(result.Hits, storage.SearchHit{
+				ID:        msg.ID,
+				Network:   msg.Network,
+				Channel:   msg.To,
+				Fragments: fragments(texts(&msg), terms),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func match(msg *storage.Message, query *storage.SearchQuery, terms []*regexp.Regexp) bool {
+	if query.Network != "" && msg.Network != query.Network {
+		return false
+	}
+	if query.Channel != "" && msg.To != query.Channel {
+		return false
+	}
+	if query.From != "" && !strings.EqualFold(msg.From, query.From) {
+		return false
+	}
+	if query.Start != 0 && msg.Time < query.Start {
+		return false
+	}
+	if query.End != 0 && msg.Time > query.End {
+		return false
+	}
+
+	if query.Event != "" || query.Nick != "" {
+		eventMatch := query.Event == ""
+		nickMatch := query.Nick == "" || strings.EqualFold(msg.From, query.Nick)
+
+		for _, event := range msg.Events {
+			if event.Type == query.Event {
+				eventMatch = true
+			}
+			for _, nick := range event.Nicks() {
+				if strings.EqualFold(nick, query.Nick) {
+					nickMatch = true
+				}
+			}
+		}
+
+		if !eventMatch || !nickMatch {
+			return false
+		}
+	}
+
+	return matchTerms(texts(msg), terms)
+}
+
+func parseTerms(q string) []*regexp.Regexp {
+	var terms []*regexp.Regexp
+	for _, word := range strings.Fields(q) {
+		terms = append(terms, regexp.MustCompile("(?i)"+regexp.QuoteMeta(word)))
+	}
+	return terms
+}
+
+// texts returns the parts of a message that get searched
+func texts(msg *storage.Message) []string {
+	var texts []string
+	if msg.Content != "" {
+		texts = append(texts, msg.Content)
+	}
+	for _, event := range msg.Events {
+		if text := event.Text(); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func matchTerms(texts []string, terms []*regexp.Regexp) bool {
+	for _, term := range terms {
+		found := false
+		for _, text := range texts {
+			if term.MatchString(text) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fragments returns the texts that match any of the terms, HTML escaped
+// with the matches wrapped in <mark>
+func fragments(texts []string, terms []*regexp.Regexp) []string {
+	var fragments []string
+
+	for _, text := range texts {
+		var matches [][]int
+		for _, term := range terms {
+			matches = append(matches, term.FindAllStringIndex(text, -1)...)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		sort.Slice(matches, func(i, j int) bool {
+			return matches[i][0] < matches[j][0]
+		})
+
+		sb := strings.Builder{}
+		pos := 0
+		for _, m := range matches {
+			if m[0] < pos {
+				continue
+			}
+
+			sb.WriteString(html.EscapeString(text[pos:m[0]]))
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(text[m[0]:m[1]]))
+			sb.WriteString("</mark>")
+			pos = m[1]
+		}
+		sb.WriteString(html.EscapeString(text[pos:]))
+
+		fragments = append(fragments, sb.String())
+	}
+
+	return fragments
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+func TestSearch(t *testing.T) {
+	index := NewIndex()
+
+	messages := []*storage.Message{
+		{ID: "1", Network: "freenode", From: "alice", To: "#go-nuts", Content: "Gophers <3 channels", Time: 100},
+		{ID: "2", Network: "freenode", From: "bob", To: "#go-nuts", Content: "channels are great", Time: 200},
+		{ID: "3", Network: "oftc", From: "alice", To: "#rust", Content: "no channels here", Time: 300},
+		{ID: "4", Network: "freenode", To: "#go-nuts", Time: 400, Events: []storage.Event{
+			{Type: "kick", Params: []string{"bob", "op", "spam"}},
+		}},
+	}
+	for _, msg := range messages {
+		assert.Nil(t, index.Index(msg.ID, msg))
+	}
+
+	ids, err := index.SearchMessages("freenode", "#go-nuts", "CHANNELS")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids)
+
+	res, err := index.Search(storage.SearchQuery{Q: "channels"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), res.Total)
+	assert.Equal(t, "3", res.Hits[0].ID)
+	assert.Equal(t, "oftc", res.Hits[0].Network)
+	assert.Equal(t, "#rust", res.Hits[0].Channel)
+
+	res, err = index.Search(storage.SearchQuery{Q: "gophers channels"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, []string{"<mark>Gophers</mark> &lt;3 <mark>channels</mark>"}, res.Hits[0].Fragments)
+
+	res, err = index.Search(storage.SearchQuery{Q: "channels", From: "Alice", Network: "freenode"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "1", res.Hits[0].ID)
+
+	res, err = index.Search(storage.SearchQuery{Start: 200, End: 300})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+
+	res, err = index.Search(storage.SearchQuery{Nick: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+
+	res, err = index.Search(storage.SearchQuery{Q: "spam", Event: "kick"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, []string{"<mark>spam</mark>"}, res.Hits[0].Fragments)
+
+	res, err = index.Search(storage.SearchQuery{Q: "channels", Offset: 1, Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), res.Total)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "2", res.Hits[0].ID)
+
+	assert.Nil(t, index.DeleteMessages([]string{"1", "2"}))
+	res, err = index.Search(storage.SearchQuery{Q: "channels"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), res.Total)
+}
@@ -6,9 +6,13 @@ import (
 
 	"github.com/khlieng/dispatch/pkg/irc"
 	"github.com/khlieng/dispatch/version"
+	"github.com/kjk/betterguid"
 )
 
 type Network struct {
+	// ID identifies the network for its user, it stays the same when the
+	// network gets moved to a different host
+	ID             string
 	Name           string
 	Host           string
 	Port           string
@@ -19,6 +23,8 @@ type Network struct {
 	Realname       string
 	Account        string
 	Password       string
+	// Servers get tried in order after Host when connecting fails
+	Servers []Server
 
 	Features  map[string]interface{}
 	Connected bool
@@ -30,6 +36,12 @@ type Network struct {
 	lock     *sync.Mutex
 }
 
+// NewNetworkID returns an ID for a new network, it is random so it says
+// nothing about the host the network connects to
+func NewNetworkID() string {
+	return betterguid.New()
+}
+
 func (n *Network) Save() error {
 	return n.user.SaveNetwork(n.Copy())
 }
@@ -37,6 +49,7 @@ func (n *Network) Save() error {
 func (n *Network) Copy() *Network {
 	n.lock.Lock()
 	network := Network{
+		ID:             n.ID,
 		Name:           n.Name,
 		Host:           n.Host,
 		Port:           n.Port,
@@ -47,6 +60,7 @@ func (n *Network) Copy() *Network {
 		Realname:       n.Realname,
 		Account:        n.Account,
 		Password:       n.Password,
+		Servers:        n.Servers,
 		Features:       n.Features,
 		Connected:      n.Connected,
 		Error:          n.Error,
@@ -60,15 +74,31 @@ func (n *Network) Copy() *Network {
 	return &network
 }
 
+type Server struct {
+	Host string
+	Port string
+	TLS  bool
+}
+
 func (n *Network) Client() *irc.Client {
 	return n.client
 }
 
 func (n *Network) IRCConfig() *irc.Config {
+	servers := make([]irc.Server, len(n.Servers))
+	for i, server := range n.Servers {
+		servers[i] = irc.Server{
+			Host: server.Host,
+			Port: server.Port,
+			TLS:  server.TLS,
+		}
+	}
+
 	return &irc.Config{
 		Host:     n.Host,
 		Port:     n.Port,
 		TLS:      n.TLS,
+		Servers:  servers,
 		Nick:     n.Nick,
 		Username: n.Username,
 		Realname: n.Realname,
@@ -135,7 +165,7 @@ func (n *Network) ChannelNames() []string {
 
 func (n *Network) NewChannel(name string) *Channel {
 	return &Channel{
-		Network: n.Host,
+		Network: n.ID,
 		Name:    name,
 		user:    n.user,
 		lock:    &sync.Mutex{},
@@ -217,3 +247,191 @@ func (c *Channel) SetJoined(joined bool) {
 	c.Joined = joined
 	c.lock.Unlock()
 }
+
+// batchIndexer is implemented by search providers that can index several
+// messages at once
+type batchIndexer interface {
+	IndexBatch(messages []*Message) error
+}
+
+// migrateNetworkIDs moves the networks that have a rename stored to their
+// new ID, the store plans them when it gets upgraded from a version that
+// used the host of networks as ID. It has to run before the networks get
+// loaded.
+func (u *User) migrateNetworkIDs() error {
+	renames, err := u.store.NetworkRenames(u)
+	if err != nil {
+		return err
+	}
+
+	for from, to := range renames {
+		network, err := u.store.Network(u, from)
+		if err == ErrNotFound {
+			// Nothing left to move, this removes the rename
+			err = u.store.RemoveNetwork(u, from)
+		} else if err == nil {
+			err = u.renameNetwork(network, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renameNetwork moves everything stored for network over to the ID id. The
+// network gets stored under the new ID last and the old one, along with the
+// stored rename, removed after that. Everything gets moved again to the same
+// ID on the next start if something fails before then.
+func (u *User) renameNetwork(network *Network, id string) error {
+	from := network.ID
+
+	err := u.messageLog.RenameNetwork(from, id)
+	if err != nil {
+		return err
+	}
+
+	if u.messageIndex != nil {
+		err = u.reindexNetwork(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	channels, err := u.store.Channels(u)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if channel.Network == from {
+			channel.Network = id
+			err = u.store.SaveChannel(u, channel)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	openDMs, err := u.store.OpenDMs(u)
+	if err != nil {
+		return err
+	}
+	for _, tab := range openDMs {
+		if tab.Network == from {
+			err = u.store.AddOpenDM(u, id, tab.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	mentions, err := u.store.Mentions(u)
+	if err != nil {
+		return err
+	}
+	for _, mention := range mentions {
+		if mention.Network == from {
+			mention.Network = id
+			err = u.store.SaveMention(u, mention)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	markers, err := u.store.ReadMarkers(u)
+	if err != nil {
+		return err
+	}
+	for _, marker := range markers {
+		if marker.Network == from {
+			marker.Network = id
+			err = u.store.SaveReadMarker(u, marker)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	rules, err := u.store.IgnoreRules(u)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if rule.Network == from {
+			rule.Network = id
+			err = u.store.SaveIgnoreRule(u, rule)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	highlights, err := u.store.HighlightRules(u)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if highlights != nil {
+		muted := false
+		for i, tab := range highlights.Muted {
+			if tab.Network == from {
+				highlights.Muted[i].Network = id
+				muted = true
+			}
+		}
+		if muted {
+			err = u.store.SaveHighlightRules(u, highlights)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	network.ID = id
+	err = u.store.SaveNetwork(u, network)
+	if err != nil {
+		return err
+	}
+
+	return u.store.RemoveNetwork(u, from)
+}
+
+// reindexNetwork indexes the messages logged on the network again, the
+// network is part of what gets indexed
+func (u *User) reindexNetwork(network string) error {
+	var batch []*Message
+
+	flush := func() error {
+		var err error
+		if indexer, ok := u.messageIndex.(batchIndexer); ok {
+			err = indexer.IndexBatch(batch)
+		} else {
+			for _, message := range batch {
+				err = u.messageIndex.Index(message.ID, message)
+				if err != nil {
+					break
+				}
+			}
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	err := u.messageLog.ForEachMessage(func(message *Message) error {
+		if message.Network != network {
+			return nil
+		}
+
+		batch = append(batch, message)
+		if len(batch) == 1000 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
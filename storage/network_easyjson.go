@@ -1,4 +1,4 @@
-//v1: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+//v2: false// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
 
 package storage
 
@@ -17,7 +17,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out *Network) {
+func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out *Server) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,6 +36,99 @@ func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 			continue
 		}
 		switch key {
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = string(in.String())
+		case "tls":
+			out.TLS = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC5839400EncodeGithubComKhliengDispatchStorage(out *jwriter.Writer, in Server) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Host != "" {
+		const prefix string = ",\"host\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Host))
+	}
+	if in.Port != "" {
+		const prefix string = ",\"port\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Port))
+	}
+	if in.TLS {
+		const prefix string = ",\"tls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.TLS))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Server) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Server) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Server) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Server) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage(l, v)
+}
+func easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, out *Network) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
 		case "name":
 			out.Name = string(in.String())
 		case "host":
@@ -56,6 +149,29 @@ func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 			out.Account = string(in.String())
 		case "password":
 			out.Password = string(in.String())
+		case "servers":
+			if in.IsNull() {
+				in.Skip()
+				out.Servers = nil
+			} else {
+				in.Delim('[')
+				if out.Servers == nil {
+					if !in.IsDelim(']') {
+						out.Servers = make([]Server, 0, 1)
+					} else {
+						out.Servers = []Server{}
+					}
+				} else {
+					out.Servers = (out.Servers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Server
+					(v1).UnmarshalEasyJSON(in)
+					out.Servers = append(out.Servers, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "features":
 			if in.IsNull() {
 				in.Skip()
@@ -69,15 +185,15 @@ func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 				for !in.IsDelim('}') {
 					key := string(in.String())
 					in.WantColon()
-					var v1 interface{}
-					if m, ok := v1.(easyjson.Unmarshaler); ok {
+					var v2 interface{}
+					if m, ok := v2.(easyjson.Unmarshaler); ok {
 						m.UnmarshalEasyJSON(in)
-					} else if m, ok := v1.(json.Unmarshaler); ok {
+					} else if m, ok := v2.(json.Unmarshaler); ok {
 						_ = m.UnmarshalJSON(in.Raw())
 					} else {
-						v1 = in.Interface()
+						v2 = in.Interface()
 					}
-					(out.Features)[key] = v1
+					(out.Features)[key] = v2
 					in.WantComma()
 				}
 				in.Delim('}')
@@ -96,14 +212,24 @@ func easyjsonC5839400DecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjsonC5839400EncodeGithubComKhliengDispatchStorage(out *jwriter.Writer, in Network) {
+func easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer, in Network) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
 		first = false
 		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.Name))
 	}
 	if in.Host != "" {
@@ -196,6 +322,25 @@ func easyjsonC5839400EncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 		}
 		out.String(string(in.Password))
 	}
+	if len(in.Servers) != 0 {
+		const prefix string = ",\"servers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v3, v4 := range in.Servers {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				(v4).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	if len(in.Features) != 0 {
 		const prefix string = ",\"features\":"
 		if first {
@@ -206,21 +351,21 @@ func easyjsonC5839400EncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 		}
 		{
 			out.RawByte('{')
-			v2First := true
-			for v2Name, v2Value := range in.Features {
-				if v2First {
-					v2First = false
+			v5First := true
+			for v5Name, v5Value := range in.Features {
+				if v5First {
+					v5First = false
 				} else {
 					out.RawByte(',')
 				}
-				out.String(string(v2Name))
+				out.String(string(v5Name))
 				out.RawByte(':')
-				if m, ok := v2Value.(easyjson.Marshaler); ok {
+				if m, ok := v5Value.(easyjson.Marshaler); ok {
 					m.MarshalEasyJSON(out)
-				} else if m, ok := v2Value.(json.Marshaler); ok {
+				} else if m, ok := v5Value.(json.Marshaler); ok {
 					out.Raw(m.MarshalJSON())
 				} else {
-					out.Raw(json.Marshal(v2Value))
+					out.Raw(json.Marshal(v5Value))
 				}
 			}
 			out.RawByte('}')
@@ -252,27 +397,27 @@ func easyjsonC5839400EncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v Network) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5839400EncodeGithubComKhliengDispatchStorage(&w, v)
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Network) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5839400EncodeGithubComKhliengDispatchStorage(w, v)
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Network) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5839400DecodeGithubComKhliengDispatchStorage(&r, v)
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Network) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5839400DecodeGithubComKhliengDispatchStorage(l, v)
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(l, v)
 }
-func easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, out *Channel) {
+func easyjsonC5839400DecodeGithubComKhliengDispatchStorage2(in *jlexer.Lexer, out *Channel) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -309,7 +454,7 @@ func easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer, in Channel) {
+func easyjsonC5839400EncodeGithubComKhliengDispatchStorage2(out *jwriter.Writer, in Channel) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -355,23 +500,23 @@ func easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(out *jwriter.Writer,
 // MarshalJSON supports json.Marshaler interface
 func (v Channel) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(&w, v)
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Channel) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC5839400EncodeGithubComKhliengDispatchStorage1(w, v)
+	easyjsonC5839400EncodeGithubComKhliengDispatchStorage2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Channel) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(&r, v)
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Channel) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC5839400DecodeGithubComKhliengDispatchStorage1(l, v)
+	easyjsonC5839400DecodeGithubComKhliengDispatchStorage2(l, v)
 }
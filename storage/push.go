@@ -0,0 +1,20 @@
+package storage
+
+// PushSubscription is a browser subscribed to Web Push notifications
+type PushSubscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+func (u *User) PushSubscriptions() ([]*PushSubscription, error) {
+	return u.store.PushSubscriptions(u)
+}
+
+func (u *User) AddPushSubscription(sub *PushSubscription) error {
+	return u.store.SavePushSubscription(u, sub)
+}
+
+func (u *User) RemovePushSubscription(endpoint string) error {
+	return u.store.RemovePushSubscription(u, endpoint)
+}
@@ -0,0 +1,140 @@
+package storage
+
+import "strings"
+
+// MaxUnread is how many unread messages get counted per channel at most
+var MaxUnread = 500
+
+// ReadMarker marks where the user stopped reading in a channel or private
+// chat. Markers set in dispatch point at a message, markers that come from
+// the network only have a time.
+type ReadMarker struct {
+	Network string
+	Channel string
+	ID      string
+	Time    int64
+}
+
+// Covers returns true if the message or mention with id, logged at the
+// unix time t, has been read
+func (m *ReadMarker) Covers(id string, t int64) bool {
+	if m.ID != "" && id != "" {
+		return id <= m.ID
+	}
+	return t <= m.Time
+}
+
+// After returns true if m is further ahead than other
+func (m *ReadMarker) After(other *ReadMarker) bool {
+	if m.ID != "" && other.ID != "" {
+		return m.ID > other.ID
+	}
+	return m.Time > other.Time
+}
+
+// UnreadCount is how many messages and highlights there are after the read
+// marker of a channel or private chat
+type UnreadCount struct {
+	Network    string
+	Channel    string
+	Messages   int
+	Highlights int
+}
+
+func (u *User) ReadMarkers() ([]*ReadMarker, error) {
+	return u.store.ReadMarkers(u)
+}
+
+// SetReadMarker moves the read marker of a channel forward and marks the
+// mentions it covers as read, it returns false if the marker was already
+// further ahead
+func (u *User) SetReadMarker(marker *ReadMarker) (bool, error) {
+	u.readMarkerLock.Lock()
+	defer u.readMarkerLock.Unlock()
+
+	current, err := u.store.ReadMarker(u, marker.Network, marker.Channel)
+	if err == nil && !marker.After(current) {
+		return false, nil
+	} else if err != nil && err != ErrNotFound {
+		return false, err
+	}
+
+	err = u.store.SaveReadMarker(u, marker)
+	if err != nil {
+		return false, err
+	}
+
+	mentions, err := u.store.Mentions(u)
+	if err != nil {
+		return true, err
+	}
+
+	for _, m := range mentions {
+		if !m.Read && m.Network == marker.Network && strings.EqualFold(m.To, marker.Channel) &&
+			marker.Covers(m.ID, m.Time) {
+			m.Read = true
+
+			err = u.store.SaveMention(u, m)
+			if err != nil {
+				return true, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// UnreadCounts returns the unread counts of tabs, everything is unread in
+// tabs that have no read marker yet
+func (u *User) UnreadCounts(tabs []Tab) ([]UnreadCount, error) {
+	markers, err := u.store.ReadMarkers(u)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := u.store.Mentions(u)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadMentions := map[Tab][]*Mention{}
+	for _, m := range mentions {
+		if !m.Read {
+			tab := Tab{Network: m.Network, Name: strings.ToLower(m.To)}
+			unreadMentions[tab] = append(unreadMentions[tab], m)
+		}
+	}
+
+	counts := make([]UnreadCount, 0, len(tabs))
+	for _, tab := range tabs {
+		marker := findReadMarker(markers, tab)
+		count := UnreadCount{
+			Network: tab.Network,
+			Channel: tab.Name,
+		}
+
+		count.Messages, err = u.messageLog.CountUnread(tab.Network, tab.Name, marker, MaxUnread)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range unreadMentions[Tab{Network: tab.Network, Name: strings.ToLower(tab.Name)}] {
+			if marker == nil || !marker.Covers(m.ID, m.Time) {
+				count.Highlights++
+			}
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func findReadMarker(markers []*ReadMarker, tab Tab) *ReadMarker {
+	for _, m := range markers {
+		if m.Network == tab.Network && strings.EqualFold(m.Channel, tab.Name) {
+			return m
+		}
+	}
+	return nil
+}
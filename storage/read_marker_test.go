@@ -0,0 +1,156 @@
+package storage_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/bleve"
+)
+
+func TestReadMarker(t *testing.T) {
+	marker := &storage.ReadMarker{ID: "b", Time: 10}
+	assert.True(t, marker.Covers("a", 20))
+	assert.True(t, marker.Covers("b", 20))
+	assert.False(t, marker.Covers("c", 0))
+	// Markers from the network only have a time
+	assert.True(t, (&storage.ReadMarker{Time: 10}).Covers("c", 10))
+	assert.False(t, (&storage.ReadMarker{Time: 10}).Covers("a", 11))
+
+	assert.True(t, (&storage.ReadMarker{ID: "c"}).After(marker))
+	assert.False(t, (&storage.ReadMarker{ID: "a", Time: 20}).After(marker))
+	assert.True(t, (&storage.ReadMarker{Time: 11}).After(marker))
+	assert.False(t, (&storage.ReadMarker{Time: 10}).After(marker))
+}
+
+func TestUserReadMarkers(t *testing.T) {
+	forEachBackend(t, testUserReadMarkers)
+}
+
+func testUserReadMarkers(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		msg := &storage.Message{
+			ID:      betterguid.New(),
+			Network: "freenode",
+			From:    "bob",
+			To:      "#go",
+			Content: "hi",
+		}
+		require.Nil(t, user.LogMessage(msg))
+		ids = append(ids, msg.ID)
+
+		if i%2 == 1 {
+			require.Nil(t, user.AddMention(msg))
+		}
+	}
+	require.Nil(t, user.LogEvent("freenode", "join", []string{"alice"}, "#go"))
+
+	tabs := []storage.Tab{{Network: "freenode", Name: "#go"}, {Network: "freenode", Name: "#empty"}}
+
+	counts, err := user.UnreadCounts(tabs)
+	require.Nil(t, err)
+	assert.Equal(t, []storage.UnreadCount{
+		{Network: "freenode", Channel: "#go", Messages: 4, Highlights: 2},
+		{Network: "freenode", Channel: "#empty"},
+	}, counts)
+
+	maxUnread := storage.MaxUnread
+	storage.MaxUnread = 3
+	counts, err = user.UnreadCounts(tabs)
+	storage.MaxUnread = maxUnread
+	require.Nil(t, err)
+	assert.Equal(t, 3, counts[0].Messages)
+
+	moved, err := user.SetReadMarker(&storage.ReadMarker{Network: "freenode", Channel: "#go", ID: ids[1]})
+	require.Nil(t, err)
+	assert.True(t, moved)
+
+	counts, err = user.UnreadCounts(tabs)
+	require.Nil(t, err)
+	assert.Equal(t, 2, counts[0].Messages)
+	assert.Equal(t, 1, counts[0].Highlights)
+
+	mentions, err := user.Mentions(true)
+	require.Nil(t, err)
+	require.Len(t, mentions, 1)
+	assert.Equal(t, ids[3], mentions[0].ID)
+
+	// Markers never move back
+	moved, err = user.SetReadMarker(&storage.ReadMarker{Network: "freenode", Channel: "#go", ID: ids[0]})
+	require.Nil(t, err)
+	assert.False(t, moved)
+
+	moved, err = user.SetReadMarker(&storage.ReadMarker{Network: "freenode", Channel: "#go", ID: ids[3]})
+	require.Nil(t, err)
+	assert.True(t, moved)
+
+	counts, err = user.UnreadCounts(tabs)
+	require.Nil(t, err)
+	assert.Equal(t, 0, counts[0].Messages)
+	assert.Equal(t, 0, counts[0].Highlights)
+
+	markers, err := user.ReadMarkers()
+	require.Nil(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, ids[3], markers[0].ID)
+
+	// Markers from the network only have a time
+	for _, time := range []int64{100, 200} {
+		require.Nil(t, user.LogMessage(&storage.Message{Network: "freenode", From: "bob", To: "#time", Content: "hi", Time: time}))
+	}
+	_, err = user.SetReadMarker(&storage.ReadMarker{Network: "freenode", Channel: "#time", Time: 150})
+	require.Nil(t, err)
+
+	counts, err = user.UnreadCounts([]storage.Tab{{Network: "freenode", Name: "#time"}})
+	require.Nil(t, err)
+	assert.Equal(t, 1, counts[0].Messages)
+}
+
+func TestUserReadMarkersConcurrent(t *testing.T) {
+	forEachBackend(t, testUserReadMarkersConcurrent)
+}
+
+func testUserReadMarkersConcurrent(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return nil, nil
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = betterguid.New()
+	}
+
+	// The marker has to end up at the last message no matter what order
+	// the markers get set in
+	var wg sync.WaitGroup
+	for _, i := range rand.Perm(len(ids)) {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			_, err := user.SetReadMarker(&storage.ReadMarker{Network: "freenode", Channel: "#go", ID: id})
+			assert.Nil(t, err)
+		}(ids[i])
+	}
+	wg.Wait()
+
+	markers, err := user.ReadMarkers()
+	require.Nil(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, ids[len(ids)-1], markers[0].ID)
+}
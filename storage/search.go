@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"html"
+	"sort"
+	"strings"
+)
+
+// SearchQuery describes a message search, the filters that are left empty
+// match everything
+type SearchQuery struct {
+	Network string
+	Channel string
+	// From matches the sender of messages
+	From string
+	// Nick matches the sender of messages and the users involved in events
+	Nick  string
+	Event string
+	// Start and End limit the search to messages logged between them,
+	// they are unix timestamps
+	Start int64
+	End   int64
+	// Q uses the bleve query string syntax, terms without a field match the
+	// message content. The fields are server, to, from, content, time,
+	// events.type, events.nicks and events.text
+	Q      string
+	Offset int
+	Limit  int
+}
+
+type SearchResult struct {
+	Total uint64
+	Hits  []SearchHit
+}
+
+type SearchHit struct {
+	ID      string
+	Network string
+	Channel string
+	// Fragments are the parts of the message that matched, HTML escaped
+	// with the matching terms wrapped in <mark>
+	Fragments []string
+	// Matches are where the terms matched in the message content, they
+	// are used for the fragments when the index does not have the content
+	Matches []SearchMatch
+	Message Message
+}
+
+// SearchMatch is the byte range of a match in the content of a message
+type SearchMatch struct {
+	Start int
+	End   int
+}
+
+// Search searches all messages of the user, the hits that are returned
+// include the messages they point to
+func (u *User) Search(query SearchQuery) (*SearchResult, error) {
+	result, err := u.messageIndex.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	type tab struct {
+		network string
+		channel string
+	}
+	ids := map[tab][]string{}
+	for _, hit := range result.Hits {
+		t := tab{hit.Network, hit.Channel}
+		ids[t] = append(ids[t], hit.ID)
+	}
+
+	messages := map[string]Message{}
+	for t, ids := range ids {
+		msgs, err := u.messageLog.MessagesByID(t.network, t.channel, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range msgs {
+			msg.Network = t.network
+			msg.To = t.channel
+			messages[msg.ID] = msg
+		}
+	}
+
+	// Hits for messages that no longer exist in the log get left out, and
+	// out of the total
+	hits := result.Hits[:0]
+	for _, hit := range result.Hits {
+		if msg, ok := messages[hit.ID]; ok {
+			hit.Message = msg
+			if len(hit.Fragments) == 0 && len(hit.Matches) > 0 {
+				hit.Fragments = []string{markMatches(msg.Content, hit.Matches)}
+			}
+			hits = append(hits, hit)
+		}
+	}
+	if dropped := uint64(len(result.Hits) - len(hits)); dropped < result.Total {
+		result.Total -= dropped
+	} else {
+		result.Total = uint64(len(hits))
+	}
+	result.Hits = hits
+
+	return result, nil
+}
+
+// markMatches HTML escapes content and wraps the matches in <mark> the same
+// way the fragments from the index are
+func markMatches(content string, matches []SearchMatch) string {
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+
+	var sb strings.Builder
+	curr := 0
+
+	for _, match := range matches {
+		if match.Start < curr || match.End > len(content) || match.Start >= match.End {
+			continue
+		}
+
+		sb.WriteString(html.EscapeString(content[curr:match.Start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(content[match.Start:match.End]))
+		sb.WriteString("</mark>")
+		curr = match.End
+	}
+	sb.WriteString(html.EscapeString(content[curr:]))
+
+	return sb.String()
+}
@@ -0,0 +1,353 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// MessageStore implements storage.MessageStore for a single user
+type MessageStore struct {
+	db     *sql.DB
+	userID uint64
+}
+
+// MessageStore returns the message log of a user, it shares the database
+// connection with s
+func (s *SQLite) MessageStore(user *storage.User) *MessageStore {
+	return &MessageStore{
+		db:     s.db,
+		userID: user.ID,
+	}
+}
+
+// Close does nothing, the database gets closed with the SQLite it came from
+func (s *MessageStore) Close() {}
+
+func logMessage(stmt *sql.Stmt, userID uint64, message *storage.Message) error {
+	data, err := message.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(userID, message.Network, message.To, message.ID, message.Time, data)
+	return err
+}
+
+const insertMessage = `INSERT OR REPLACE INTO messages (user_id, network, channel, id, time, data)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+func (s *MessageStore) LogMessage(message *storage.Message) error {
+	return s.LogMessages([]*storage.Message{message})
+}
+
+func (s *MessageStore) LogMessages(messages []*storage.Message) error {
+	return transaction(s.db, func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(insertMessage)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, message := range messages {
+			err = logMessage(stmt, s.userID, message)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *MessageStore) Messages(network, channel string, count int, fromID string) ([]storage.Message, bool, error) {
+	query := `SELECT data FROM messages WHERE user_id = ? AND network = ? AND channel = ?`
+	args := []interface{}{s.userID, network, channel}
+	if fromID != "" {
+		query += ` AND id < ?`
+		args = append(args, fromID)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, count+1)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var messages []storage.Message
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, false, err
+		}
+
+		message := storage.Message{}
+		_, err = message.Unmarshal(data)
+		if err != nil {
+			return nil, false, err
+		}
+		messages = append(messages, message)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(messages) == 0 {
+		return nil, false, nil
+	}
+
+	hasMore := len(messages) > count
+	if hasMore {
+		messages = messages[:count]
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, hasMore, nil
+}
+
+func (s *MessageStore) MessagesByID(network, channel string, ids []string) ([]storage.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{s.userID, network, channel}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(`SELECT id, data FROM messages WHERE user_id = ? AND network = ? AND channel = ?
+		AND id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := map[string]storage.Message{}
+	for rows.Next() {
+		var id string
+		var data []byte
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		message := storage.Message{}
+		_, err = message.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		byID[id] = message
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The messages are returned in the order of ids
+	var messages []storage.Message
+	for _, id := range ids {
+		if message, ok := byID[id]; ok {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+// MessageCount returns the number of messages logged in all channels
+func (s *MessageStore) MessageCount() (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE user_id = ?`, s.userID).Scan(&count)
+	return count, err
+}
+
+// CountUnread only reads the messages after the read marker, they have to
+// be decoded to leave out the ones that only hold events
+func (s *MessageStore) CountUnread(network, channel string, marker *storage.ReadMarker, max int) (int, error) {
+	query := `SELECT data FROM messages WHERE user_id = ? AND network = ? AND channel = ?`
+	args := []interface{}{s.userID, network, channel}
+	if marker != nil && marker.ID != "" {
+		query += ` AND id > ?`
+		args = append(args, marker.ID)
+	} else if marker != nil {
+		query += ` AND time > ?`
+		args = append(args, marker.Time)
+	}
+	query += ` ORDER BY id DESC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for count < max && rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return 0, err
+		}
+
+		message := storage.Message{}
+		_, err = message.Unmarshal(data)
+		if err != nil {
+			return 0, err
+		}
+
+		if message.Content != "" {
+			count++
+		}
+	}
+
+	return count, rows.Err()
+}
+
+func (s *MessageStore) ForEachMessage(fn func(*storage.Message) error) error {
+	rows, err := s.db.Query(`SELECT network, channel, data FROM messages WHERE user_id = ?
+		ORDER BY network, channel, id`, s.userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		message := storage.Message{}
+		var data []byte
+		err = rows.Scan(&message.Network, &message.To, &data)
+		if err != nil {
+			return err
+		}
+
+		_, err = message.Unmarshal(data)
+		if err != nil {
+			return err
+		}
+
+		err = fn(&message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// PruneMessages deletes the oldest messages in each channel, limits returns
+// the time messages have to be logged before and the number of messages to
+// keep, 0 disables a limit. Message IDs sort by the time they were logged.
+func (s *MessageStore) PruneMessages(limits func(network, channel string) (before int64, keep int)) ([]string, error) {
+	type tab struct {
+		network string
+		channel string
+		count   int
+	}
+
+	rows, err := s.db.Query(`SELECT network, channel, COUNT(*) FROM messages WHERE user_id = ?
+		GROUP BY network, channel`, s.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tabs []tab
+	for rows.Next() {
+		t := tab{}
+		err = rows.Scan(&t.network, &t.channel, &t.count)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tabs = append(tabs, t)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+
+	err = transaction(s.db, func(tx *sql.Tx) error {
+		for _, t := range tabs {
+			before, keep := limits(t.network, t.channel)
+			if before == 0 && keep == 0 {
+				continue
+			}
+
+			overLimit := 0
+			if keep > 0 && t.count > keep {
+				overLimit = t.count - keep
+			}
+
+			// The messages over the limit get deleted regardless of when
+			// they were logged, the rest only when they are too old
+			query := `SELECT id FROM messages WHERE user_id = ? AND network = ? AND channel = ?
+				ORDER BY id LIMIT ?`
+			args := []interface{}{s.userID, t.network, t.channel, overLimit}
+			if before != 0 {
+				query = `SELECT id FROM messages WHERE user_id = ? AND network = ? AND channel = ?
+					AND (id IN (SELECT id FROM messages WHERE user_id = ? AND network = ? AND channel = ?
+					ORDER BY id LIMIT ?) OR time < ?)`
+				args = []interface{}{s.userID, t.network, t.channel,
+					s.userID, t.network, t.channel, overLimit, before}
+			}
+
+			ids, err := queryIDs(tx, query, args...)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				_, err = tx.Exec(`DELETE FROM messages WHERE user_id = ? AND network = ? AND channel = ? AND id = ?`,
+					s.userID, t.network, t.channel, id)
+				if err != nil {
+					return err
+				}
+			}
+			deleted = append(deleted, ids...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+func (s *MessageStore) RenameNetwork(from, to string) error {
+	_, err := s.db.Exec(`UPDATE messages SET network = ? WHERE user_id = ? AND network = ?`,
+		to, s.userID, from)
+	return err
+}
+
+// Compact returns the pages freed up by deleted messages to the file system
+func (s *MessageStore) Compact() error {
+	_, err := s.db.Exec(`PRAGMA incremental_vacuum`)
+	return err
+}
+
+func queryIDs(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/khlieng/dispatch/storage"
+)
+
+// migrations upgrade the data in the database, they get run in order
+// starting from the user_version of the database
+var migrations = []func(tx *sql.Tx) error{
+	addLinkPreviewsSetting,
+}
+
+func migrate(db *sql.DB) error {
+	return transaction(db, func(tx *sql.Tx) error {
+		var version int
+		err := tx.QueryRow(`PRAGMA user_version`).Scan(&version)
+		if err != nil {
+			return err
+		}
+		if version >= len(migrations) {
+			return nil
+		}
+
+		for ; version < len(migrations); version++ {
+			err = migrations[version](tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		// PRAGMA does not take parameters
+		_, err = tx.Exec(`PRAGMA user_version = ` + strconv.Itoa(version))
+		return err
+	})
+}
+
+// addLinkPreviewsSetting upgrades users stored before
+// ClientSettings.LinkPreviews got added
+func addLinkPreviewsSetting(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, data FROM users`)
+	if err != nil {
+		return err
+	}
+
+	users := map[uint64][]byte{}
+	for rows.Next() {
+		var id uint64
+		var data []byte
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		users[id], err = storage.AddLinkPreviewsSetting(data)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for id, data := range users {
+		_, err = tx.Exec(`UPDATE users SET data = ? WHERE id = ?`, data, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
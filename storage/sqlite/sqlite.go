@@ -0,0 +1,747 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	// Registers the pure Go sqlite driver
+	_ "modernc.org/sqlite"
+
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// busyTimeout is how many milliseconds to wait for the lock on a database
+// that another connection is writing to
+const busyTimeout = 5000
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		data BLOB NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS networks (
+		user_id INTEGER NOT NULL,
+		id TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, id)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS channels (
+		user_id INTEGER NOT NULL,
+		network TEXT NOT NULL,
+		name TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, network, name)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS open_dms (
+		user_id INTEGER NOT NULL,
+		network TEXT NOT NULL,
+		nick TEXT NOT NULL,
+		PRIMARY KEY (user_id, network, nick)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS sessions (
+		key TEXT PRIMARY KEY,
+		data BLOB NOT NULL
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS messages (
+		user_id INTEGER NOT NULL,
+		network TEXT NOT NULL,
+		channel TEXT NOT NULL,
+		id TEXT NOT NULL,
+		time INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, network, channel, id)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS highlight_rules (
+		user_id INTEGER PRIMARY KEY,
+		data BLOB NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS mentions (
+		user_id INTEGER NOT NULL,
+		id TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, id)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS push_subscriptions (
+		user_id INTEGER NOT NULL,
+		endpoint TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, endpoint)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS ignore_rules (
+		user_id INTEGER NOT NULL,
+		id TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, id)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS read_markers (
+		user_id INTEGER NOT NULL,
+		network TEXT NOT NULL,
+		channel TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, network, channel)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS api_tokens (
+		user_id INTEGER NOT NULL,
+		id TEXT NOT NULL,
+		data BLOB NOT NULL,
+		PRIMARY KEY (user_id, id)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS network_renames (
+		user_id INTEGER NOT NULL,
+		network TEXT NOT NULL,
+		new_id TEXT NOT NULL,
+		PRIMARY KEY (user_id, network)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS link_meta (
+		url TEXT PRIMARY KEY,
+		time INTEGER NOT NULL,
+		data BLOB NOT NULL
+	) WITHOUT ROWID`,
+}
+
+// SQLite implements storage.Store, storage.SessionStore and
+// storage.LinkMetaStore, the message
+// logs of all users are kept in the same database, MessageStore returns
+// the storage.MessageStore of a user
+type SQLite struct {
+	db *sql.DB
+}
+
+func New(path string) (*SQLite, error) {
+	// auto_vacuum has to be set before the tables get created
+	dsn := path + "?_pragma=auto_vacuum(incremental)&_pragma=journal_mode(wal)" +
+		"&_pragma=busy_timeout(" + strconv.Itoa(busyTimeout) + ")&_txlock=immediate"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range schema {
+		_, err = db.Exec(stmt)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	err = migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLite{
+		db: db,
+	}, nil
+}
+
+func (s *SQLite) Close() {
+	s.db.Close()
+}
+
+func transaction(db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLite) Users() ([]*storage.User, error) {
+	rows, err := s.db.Query(`SELECT id, data FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*storage.User
+	for rows.Next() {
+		var id uint64
+		var data []byte
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		user := storage.User{}
+		_, err = user.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		user.ID = id
+		user.IDBytes = idToBytes(id)
+
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *SQLite) SaveUser(user *storage.User) error {
+	return transaction(s.db, func(tx *sql.Tx) error {
+		if user.ID == 0 {
+			res, err := tx.Exec(`INSERT INTO users (data) VALUES (x'')`)
+			if err != nil {
+				return err
+			}
+
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			user.ID = uint64(id)
+			user.IDBytes = idToBytes(user.ID)
+		}
+		user.Username = strconv.FormatUint(user.ID, 10)
+
+		data, err := user.Marshal(nil)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT OR REPLACE INTO users (id, data) VALUES (?, ?)`, user.ID, data)
+		return err
+	})
+}
+
+func (s *SQLite) DeleteUser(user *storage.User) error {
+	return transaction(s.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM users WHERE id = ?`, user.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{"networks", "channels", "open_dms", "messages", "highlight_rules", "mentions", "push_subscriptions", "ignore_rules", "read_markers", "api_tokens", "network_renames"} {
+			_, err = tx.Exec(`DELETE FROM `+table+` WHERE user_id = ?`, user.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLite) Network(user *storage.User, id string) (*storage.Network, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM networks WHERE user_id = ? AND id = ?`, user.ID, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	network := &storage.Network{}
+	_, err = network.Unmarshal(data)
+	return network, err
+}
+
+func (s *SQLite) Networks(user *storage.User) ([]*storage.Network, error) {
+	rows, err := s.db.Query(`SELECT data FROM networks WHERE user_id = ? ORDER BY id`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var networks []*storage.Network
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		network := storage.Network{}
+		_, err = network.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, &network)
+	}
+
+	return networks, rows.Err()
+}
+
+func (s *SQLite) SaveNetwork(user *storage.User, network *storage.Network) error {
+	data, err := network.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO networks (user_id, id, data) VALUES (?, ?, ?)`,
+		user.ID, network.ID, data)
+	return err
+}
+
+func (s *SQLite) RemoveNetwork(user *storage.User, id string) error {
+	return transaction(s.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM networks WHERE user_id = ? AND id = ?`, user.ID, id)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{"channels", "open_dms", "read_markers", "network_renames"} {
+			_, err = tx.Exec(`DELETE FROM `+table+` WHERE user_id = ? AND network = ?`, user.ID, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLite) NetworkRenames(user *storage.User) (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT network, new_id FROM network_renames WHERE user_id = ?`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	renames := map[string]string{}
+	for rows.Next() {
+		var from, to string
+		err = rows.Scan(&from, &to)
+		if err != nil {
+			return nil, err
+		}
+		renames[from] = to
+	}
+
+	return renames, rows.Err()
+}
+
+func (s *SQLite) SaveNetworkRename(user *storage.User, from, to string) error {
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO network_renames (user_id, network, new_id) VALUES (?, ?, ?)`,
+		user.ID, from, to)
+	return err
+}
+
+func (s *SQLite) Channels(user *storage.User) ([]*storage.Channel, error) {
+	rows, err := s.db.Query(`SELECT data FROM channels WHERE user_id = ? ORDER BY network, name`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []*storage.Channel
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		channel := storage.Channel{}
+		_, err = channel.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, &channel)
+	}
+
+	return channels, rows.Err()
+}
+
+func (s *SQLite) SaveChannel(user *storage.User, channel *storage.Channel) error {
+	data, err := channel.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO channels (user_id, network, name, data) VALUES (?, ?, ?, ?)`,
+		user.ID, channel.Network, channel.Name, data)
+	return err
+}
+
+func (s *SQLite) RemoveChannel(user *storage.User, network, channel string) error {
+	_, err := s.db.Exec(`DELETE FROM channels WHERE user_id = ? AND network = ? AND name = ?`,
+		user.ID, network, channel)
+	return err
+}
+
+func (s *SQLite) HasChannel(user *storage.User, network, channel string) bool {
+	var n int
+	err := s.db.QueryRow(`SELECT 1 FROM channels WHERE user_id = ? AND network = ? AND name = ?`,
+		user.ID, network, channel).Scan(&n)
+	return err == nil
+}
+
+func (s *SQLite) OpenDMs(user *storage.User) ([]storage.Tab, error) {
+	rows, err := s.db.Query(`SELECT network, nick FROM open_dms WHERE user_id = ? ORDER BY network, nick`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var openDMs []storage.Tab
+	for rows.Next() {
+		tab := storage.Tab{}
+		err = rows.Scan(&tab.Network, &tab.Name)
+		if err != nil {
+			return nil, err
+		}
+		openDMs = append(openDMs, tab)
+	}
+
+	return openDMs, rows.Err()
+}
+
+func (s *SQLite) AddOpenDM(user *storage.User, network, nick string) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO open_dms (user_id, network, nick) VALUES (?, ?, ?)`,
+		user.ID, network, nick)
+	return err
+}
+
+func (s *SQLite) RemoveOpenDM(user *storage.User, network, nick string) error {
+	_, err := s.db.Exec(`DELETE FROM open_dms WHERE user_id = ? AND network = ? AND nick = ?`,
+		user.ID, network, nick)
+	return err
+}
+
+func (s *SQLite) HighlightRules(user *storage.User) (*storage.HighlightRules, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM highlight_rules WHERE user_id = ?`, user.ID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rules := &storage.HighlightRules{}
+	_, err = rules.Unmarshal(data)
+	return rules, err
+}
+
+func (s *SQLite) SaveHighlightRules(user *storage.User, rules *storage.HighlightRules) error {
+	data, err := rules.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO highlight_rules (user_id, data) VALUES (?, ?)`, user.ID, data)
+	return err
+}
+
+func (s *SQLite) Mentions(user *storage.User) ([]*storage.Mention, error) {
+	rows, err := s.db.Query(`SELECT data FROM mentions WHERE user_id = ? ORDER BY id`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []*storage.Mention
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		mention := storage.Mention{}
+		_, err = mention.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, &mention)
+	}
+
+	return mentions, rows.Err()
+}
+
+func (s *SQLite) SaveMention(user *storage.User, mention *storage.Mention) error {
+	data, err := mention.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO mentions (user_id, id, data) VALUES (?, ?, ?)`,
+		user.ID, mention.ID, data)
+	return err
+}
+
+func (s *SQLite) RemoveMentions(user *storage.User, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, user.ID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	_, err := s.db.Exec(`DELETE FROM mentions WHERE user_id = ? AND id IN (`+placeholders(len(ids))+`)`, args...)
+	return err
+}
+
+func (s *SQLite) PushSubscriptions(user *storage.User) ([]*storage.PushSubscription, error) {
+	rows, err := s.db.Query(`SELECT data FROM push_subscriptions WHERE user_id = ?`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*storage.PushSubscription
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		sub := storage.PushSubscription{}
+		_, err = sub.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, &sub)
+	}
+
+	return subs, rows.Err()
+}
+
+func (s *SQLite) SavePushSubscription(user *storage.User, sub *storage.PushSubscription) error {
+	data, err := sub.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO push_subscriptions (user_id, endpoint, data) VALUES (?, ?, ?)`,
+		user.ID, sub.Endpoint, data)
+	return err
+}
+
+func (s *SQLite) RemovePushSubscription(user *storage.User, endpoint string) error {
+	_, err := s.db.Exec(`DELETE FROM push_subscriptions WHERE user_id = ? AND endpoint = ?`,
+		user.ID, endpoint)
+	return err
+}
+
+func (s *SQLite) IgnoreRules(user *storage.User) ([]*storage.IgnoreRule, error) {
+	rows, err := s.db.Query(`SELECT data FROM ignore_rules WHERE user_id = ? ORDER BY id`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*storage.IgnoreRule
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		rule := storage.IgnoreRule{}
+		_, err = rule.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (s *SQLite) SaveIgnoreRule(user *storage.User, rule *storage.IgnoreRule) error {
+	data, err := rule.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO ignore_rules (user_id, id, data) VALUES (?, ?, ?)`,
+		user.ID, rule.ID, data)
+	return err
+}
+
+func (s *SQLite) RemoveIgnoreRule(user *storage.User, id string) error {
+	_, err := s.db.Exec(`DELETE FROM ignore_rules WHERE user_id = ? AND id = ?`, user.ID, id)
+	return err
+}
+
+func (s *SQLite) ReadMarker(user *storage.User, network, channel string) (*storage.ReadMarker, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM read_markers WHERE user_id = ? AND network = ? AND channel = ?`,
+		user.ID, network, channel).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	marker := &storage.ReadMarker{}
+	_, err = marker.Unmarshal(data)
+	return marker, err
+}
+
+func (s *SQLite) ReadMarkers(user *storage.User) ([]*storage.ReadMarker, error) {
+	rows, err := s.db.Query(`SELECT data FROM read_markers WHERE user_id = ?`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var markers []*storage.ReadMarker
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		marker := storage.ReadMarker{}
+		_, err = marker.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		markers = append(markers, &marker)
+	}
+
+	return markers, rows.Err()
+}
+
+func (s *SQLite) SaveReadMarker(user *storage.User, marker *storage.ReadMarker) error {
+	data, err := marker.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO read_markers (user_id, network, channel, data) VALUES (?, ?, ?, ?)`,
+		user.ID, marker.Network, marker.Channel, data)
+	return err
+}
+
+func (s *SQLite) APITokens(user *storage.User) ([]*storage.APIToken, error) {
+	rows, err := s.db.Query(`SELECT data FROM api_tokens WHERE user_id = ? ORDER BY id`, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*storage.APIToken
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		token := storage.APIToken{}
+		_, err = token.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (s *SQLite) SaveAPIToken(user *storage.User, token *storage.APIToken) error {
+	data, err := token.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO api_tokens (user_id, id, data) VALUES (?, ?, ?)`,
+		user.ID, token.ID, data)
+	return err
+}
+
+func (s *SQLite) RemoveAPIToken(user *storage.User, id string) error {
+	_, err := s.db.Exec(`DELETE FROM api_tokens WHERE user_id = ? AND id = ?`, user.ID, id)
+	return err
+}
+
+func (s *SQLite) Sessions() ([]*session.Session, error) {
+	rows, err := s.db.Query(`SELECT data FROM sessions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*session.Session
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		session := session.Session{}
+		_, err = session.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (s *SQLite) SaveSession(session *session.Session) error {
+	data, err := session.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO sessions (key, data) VALUES (?, ?)`, session.Key(), data)
+	return err
+}
+
+func (s *SQLite) DeleteSession(key string) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE key = ?`, key)
+	return err
+}
+
+func (s *SQLite) LinkMeta(url string) (*storage.LinkMeta, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM link_meta WHERE url = ?`, url).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	meta := &storage.LinkMeta{}
+	return meta, meta.Unmarshal(data)
+}
+
+func (s *SQLite) SaveLinkMeta(meta *storage.LinkMeta) error {
+	data, err := meta.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO link_meta (url, time, data) VALUES (?, ?, ?)`,
+		meta.URL, meta.Time, data)
+	return err
+}
+
+func (s *SQLite) PruneLinkMeta(before int64) error {
+	_, err := s.db.Exec(`DELETE FROM link_meta WHERE time < ?`, before)
+	return err
+}
+
+// placeholders returns n comma separated query parameters
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func idToBytes(i uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return b
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/storage"
+	"github.com/khlieng/dispatch/storage/storagetest"
+)
+
+func open(t *testing.T) *SQLite {
+	dir, err := ioutil.TempDir("", "")
+	require.Nil(t, err)
+
+	db, err := New(filepath.Join(dir, "dispatch.sqlite"))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+	return db
+}
+
+func TestStore(t *testing.T) {
+	storagetest.TestStore(t, func(t *testing.T) storage.Store {
+		return open(t)
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	storagetest.TestSessionStore(t, func(t *testing.T) storage.SessionStore {
+		return open(t)
+	})
+}
+
+func TestLinkMetaStore(t *testing.T) {
+	storagetest.TestLinkMetaStore(t, func(t *testing.T) storage.LinkMetaStore {
+		return open(t)
+	})
+}
+
+func TestMessageStore(t *testing.T) {
+	storagetest.TestMessageStore(t, func(t *testing.T) storage.MessageStore {
+		return open(t).MessageStore(&storage.User{ID: 1})
+	})
+}
+
+// The message logs of users share a table
+func TestMessageStoreUsers(t *testing.T) {
+	db := open(t)
+	storagetest.TestMessageStore(t, func(t *testing.T) storage.MessageStore {
+		user := &storage.User{}
+		require.Nil(t, db.SaveUser(user))
+		return db.MessageStore(user)
+	})
+}
@@ -12,6 +12,10 @@ var (
 
 	GetMessageStore          MessageStoreCreator
 	GetMessageSearchProvider MessageSearchProviderCreator
+
+	// InMemory is set when the storage backend keeps everything in memory,
+	// nothing gets written to the data directory for users then
+	InMemory bool
 )
 
 func Initialize(root, dataRoot, configRoot string) {
@@ -28,6 +32,9 @@ func Initialize(root, dataRoot, configRoot string) {
 
 var (
 	ErrNotFound = errors.New("no item found")
+	// ErrOutdatedIndex is returned by searches the search index is too old
+	// to answer
+	ErrOutdatedIndex = errors.New("The search index is from an older version of dispatch, run dispatch reindex to rebuild it")
 )
 
 type Store interface {
@@ -35,10 +42,17 @@ type Store interface {
 	SaveUser(user *User) error
 	DeleteUser(user *User) error
 
-	Network(user *User, host string) (*Network, error)
+	Network(user *User, id string) (*Network, error)
 	Networks(user *User) ([]*Network, error)
 	SaveNetwork(user *User, network *Network) error
-	RemoveNetwork(user *User, host string) error
+	// RemoveNetwork removes the network along with its channels, open DMs,
+	// read markers and rename
+	RemoveNetwork(user *User, id string) error
+	// NetworkRenames returns the IDs the networks of the user have to be
+	// moved to, keyed by their current ID. They are kept until the network
+	// gets removed from its current ID.
+	NetworkRenames(user *User) (map[string]string, error)
+	SaveNetworkRename(user *User, from, to string) error
 
 	Channels(user *User) ([]*Channel, error)
 	HasChannel(user *User, network, channel string) bool
@@ -48,6 +62,36 @@ type Store interface {
 	OpenDMs(user *User) ([]Tab, error)
 	AddOpenDM(user *User, network, nick string) error
 	RemoveOpenDM(user *User, network, nick string) error
+
+	// HighlightRules returns ErrNotFound if the user has not saved any
+	HighlightRules(user *User) (*HighlightRules, error)
+	SaveHighlightRules(user *User, rules *HighlightRules) error
+
+	// Mentions returns the mentions of the user ordered by ID, which is
+	// the order they happened in
+	Mentions(user *User) ([]*Mention, error)
+	SaveMention(user *User, mention *Mention) error
+	RemoveMentions(user *User, ids []string) error
+
+	// PushSubscriptions are keyed by their endpoint
+	PushSubscriptions(user *User) ([]*PushSubscription, error)
+	SavePushSubscription(user *User, sub *PushSubscription) error
+	RemovePushSubscription(user *User, endpoint string) error
+
+	// IgnoreRules returns the ignore rules of the user ordered by ID
+	IgnoreRules(user *User) ([]*IgnoreRule, error)
+	SaveIgnoreRule(user *User, rule *IgnoreRule) error
+	RemoveIgnoreRule(user *User, id string) error
+
+	// ReadMarker returns ErrNotFound if the channel has no read marker
+	ReadMarker(user *User, network, channel string) (*ReadMarker, error)
+	ReadMarkers(user *User) ([]*ReadMarker, error)
+	SaveReadMarker(user *User, marker *ReadMarker) error
+
+	// APITokens returns the API tokens of the user ordered by ID
+	APITokens(user *User) ([]*APIToken, error)
+	SaveAPIToken(user *User, token *APIToken) error
+	RemoveAPIToken(user *User, id string) error
 }
 
 type SessionStore interface {
@@ -56,19 +100,50 @@ type SessionStore interface {
 	DeleteSession(key string) error
 }
 
+// LinkMetaStore caches the metadata of links in messages, it is shared by
+// all users
+type LinkMetaStore interface {
+	LinkMeta(url string) (*LinkMeta, error)
+	SaveLinkMeta(meta *LinkMeta) error
+	// PruneLinkMeta deletes the metadata fetched before the unix time before
+	PruneLinkMeta(before int64) error
+}
+
 type MessageStore interface {
 	LogMessage(message *Message) error
 	LogMessages(messages []*Message) error
 	Messages(network, channel string, count int, fromID string) ([]Message, bool, error)
 	MessagesByID(network, channel string, ids []string) ([]Message, error)
+	// ForEachMessage calls fn with every message in the store, the messages
+	// of a channel come in a row in the order they were logged
+	ForEachMessage(fn func(*Message) error) error
+	// PruneMessages deletes the oldest messages in each channel and returns
+	// their IDs, limits returns the unix time messages have to be logged
+	// before and the number of messages to keep, 0 disables a limit
+	PruneMessages(limits func(network, channel string) (before int64, keep int)) ([]string, error)
+	// CountUnread returns how many messages with content there are in a
+	// channel after the read marker, all of them are unread if it is nil.
+	// It stops counting at max.
+	CountUnread(network, channel string, marker *ReadMarker, max int) (int, error)
+	// RenameNetwork moves the messages logged on the network from over to
+	// the network to
+	RenameNetwork(from, to string) error
 	Close()
 }
 
+// MessageStoreCompacter is implemented by message stores that need to be
+// compacted to free up the space of deleted messages
+type MessageStoreCompacter interface {
+	Compact() error
+}
+
 type MessageStoreCreator func(*User) (MessageStore, error)
 
 type MessageSearchProvider interface {
 	SearchMessages(network, channel, q string) ([]string, error)
+	Search(query SearchQuery) (*SearchResult, error)
 	Index(id string, message *Message) error
+	DeleteMessages(ids []string) error
 	Close()
 }
 
@@ -205,7 +205,7 @@ func (d *User) Unmarshal(buf []byte) (uint64, error) {
 
 func (d *ClientSettings) Size() (s uint64) {
 
-	s += 1
+	s += 2
 	return
 }
 func (d *ClientSettings) Marshal(buf []byte) ([]byte, error) {
@@ -226,7 +226,14 @@ func (d *ClientSettings) Marshal(buf []byte) ([]byte, error) {
 			buf[0] = 0
 		}
 	}
-	return buf[:i+1], nil
+	{
+		if d.LinkPreviews {
+			buf[1] = 1
+		} else {
+			buf[1] = 0
+		}
+	}
+	return buf[:i+2], nil
 }
 
 func (d *ClientSettings) Unmarshal(buf []byte) (uint64, error) {
@@ -235,7 +242,10 @@ func (d *ClientSettings) Unmarshal(buf []byte) (uint64, error) {
 	{
 		d.ColoredNicks = buf[0] == 1
 	}
-	return i + 1, nil
+	{
+		d.LinkPreviews = buf[1] == 1
+	}
+	return i + 2, nil
 }
 
 func (d *Network) Size() (s uint64) {
@@ -375,6 +385,44 @@ func (d *Network) Size() (s uint64) {
 		}
 		s += l
 	}
+	{
+		l := uint64(len(d.Servers))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Servers {
+
+			{
+				s += d.Servers[k0].Size()
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
 	s += 1
 	return
 }
@@ -567,6 +615,53 @@ func (d *Network) Marshal(buf []byte) ([]byte, error) {
 		copy(buf[i+1:], d.Password)
 		i += l
 	}
+	{
+		l := uint64(len(d.Servers))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Servers {
+
+			{
+				nbuf, err := d.Servers[k0].Marshal(buf[i+1:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.ID)
+		i += l
+	}
 	return buf[:i+1], nil
 }
 
@@ -756,13 +851,67 @@ func (d *Network) Unmarshal(buf []byte) (uint64, error) {
 		d.Password = string(buf[i+1 : i+1+l])
 		i += l
 	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Servers)) >= l {
+			d.Servers = d.Servers[:l]
+		} else {
+			d.Servers = make([]Server, l)
+		}
+		for k0 := range d.Servers {
+
+			{
+				ni, err := d.Servers[k0].Unmarshal(buf[i+1:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+1 : i+1+l])
+		i += l
+	}
 	return i + 1, nil
 }
 
-func (d *Channel) Size() (s uint64) {
+func (d *Server) Size() (s uint64) {
 
 	{
-		l := uint64(len(d.Network))
+		l := uint64(len(d.Host))
 
 		{
 
@@ -777,7 +926,7 @@ func (d *Channel) Size() (s uint64) {
 		s += l
 	}
 	{
-		l := uint64(len(d.Name))
+		l := uint64(len(d.Port))
 
 		{
 
@@ -791,9 +940,10 @@ func (d *Channel) Size() (s uint64) {
 		}
 		s += l
 	}
+	s += 1
 	return
 }
-func (d *Channel) Marshal(buf []byte) ([]byte, error) {
+func (d *Server) Marshal(buf []byte) ([]byte, error) {
 	size := d.Size()
 	{
 		if uint64(cap(buf)) >= size {
@@ -805,7 +955,7 @@ func (d *Channel) Marshal(buf []byte) ([]byte, error) {
 	i := uint64(0)
 
 	{
-		l := uint64(len(d.Network))
+		l := uint64(len(d.Host))
 
 		{
 
@@ -820,11 +970,11 @@ func (d *Channel) Marshal(buf []byte) ([]byte, error) {
 			i++
 
 		}
-		copy(buf[i+0:], d.Network)
+		copy(buf[i+0:], d.Host)
 		i += l
 	}
 	{
-		l := uint64(len(d.Name))
+		l := uint64(len(d.Port))
 
 		{
 
@@ -839,13 +989,20 @@ func (d *Channel) Marshal(buf []byte) ([]byte, error) {
 			i++
 
 		}
-		copy(buf[i+0:], d.Name)
+		copy(buf[i+0:], d.Port)
 		i += l
 	}
-	return buf[:i+0], nil
+	{
+		if d.TLS {
+			buf[i+0] = 1
+		} else {
+			buf[i+0] = 0
+		}
+	}
+	return buf[:i+1], nil
 }
 
-func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
+func (d *Server) Unmarshal(buf []byte) (uint64, error) {
 	i := uint64(0)
 
 	{
@@ -865,7 +1022,7 @@ func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Network = string(buf[i+0 : i+0+l])
+		d.Host = string(buf[i+0 : i+0+l])
 		i += l
 	}
 	{
@@ -885,46 +1042,19 @@ func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
 			l = t
 
 		}
-		d.Name = string(buf[i+0 : i+0+l])
+		d.Port = string(buf[i+0 : i+0+l])
 		i += l
 	}
-	return i + 0, nil
-}
-
-func (d *Message) Size() (s uint64) {
-
 	{
-		l := uint64(len(d.ID))
-
-		{
-
-			t := l
-			for t >= 0x80 {
-				t >>= 7
-				s++
-			}
-			s++
-
-		}
-		s += l
+		d.TLS = buf[i+0] == 1
 	}
-	{
-		l := uint64(len(d.From))
-
-		{
+	return i + 1, nil
+}
 
-			t := l
-			for t >= 0x80 {
-				t >>= 7
-				s++
-			}
-			s++
+func (d *Channel) Size() (s uint64) {
 
-		}
-		s += l
-	}
 	{
-		l := uint64(len(d.Content))
+		l := uint64(len(d.Network))
 
 		{
 
@@ -939,7 +1069,7 @@ func (d *Message) Size() (s uint64) {
 		s += l
 	}
 	{
-		l := uint64(len(d.Events))
+		l := uint64(len(d.Name))
 
 		{
 
@@ -951,20 +1081,11 @@ func (d *Message) Size() (s uint64) {
 			s++
 
 		}
-
-		for k0 := range d.Events {
-
-			{
-				s += d.Events[k0].Size()
-			}
-
-		}
-
+		s += l
 	}
-	s += 8
 	return
 }
-func (d *Message) Marshal(buf []byte) ([]byte, error) {
+func (d *Channel) Marshal(buf []byte) ([]byte, error) {
 	size := d.Size()
 	{
 		if uint64(cap(buf)) >= size {
@@ -976,7 +1097,7 @@ func (d *Message) Marshal(buf []byte) ([]byte, error) {
 	i := uint64(0)
 
 	{
-		l := uint64(len(d.ID))
+		l := uint64(len(d.Network))
 
 		{
 
@@ -991,11 +1112,11 @@ func (d *Message) Marshal(buf []byte) ([]byte, error) {
 			i++
 
 		}
-		copy(buf[i+0:], d.ID)
+		copy(buf[i+0:], d.Network)
 		i += l
 	}
 	{
-		l := uint64(len(d.From))
+		l := uint64(len(d.Name))
 
 		{
 
@@ -1010,40 +1131,211 @@ func (d *Message) Marshal(buf []byte) ([]byte, error) {
 			i++
 
 		}
-		copy(buf[i+0:], d.From)
+		copy(buf[i+0:], d.Name)
 		i += l
 	}
+	return buf[:i+0], nil
+}
+
+func (d *Channel) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
 	{
-		l := uint64(len(d.Content))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
-			for t >= 0x80 {
-				buf[i+0] = byte(t) | 0x80
-				t >>= 7
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
 				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
 			}
-			buf[i+0] = byte(t)
 			i++
 
+			l = t
+
 		}
-		copy(buf[i+0:], d.Content)
+		d.Network = string(buf[i+0 : i+0+l])
 		i += l
 	}
 	{
-
-		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Time
-
-	}
-	{
-		l := uint64(len(d.Events))
+		l := uint64(0)
 
 		{
 
-			t := uint64(l)
-
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	return i + 0, nil
+}
+
+func (d *Message) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.From))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Content))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Events))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Events {
+
+			{
+				s += d.Events[k0].Size()
+			}
+
+		}
+
+	}
+	s += 8
+	return
+}
+func (d *Message) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.From))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.From)
+		i += l
+	}
+	{
+		l := uint64(len(d.Content))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Content)
+		i += l
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Time
+
+	}
+	{
+		l := uint64(len(d.Events))
+
+		{
+
+			t := uint64(l)
+
 			for t >= 0x80 {
 				buf[i+8] = byte(t) | 0x80
 				t >>= 7
@@ -1383,3 +1675,1958 @@ func (d *Event) Unmarshal(buf []byte) (uint64, error) {
 	}
 	return i + 8, nil
 }
+
+func (d *Tab) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	return
+}
+func (d *Tab) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Network)
+		i += l
+	}
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Name)
+		i += l
+	}
+	return buf[:i+0], nil
+}
+
+func (d *Tab) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Network = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	return i + 0, nil
+}
+
+func (d *HighlightRules) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Keywords))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Keywords {
+
+			{
+				l := uint64(len(d.Keywords[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.Patterns))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Patterns {
+
+			{
+				l := uint64(len(d.Patterns[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	{
+		l := uint64(len(d.Muted))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Muted {
+
+			{
+				s += d.Muted[k0].Size()
+			}
+
+		}
+
+	}
+	s += 1
+	return
+}
+func (d *HighlightRules) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		if d.Nick {
+			buf[0] = 1
+		} else {
+			buf[0] = 0
+		}
+	}
+	{
+		l := uint64(len(d.Keywords))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Keywords {
+
+			{
+				l := uint64(len(d.Keywords[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+1] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+1] = byte(t)
+					i++
+
+				}
+				copy(buf[i+1:], d.Keywords[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.Patterns))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Patterns {
+
+			{
+				l := uint64(len(d.Patterns[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+1] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+1] = byte(t)
+					i++
+
+				}
+				copy(buf[i+1:], d.Patterns[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(len(d.Muted))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Muted {
+
+			{
+				nbuf, err := d.Muted[k0].Marshal(buf[i+1:])
+				if err != nil {
+					return nil, err
+				}
+				i += uint64(len(nbuf))
+			}
+
+		}
+	}
+	return buf[:i+1], nil
+}
+
+func (d *HighlightRules) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		d.Nick = buf[0] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Keywords)) >= l {
+			d.Keywords = d.Keywords[:l]
+		} else {
+			d.Keywords = make([]string, l)
+		}
+		for k0 := range d.Keywords {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+1] & 0x7F)
+					for buf[i+1]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+1]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Keywords[k0] = string(buf[i+1 : i+1+l])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Patterns)) >= l {
+			d.Patterns = d.Patterns[:l]
+		} else {
+			d.Patterns = make([]string, l)
+		}
+		for k0 := range d.Patterns {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+1] & 0x7F)
+					for buf[i+1]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+1]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Patterns[k0] = string(buf[i+1 : i+1+l])
+				i += l
+			}
+
+		}
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Muted)) >= l {
+			d.Muted = d.Muted[:l]
+		} else {
+			d.Muted = make([]Tab, l)
+		}
+		for k0 := range d.Muted {
+
+			{
+				ni, err := d.Muted[k0].Unmarshal(buf[i+1:])
+				if err != nil {
+					return 0, err
+				}
+				i += ni
+			}
+
+		}
+	}
+	return i + 1, nil
+}
+
+func (d *Mention) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.To))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.From))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Content))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 9
+	return
+}
+func (d *Mention) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Network)
+		i += l
+	}
+	{
+		l := uint64(len(d.To))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.To)
+		i += l
+	}
+	{
+		l := uint64(len(d.From))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.From)
+		i += l
+	}
+	{
+		l := uint64(len(d.Content))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Content)
+		i += l
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Time
+
+	}
+	{
+		if d.Read {
+			buf[i+8] = 1
+		} else {
+			buf[i+8] = 0
+		}
+	}
+	return buf[:i+9], nil
+}
+
+func (d *Mention) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Network = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.To = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.From = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Content = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Time = *(*int64)(unsafe.Pointer(&buf[i+0]))
+
+	}
+	{
+		d.Read = buf[i+8] == 1
+	}
+	return i + 9, nil
+}
+
+func (d *PushSubscription) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Endpoint))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.P256dh))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Auth))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	return
+}
+func (d *PushSubscription) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Endpoint))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Endpoint)
+		i += l
+	}
+	{
+		l := uint64(len(d.P256dh))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.P256dh)
+		i += l
+	}
+	{
+		l := uint64(len(d.Auth))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Auth)
+		i += l
+	}
+	return buf[:i+0], nil
+}
+
+func (d *PushSubscription) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Endpoint = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.P256dh = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Auth = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	return i + 0, nil
+}
+
+func (d *IgnoreRule) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Nick))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Mask))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Channel))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Types))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Types {
+
+			{
+				l := uint64(len(d.Types[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 1
+	return
+}
+func (d *IgnoreRule) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.Nick))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Nick)
+		i += l
+	}
+	{
+		l := uint64(len(d.Mask))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Mask)
+		i += l
+	}
+	{
+		if d.Regex {
+			buf[i+0] = 1
+		} else {
+			buf[i+0] = 0
+		}
+	}
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Network)
+		i += l
+	}
+	{
+		l := uint64(len(d.Channel))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		copy(buf[i+1:], d.Channel)
+		i += l
+	}
+	{
+		l := uint64(len(d.Types))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+1] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+1] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Types {
+
+			{
+				l := uint64(len(d.Types[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+1] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+1] = byte(t)
+					i++
+
+				}
+				copy(buf[i+1:], d.Types[k0])
+				i += l
+			}
+
+		}
+	}
+	return buf[:i+1], nil
+}
+
+func (d *IgnoreRule) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Nick = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Mask = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		d.Regex = buf[i+0] == 1
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Network = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Channel = string(buf[i+1 : i+1+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+1] & 0x7F)
+			for buf[i+1]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+1]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Types)) >= l {
+			d.Types = d.Types[:l]
+		} else {
+			d.Types = make([]string, l)
+		}
+		for k0 := range d.Types {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+1] & 0x7F)
+					for buf[i+1]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+1]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Types[k0] = string(buf[i+1 : i+1+l])
+				i += l
+			}
+
+		}
+	}
+	return i + 1, nil
+}
+
+func (d *ReadMarker) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Channel))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	s += 8
+	return
+}
+func (d *ReadMarker) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.Network))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Network)
+		i += l
+	}
+	{
+		l := uint64(len(d.Channel))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Channel)
+		i += l
+	}
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Time
+
+	}
+	return buf[:i+8], nil
+}
+
+func (d *ReadMarker) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Network = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Channel = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+
+		d.Time = *(*int64)(unsafe.Pointer(&buf[i+0]))
+
+	}
+	return i + 8, nil
+}
+
+func (d *APIToken) Size() (s uint64) {
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Hash))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+		s += l
+	}
+	{
+		l := uint64(len(d.Scopes))
+
+		{
+
+			t := l
+			for t >= 0x80 {
+				t >>= 7
+				s++
+			}
+			s++
+
+		}
+
+		for k0 := range d.Scopes {
+
+			{
+				l := uint64(len(d.Scopes[k0]))
+
+				{
+
+					t := l
+					for t >= 0x80 {
+						t >>= 7
+						s++
+					}
+					s++
+
+				}
+				s += l
+			}
+
+		}
+
+	}
+	s += 16
+	return
+}
+func (d *APIToken) Marshal(buf []byte) ([]byte, error) {
+	size := d.Size()
+	{
+		if uint64(cap(buf)) >= size {
+			buf = buf[:size]
+		} else {
+			buf = make([]byte, size)
+		}
+	}
+	i := uint64(0)
+
+	{
+		l := uint64(len(d.ID))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.ID)
+		i += l
+	}
+	{
+		l := uint64(len(d.Name))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Name)
+		i += l
+	}
+	{
+		l := uint64(len(d.Hash))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		copy(buf[i+0:], d.Hash)
+		i += l
+	}
+	{
+		l := uint64(len(d.Scopes))
+
+		{
+
+			t := uint64(l)
+
+			for t >= 0x80 {
+				buf[i+0] = byte(t) | 0x80
+				t >>= 7
+				i++
+			}
+			buf[i+0] = byte(t)
+			i++
+
+		}
+		for k0 := range d.Scopes {
+
+			{
+				l := uint64(len(d.Scopes[k0]))
+
+				{
+
+					t := uint64(l)
+
+					for t >= 0x80 {
+						buf[i+0] = byte(t) | 0x80
+						t >>= 7
+						i++
+					}
+					buf[i+0] = byte(t)
+					i++
+
+				}
+				copy(buf[i+0:], d.Scopes[k0])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+0])) = d.Created
+
+	}
+	{
+
+		*(*int64)(unsafe.Pointer(&buf[i+8])) = d.LastUsed
+
+	}
+	return buf[:i+16], nil
+}
+
+func (d *APIToken) Unmarshal(buf []byte) (uint64, error) {
+	i := uint64(0)
+
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.ID = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		d.Name = string(buf[i+0 : i+0+l])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Hash)) >= l {
+			d.Hash = d.Hash[:l]
+		} else {
+			d.Hash = make([]byte, l)
+		}
+		copy(d.Hash, buf[i+0:])
+		i += l
+	}
+	{
+		l := uint64(0)
+
+		{
+
+			bs := uint8(7)
+			t := uint64(buf[i+0] & 0x7F)
+			for buf[i+0]&0x80 == 0x80 {
+				i++
+				t |= uint64(buf[i+0]&0x7F) << bs
+				bs += 7
+			}
+			i++
+
+			l = t
+
+		}
+		if uint64(cap(d.Scopes)) >= l {
+			d.Scopes = d.Scopes[:l]
+		} else {
+			d.Scopes = make([]string, l)
+		}
+		for k0 := range d.Scopes {
+
+			{
+				l := uint64(0)
+
+				{
+
+					bs := uint8(7)
+					t := uint64(buf[i+0] & 0x7F)
+					for buf[i+0]&0x80 == 0x80 {
+						i++
+						t |= uint64(buf[i+0]&0x7F) << bs
+						bs += 7
+					}
+					i++
+
+					l = t
+
+				}
+				d.Scopes[k0] = string(buf[i+0 : i+0+l])
+				i += l
+			}
+
+		}
+	}
+	{
+
+		d.Created = *(*int64)(unsafe.Pointer(&buf[i+0]))
+
+	}
+	{
+
+		d.LastUsed = *(*int64)(unsafe.Pointer(&buf[i+8]))
+
+	}
+	return i + 16, nil
+}
@@ -0,0 +1,1107 @@
+// Package storagetest checks that implementations of the storage interfaces
+// behave the way the rest of dispatch expects them to. Every backend runs
+// these tests from its own test files.
+package storagetest
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/kjk/betterguid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khlieng/dispatch/pkg/linkmeta"
+	"github.com/khlieng/dispatch/pkg/session"
+	"github.com/khlieng/dispatch/storage"
+)
+
+// TestStore runs the tests for storage.Store, open has to return an empty
+// store every time it gets called and clean it up when t is done
+func TestStore(t *testing.T, open func(t *testing.T) storage.Store) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, open(t)) })
+	t.Run("Networks", func(t *testing.T) { testNetworks(t, open(t)) })
+	t.Run("RemoveNetwork", func(t *testing.T) { testRemoveNetwork(t, open(t)) })
+	t.Run("NetworkRenames", func(t *testing.T) { testNetworkRenames(t, open(t)) })
+	t.Run("Channels", func(t *testing.T) { testChannels(t, open(t)) })
+	t.Run("OpenDMs", func(t *testing.T) { testOpenDMs(t, open(t)) })
+	t.Run("HighlightRules", func(t *testing.T) { testHighlightRules(t, open(t)) })
+	t.Run("Mentions", func(t *testing.T) { testMentions(t, open(t)) })
+	t.Run("PushSubscriptions", func(t *testing.T) { testPushSubscriptions(t, open(t)) })
+	t.Run("IgnoreRules", func(t *testing.T) { testIgnoreRules(t, open(t)) })
+	t.Run("ReadMarkers", func(t *testing.T) { testReadMarkers(t, open(t)) })
+	t.Run("APITokens", func(t *testing.T) { testAPITokens(t, open(t)) })
+	t.Run("DeleteUser", func(t *testing.T) { testDeleteUser(t, open(t)) })
+}
+
+// TestSessionStore runs the tests for storage.SessionStore, open has to
+// return an empty store every time it gets called and clean it up when t
+// is done
+func TestSessionStore(t *testing.T, open func(t *testing.T) storage.SessionStore) {
+	store := open(t)
+
+	sessions, err := store.Sessions()
+	require.Nil(t, err)
+	assert.Len(t, sessions, 0)
+
+	s1, err := session.New(1)
+	require.Nil(t, err)
+	s2, err := session.New(2)
+	require.Nil(t, err)
+
+	require.Nil(t, store.SaveSession(s1))
+	require.Nil(t, store.SaveSession(s2))
+	require.Nil(t, store.SaveSession(s1))
+
+	sessions, err = store.Sessions()
+	require.Nil(t, err)
+	require.Len(t, sessions, 2)
+
+	byKey := map[string]*session.Session{}
+	for _, s := range sessions {
+		byKey[s.Key()] = s
+	}
+	require.Contains(t, byKey, s1.Key())
+	require.Contains(t, byKey, s2.Key())
+	assert.Equal(t, uint64(1), byKey[s1.Key()].UserID)
+	assert.Equal(t, uint64(2), byKey[s2.Key()].UserID)
+	assert.False(t, byKey[s1.Key()].Expired())
+
+	require.Nil(t, store.DeleteSession(s1.Key()))
+	require.Nil(t, store.DeleteSession("missing"))
+
+	sessions, err = store.Sessions()
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, s2.Key(), sessions[0].Key())
+}
+
+// TestLinkMetaStore runs the tests for storage.LinkMetaStore, open has to
+// return an empty store every time it gets called and clean it up when t
+// is done
+func TestLinkMetaStore(t *testing.T, open func(t *testing.T) storage.LinkMetaStore) {
+	store := open(t)
+
+	_, err := store.LinkMeta("https://example.com")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	example := &storage.LinkMeta{
+		URL: "https://example.com",
+		Meta: &linkmeta.Meta{
+			URL:         "https://example.com",
+			Title:       "Example",
+			Description: "An example",
+			ImageURL:    "https://example.com/image.png",
+		},
+		Time: 100,
+	}
+	// Links that had nothing to fetch get stored too
+	failed := &storage.LinkMeta{
+		URL:  "https://example.com/404",
+		Time: 200,
+	}
+	require.Nil(t, store.SaveLinkMeta(example))
+	require.Nil(t, store.SaveLinkMeta(failed))
+
+	meta, err := store.LinkMeta("https://example.com")
+	require.Nil(t, err)
+	assert.Equal(t, example, meta)
+
+	meta, err = store.LinkMeta("https://example.com/404")
+	require.Nil(t, err)
+	assert.Equal(t, failed, meta)
+
+	example.Meta.Title = "Updated"
+	example.Time = 300
+	require.Nil(t, store.SaveLinkMeta(example))
+
+	meta, err = store.LinkMeta("https://example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "Updated", meta.Meta.Title)
+
+	require.Nil(t, store.PruneLinkMeta(250))
+
+	_, err = store.LinkMeta("https://example.com/404")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	_, err = store.LinkMeta("https://example.com")
+	assert.Nil(t, err)
+}
+
+// TestMessageStore runs the tests for storage.MessageStore, open has to
+// return an empty store every time it gets called and clean it up when t
+// is done
+func TestMessageStore(t *testing.T, open func(t *testing.T) storage.MessageStore) {
+	t.Run("Messages", func(t *testing.T) { testMessages(t, open(t)) })
+	t.Run("Channels", func(t *testing.T) { testMessageChannels(t, open(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdateMessage(t, open(t)) })
+	t.Run("MessagesByID", func(t *testing.T) { testMessagesByID(t, open(t)) })
+	t.Run("ForEachMessage", func(t *testing.T) { testForEachMessage(t, open(t)) })
+	t.Run("PruneMessages", func(t *testing.T) { testPruneMessages(t, open(t)) })
+	t.Run("CountUnread", func(t *testing.T) { testCountUnread(t, open(t)) })
+	t.Run("RenameNetwork", func(t *testing.T) { testRenameNetwork(t, open(t)) })
+}
+
+func newUser(t *testing.T, store storage.Store) *storage.User {
+	user := &storage.User{}
+	require.Nil(t, store.SaveUser(user))
+	return user
+}
+
+func testUsers(t *testing.T, store storage.Store) {
+	users, err := store.Users()
+	require.Nil(t, err)
+	assert.Len(t, users, 0)
+
+	u1 := newUser(t, store)
+	u2 := newUser(t, store)
+	assert.Equal(t, uint64(1), u1.ID)
+	assert.Equal(t, "1", u1.Username)
+	assert.Equal(t, uint64(2), u2.ID)
+	assert.Equal(t, "2", u2.Username)
+
+	// Saving a user again keeps its ID
+	require.Nil(t, store.SaveUser(u1))
+	assert.Equal(t, uint64(1), u1.ID)
+
+	users, err = store.Users()
+	require.Nil(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, uint64(1), users[0].ID)
+	assert.Equal(t, "1", users[0].Username)
+	assert.Equal(t, u1.IDBytes, users[0].IDBytes)
+	assert.Equal(t, uint64(2), users[1].ID)
+
+	// Users with an ID, like the ones being migrated from another store,
+	// keep it and new users get IDs after it
+	u10 := &storage.User{ID: 10, IDBytes: idBytes(10)}
+	require.Nil(t, store.SaveUser(u10))
+	assert.Equal(t, "10", u10.Username)
+
+	u11 := newUser(t, store)
+	assert.Equal(t, uint64(11), u11.ID)
+
+	users, err = store.Users()
+	require.Nil(t, err)
+	assert.Len(t, users, 4)
+}
+
+func testNetworks(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	_, err := store.Network(user, "freenode")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	networks, err := store.Networks(user)
+	require.Nil(t, err)
+	assert.Len(t, networks, 0)
+
+	freenode := &storage.Network{
+		ID:   "freenode",
+		Name: "freenode",
+		Host: "irc.freenode.net",
+		Port: "6697",
+		TLS:  true,
+		Nick: "nick",
+		Servers: []storage.Server{
+			{Host: "irc.freenode.net", Port: "6697", TLS: true},
+		},
+	}
+	oftc := &storage.Network{
+		ID:   "oftc",
+		Host: "irc.oftc.net",
+	}
+	require.Nil(t, store.SaveNetwork(user, oftc))
+	require.Nil(t, store.SaveNetwork(user, freenode))
+
+	network, err := store.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.Equal(t, freenode, network)
+
+	networks, err = store.Networks(user)
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.Network{freenode, oftc}, networks)
+
+	freenode.Nick = "other"
+	require.Nil(t, store.SaveNetwork(user, freenode))
+
+	network, err = store.Network(user, "freenode")
+	require.Nil(t, err)
+	assert.Equal(t, "other", network.Nick)
+
+	networks, err = store.Networks(other)
+	require.Nil(t, err)
+	assert.Len(t, networks, 0)
+
+	_, err = store.Network(other, "freenode")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func testRemoveNetwork(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	// The ID of the second network starts with the ID of the first one,
+	// removing the first one can not touch the second one
+	for _, u := range []*storage.User{user, other} {
+		for _, id := range []string{"freenode", "freenode-2"} {
+			require.Nil(t, store.SaveNetwork(u, &storage.Network{ID: id, Host: "irc.freenode.net"}))
+			require.Nil(t, store.SaveChannel(u, &storage.Channel{Network: id, Name: "#go"}))
+			require.Nil(t, store.AddOpenDM(u, id, "bob"))
+			require.Nil(t, store.SaveReadMarker(u, &storage.ReadMarker{Network: id, Channel: "#go", Time: 1}))
+		}
+	}
+
+	require.Nil(t, store.RemoveNetwork(user, "freenode"))
+
+	_, err := store.Network(user, "freenode")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	networks, err := store.Networks(user)
+	require.Nil(t, err)
+	require.Len(t, networks, 1)
+	assert.Equal(t, "freenode-2", networks[0].ID)
+
+	channels, err := store.Channels(user)
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.Channel{{Network: "freenode-2", Name: "#go"}}, channels)
+	assert.False(t, store.HasChannel(user, "freenode", "#go"))
+
+	openDMs, err := store.OpenDMs(user)
+	require.Nil(t, err)
+	assert.Equal(t, []storage.Tab{{Network: "freenode-2", Name: "bob"}}, openDMs)
+
+	_, err = store.ReadMarker(user, "freenode", "#go")
+	assert.Equal(t, storage.ErrNotFound, err)
+	markers, err := store.ReadMarkers(user)
+	require.Nil(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, "freenode-2", markers[0].Network)
+
+	networks, err = store.Networks(other)
+	require.Nil(t, err)
+	assert.Len(t, networks, 2)
+
+	channels, err = store.Channels(other)
+	require.Nil(t, err)
+	assert.Len(t, channels, 2)
+
+	openDMs, err = store.OpenDMs(other)
+	require.Nil(t, err)
+	assert.Len(t, openDMs, 2)
+
+	markers, err = store.ReadMarkers(other)
+	require.Nil(t, err)
+	assert.Len(t, markers, 2)
+}
+
+func testNetworkRenames(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	renames, err := store.NetworkRenames(user)
+	require.Nil(t, err)
+	assert.Len(t, renames, 0)
+
+	require.Nil(t, store.SaveNetwork(user, &storage.Network{ID: "irc.freenode.net"}))
+	require.Nil(t, store.SaveNetworkRename(user, "irc.freenode.net", "a"))
+	require.Nil(t, store.SaveNetworkRename(user, "irc.freenode.net", "b"))
+	require.Nil(t, store.SaveNetworkRename(user, "irc.oftc.net", "c"))
+	require.Nil(t, store.SaveNetworkRename(other, "irc.freenode.net", "d"))
+
+	renames, err = store.NetworkRenames(user)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"irc.freenode.net": "b", "irc.oftc.net": "c"}, renames)
+
+	// Removing the network is what finishes the rename
+	require.Nil(t, store.RemoveNetwork(user, "irc.freenode.net"))
+
+	renames, err = store.NetworkRenames(user)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"irc.oftc.net": "c"}, renames)
+
+	renames, err = store.NetworkRenames(other)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"irc.freenode.net": "d"}, renames)
+}
+
+func testChannels(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	channels, err := store.Channels(user)
+	require.Nil(t, err)
+	assert.Len(t, channels, 0)
+
+	goNuts := &storage.Channel{Network: "freenode", Name: "#go-nuts"}
+	goChan := &storage.Channel{Network: "freenode", Name: "#go"}
+	rust := &storage.Channel{Network: "oftc", Name: "#rust"}
+	require.Nil(t, store.SaveChannel(user, rust))
+	require.Nil(t, store.SaveChannel(user, goNuts))
+	require.Nil(t, store.SaveChannel(user, goChan))
+	require.Nil(t, store.SaveChannel(user, goChan))
+
+	channels, err = store.Channels(user)
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.Channel{goChan, goNuts, rust}, channels)
+
+	assert.True(t, store.HasChannel(user, "freenode", "#go"))
+	assert.False(t, store.HasChannel(user, "freenode", "#rust"))
+	assert.False(t, store.HasChannel(other, "freenode", "#go"))
+
+	require.Nil(t, store.RemoveChannel(user, "freenode", "#go"))
+	require.Nil(t, store.RemoveChannel(user, "freenode", "#missing"))
+
+	channels, err = store.Channels(user)
+	require.Nil(t, err)
+	assert.Equal(t, []*storage.Channel{goNuts, rust}, channels)
+	assert.False(t, store.HasChannel(user, "freenode", "#go"))
+
+	channels, err = store.Channels(other)
+	require.Nil(t, err)
+	assert.Len(t, channels, 0)
+}
+
+func testOpenDMs(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	openDMs, err := store.OpenDMs(user)
+	require.Nil(t, err)
+	assert.Len(t, openDMs, 0)
+
+	// Network IDs and nicks can contain most characters, they have to
+	// come back out the way they went in
+	require.Nil(t, store.AddOpenDM(user, "irc.freenode.net-2", "bob"))
+	require.Nil(t, store.AddOpenDM(user, "irc.freenode.net", "[alice]"))
+	require.Nil(t, store.AddOpenDM(user, "irc.freenode.net", "a:b|c"))
+	require.Nil(t, store.AddOpenDM(user, "irc.freenode.net", "a:b|c"))
+	require.Nil(t, store.AddOpenDM(other, "irc.freenode.net", "carol"))
+
+	openDMs, err = store.OpenDMs(user)
+	require.Nil(t, err)
+	assert.Equal(t, []storage.Tab{
+		{Network: "irc.freenode.net", Name: "[alice]"},
+		{Network: "irc.freenode.net", Name: "a:b|c"},
+		{Network: "irc.freenode.net-2", Name: "bob"},
+	}, openDMs)
+
+	require.Nil(t, store.RemoveOpenDM(user, "irc.freenode.net", "[alice]"))
+	require.Nil(t, store.RemoveOpenDM(user, "irc.freenode.net", "missing"))
+
+	openDMs, err = store.OpenDMs(user)
+	require.Nil(t, err)
+	assert.Equal(t, []storage.Tab{
+		{Network: "irc.freenode.net", Name: "a:b|c"},
+		{Network: "irc.freenode.net-2", Name: "bob"},
+	}, openDMs)
+
+	openDMs, err = store.OpenDMs(other)
+	require.Nil(t, err)
+	assert.Equal(t, []storage.Tab{{Network: "irc.freenode.net", Name: "carol"}}, openDMs)
+}
+
+func testHighlightRules(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	_, err := store.HighlightRules(user)
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	rules := &storage.HighlightRules{
+		Nick:     true,
+		Keywords: []string{"dispatch", "go"},
+		Patterns: []string{`(?i)deploy(ed|ing)?`},
+		Muted:    []storage.Tab{{Network: "freenode", Name: "#spam"}},
+	}
+	require.Nil(t, store.SaveHighlightRules(user, rules))
+
+	stored, err := store.HighlightRules(user)
+	require.Nil(t, err)
+	assert.Equal(t, rules, stored)
+
+	rules = &storage.HighlightRules{Keywords: []string{"other"}}
+	require.Nil(t, store.SaveHighlightRules(user, rules))
+
+	stored, err = store.HighlightRules(user)
+	require.Nil(t, err)
+	assert.Equal(t, rules, stored)
+
+	_, err = store.HighlightRules(other)
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func testMentions(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	mentions, err := store.Mentions(user)
+	require.Nil(t, err)
+	assert.Len(t, mentions, 0)
+
+	ids := []string{betterguid.New(), betterguid.New(), betterguid.New()}
+	for i, id := range ids {
+		require.Nil(t, store.SaveMention(user, &storage.Mention{
+			ID:      id,
+			Network: "freenode",
+			To:      "#go",
+			From:    "bob",
+			Content: "hi " + strconv.Itoa(i),
+			Time:    int64(i),
+		}))
+	}
+	require.Nil(t, store.SaveMention(other, &storage.Mention{ID: betterguid.New()}))
+
+	mentions, err = store.Mentions(user)
+	require.Nil(t, err)
+	require.Len(t, mentions, 3)
+	for i, m := range mentions {
+		assert.Equal(t, ids[i], m.ID)
+		assert.Equal(t, "hi "+strconv.Itoa(i), m.Content)
+		assert.False(t, m.Read)
+	}
+
+	mentions[1].Read = true
+	require.Nil(t, store.SaveMention(user, mentions[1]))
+	require.Nil(t, store.RemoveMentions(user, []string{ids[0], "missing"}))
+	require.Nil(t, store.RemoveMentions(user, nil))
+
+	mentions, err = store.Mentions(user)
+	require.Nil(t, err)
+	require.Len(t, mentions, 2)
+	assert.Equal(t, ids[1], mentions[0].ID)
+	assert.True(t, mentions[0].Read)
+	assert.Equal(t, ids[2], mentions[1].ID)
+
+	mentions, err = store.Mentions(other)
+	require.Nil(t, err)
+	assert.Len(t, mentions, 1)
+}
+
+func testPushSubscriptions(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	subs, err := store.PushSubscriptions(user)
+	require.Nil(t, err)
+	assert.Len(t, subs, 0)
+
+	sub := &storage.PushSubscription{Endpoint: "https://push.example.com/1", P256dh: "key", Auth: "auth"}
+	require.Nil(t, store.SavePushSubscription(user, sub))
+	require.Nil(t, store.SavePushSubscription(user, &storage.PushSubscription{Endpoint: "https://push.example.com/2"}))
+	require.Nil(t, store.SavePushSubscription(other, &storage.PushSubscription{Endpoint: "https://push.example.com/3"}))
+
+	// Subscribing again with the same endpoint replaces the keys
+	sub.Auth = "new auth"
+	require.Nil(t, store.SavePushSubscription(user, sub))
+
+	subs, err = store.PushSubscriptions(user)
+	require.Nil(t, err)
+	require.Len(t, subs, 2)
+	for _, s := range subs {
+		if s.Endpoint == sub.Endpoint {
+			assert.Equal(t, sub, s)
+		}
+	}
+
+	require.Nil(t, store.RemovePushSubscription(user, sub.Endpoint))
+	require.Nil(t, store.RemovePushSubscription(user, "https://push.example.com/missing"))
+
+	subs, err = store.PushSubscriptions(user)
+	require.Nil(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, "https://push.example.com/2", subs[0].Endpoint)
+
+	subs, err = store.PushSubscriptions(other)
+	require.Nil(t, err)
+	assert.Len(t, subs, 1)
+}
+
+func testIgnoreRules(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	rules, err := store.IgnoreRules(user)
+	require.Nil(t, err)
+	assert.Len(t, rules, 0)
+
+	ids := []string{betterguid.New(), betterguid.New()}
+	require.Nil(t, store.SaveIgnoreRule(user, &storage.IgnoreRule{
+		ID:      ids[0],
+		Nick:    "troll",
+		Network: "freenode",
+		Types:   []string{storage.IgnoreMessages},
+	}))
+	require.Nil(t, store.SaveIgnoreRule(user, &storage.IgnoreRule{ID: ids[1], Mask: "*!*@spam.example.com"}))
+	require.Nil(t, store.SaveIgnoreRule(other, &storage.IgnoreRule{ID: betterguid.New(), Nick: "bob"}))
+
+	rules, err = store.IgnoreRules(user)
+	require.Nil(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, ids[0], rules[0].ID)
+	assert.Equal(t, "troll", rules[0].Nick)
+	assert.Equal(t, []string{storage.IgnoreMessages}, rules[0].Types)
+	assert.Equal(t, "*!*@spam.example.com", rules[1].Mask)
+
+	require.Nil(t, store.RemoveIgnoreRule(user, ids[0]))
+	require.Nil(t, store.RemoveIgnoreRule(user, "missing"))
+
+	rules, err = store.IgnoreRules(user)
+	require.Nil(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, ids[1], rules[0].ID)
+
+	rules, err = store.IgnoreRules(other)
+	require.Nil(t, err)
+	assert.Len(t, rules, 1)
+}
+
+func testReadMarkers(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	_, err := store.ReadMarker(user, "freenode", "#go")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	markers, err := store.ReadMarkers(user)
+	require.Nil(t, err)
+	assert.Len(t, markers, 0)
+
+	marker := &storage.ReadMarker{Network: "freenode", Channel: "#go", ID: betterguid.New(), Time: 1}
+	require.Nil(t, store.SaveReadMarker(user, marker))
+	require.Nil(t, store.SaveReadMarker(user, &storage.ReadMarker{Network: "freenode", Channel: "bob", Time: 2}))
+	require.Nil(t, store.SaveReadMarker(other, &storage.ReadMarker{Network: "freenode", Channel: "#go", Time: 3}))
+
+	marker.ID = betterguid.New()
+	marker.Time = 4
+	require.Nil(t, store.SaveReadMarker(user, marker))
+
+	stored, err := store.ReadMarker(user, "freenode", "#go")
+	require.Nil(t, err)
+	assert.Equal(t, marker, stored)
+
+	markers, err = store.ReadMarkers(user)
+	require.Nil(t, err)
+	assert.Len(t, markers, 2)
+
+	stored, err = store.ReadMarker(other, "freenode", "#go")
+	require.Nil(t, err)
+	assert.Equal(t, int64(3), stored.Time)
+}
+
+func testAPITokens(t *testing.T, store storage.Store) {
+	user := newUser(t, store)
+	other := newUser(t, store)
+
+	tokens, err := store.APITokens(user)
+	require.Nil(t, err)
+	assert.Len(t, tokens, 0)
+
+	token := &storage.APIToken{
+		ID:      betterguid.New(),
+		Name:    "ci",
+		Hash:    []byte{1, 2, 3},
+		Scopes:  []string{storage.ScopeSendMessages},
+		Created: 1,
+	}
+	require.Nil(t, store.SaveAPIToken(user, token))
+	require.Nil(t, store.SaveAPIToken(user, &storage.APIToken{ID: betterguid.New(), Name: "logs"}))
+	require.Nil(t, store.SaveAPIToken(other, &storage.APIToken{ID: betterguid.New(), Name: "other"}))
+
+	token.LastUsed = 2
+	require.Nil(t, store.SaveAPIToken(user, token))
+
+	tokens, err = store.APITokens(user)
+	require.Nil(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, token, tokens[0])
+	assert.Equal(t, "logs", tokens[1].Name)
+
+	require.Nil(t, store.RemoveAPIToken(user, token.ID))
+	require.Nil(t, store.RemoveAPIToken(user, "missing"))
+
+	tokens, err = store.APITokens(user)
+	require.Nil(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "logs", tokens[0].Name)
+
+	tokens, err = store.APITokens(other)
+	require.Nil(t, err)
+	assert.Len(t, tokens, 1)
+}
+
+func testDeleteUser(t *testing.T, store storage.Store) {
+	users := []*storage.User{newUser(t, store), newUser(t, store)}
+
+	for _, user := range users {
+		require.Nil(t, store.SaveNetwork(user, &storage.Network{ID: "freenode"}))
+		require.Nil(t, store.SaveChannel(user, &storage.Channel{Network: "freenode", Name: "#go"}))
+		require.Nil(t, store.AddOpenDM(user, "freenode", "bob"))
+		require.Nil(t, store.SaveHighlightRules(user, storage.DefaultHighlightRules()))
+		require.Nil(t, store.SaveMention(user, &storage.Mention{ID: betterguid.New()}))
+		require.Nil(t, store.SavePushSubscription(user, &storage.PushSubscription{Endpoint: "https://push.example.com"}))
+		require.Nil(t, store.SaveIgnoreRule(user, &storage.IgnoreRule{ID: betterguid.New(), Nick: "troll"}))
+		require.Nil(t, store.SaveReadMarker(user, &storage.ReadMarker{Network: "freenode", Channel: "#go"}))
+		require.Nil(t, store.SaveAPIToken(user, &storage.APIToken{ID: betterguid.New()}))
+		require.Nil(t, store.SaveNetworkRename(user, "freenode", "new"))
+	}
+
+	require.Nil(t, store.DeleteUser(users[0]))
+
+	remaining, err := store.Users()
+	require.Nil(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, users[1].ID, remaining[0].ID)
+
+	networks, err := store.Networks(users[0])
+	require.Nil(t, err)
+	assert.Len(t, networks, 0)
+
+	channels, err := store.Channels(users[0])
+	require.Nil(t, err)
+	assert.Len(t, channels, 0)
+
+	openDMs, err := store.OpenDMs(users[0])
+	require.Nil(t, err)
+	assert.Len(t, openDMs, 0)
+
+	_, err = store.HighlightRules(users[0])
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	mentions, err := store.Mentions(users[0])
+	require.Nil(t, err)
+	assert.Len(t, mentions, 0)
+
+	subs, err := store.PushSubscriptions(users[0])
+	require.Nil(t, err)
+	assert.Len(t, subs, 0)
+
+	ignores, err := store.IgnoreRules(users[0])
+	require.Nil(t, err)
+	assert.Len(t, ignores, 0)
+
+	markers, err := store.ReadMarkers(users[0])
+	require.Nil(t, err)
+	assert.Len(t, markers, 0)
+
+	tokens, err := store.APITokens(users[0])
+	require.Nil(t, err)
+	assert.Len(t, tokens, 0)
+
+	renames, err := store.NetworkRenames(users[0])
+	require.Nil(t, err)
+	assert.Len(t, renames, 0)
+
+	networks, err = store.Networks(users[1])
+	require.Nil(t, err)
+	assert.Len(t, networks, 1)
+
+	channels, err = store.Channels(users[1])
+	require.Nil(t, err)
+	assert.Len(t, channels, 1)
+
+	openDMs, err = store.OpenDMs(users[1])
+	require.Nil(t, err)
+	assert.Len(t, openDMs, 1)
+
+	_, err = store.HighlightRules(users[1])
+	assert.Nil(t, err)
+
+	mentions, err = store.Mentions(users[1])
+	require.Nil(t, err)
+	assert.Len(t, mentions, 1)
+
+	subs, err = store.PushSubscriptions(users[1])
+	require.Nil(t, err)
+	assert.Len(t, subs, 1)
+
+	ignores, err = store.IgnoreRules(users[1])
+	require.Nil(t, err)
+	assert.Len(t, ignores, 1)
+
+	markers, err = store.ReadMarkers(users[1])
+	require.Nil(t, err)
+	assert.Len(t, markers, 1)
+
+	tokens, err = store.APITokens(users[1])
+	require.Nil(t, err)
+	assert.Len(t, tokens, 1)
+
+	renames, err = store.NetworkRenames(users[1])
+	require.Nil(t, err)
+	assert.Len(t, renames, 1)
+}
+
+// logMessages logs n messages to a channel and returns their IDs in the
+// order they were logged
+func logMessages(t *testing.T, store storage.MessageStore, network, channel string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = betterguid.New()
+		require.Nil(t, store.LogMessage(&storage.Message{
+			ID:      ids[i],
+			Network: network,
+			To:      channel,
+			From:    "nick",
+			Content: channel + " message" + strconv.Itoa(i),
+			Time:    int64(i+1) * 100,
+		}))
+	}
+	return ids
+}
+
+func messageIDs(messages []storage.Message) []string {
+	ids := make([]string, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+	return ids
+}
+
+func testMessages(t *testing.T, store storage.MessageStore) {
+	messages, hasMore, err := store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 0)
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 10, betterguid.New())
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 0)
+
+	ids := logMessages(t, store, "freenode", "#go", 5)
+
+	// The latest messages, oldest first
+	messages, hasMore, err = store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, ids, messageIDs(messages))
+	assert.Equal(t, "nick", messages[0].From)
+	assert.Equal(t, "#go message0", messages[0].Content)
+	assert.Equal(t, int64(100), messages[0].Time)
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 5, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, ids, messageIDs(messages))
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 3, "")
+	require.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, ids[2:], messageIDs(messages))
+
+	// Paging backwards from a message leaves it out
+	messages, hasMore, err = store.Messages("freenode", "#go", 2, ids[3])
+	require.Nil(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, ids[1:3], messageIDs(messages))
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 2, ids[2])
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, ids[:2], messageIDs(messages))
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 10, ids[2])
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, ids[:2], messageIDs(messages))
+
+	messages, hasMore, err = store.Messages("freenode", "#go", 10, ids[0])
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 0)
+
+	// An ID that is newer than all the messages pages from the end
+	messages, hasMore, err = store.Messages("freenode", "#go", 10, betterguid.New())
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, ids, messageIDs(messages))
+}
+
+func testMessageChannels(t *testing.T, store storage.MessageStore) {
+	// The names of these channels and networks start with each other
+	goIDs := logMessages(t, store, "freenode", "#go", 3)
+	goNutsIDs := logMessages(t, store, "freenode", "#go-nuts", 2)
+	otherIDs := logMessages(t, store, "freenode-2", "#go", 4)
+
+	messages, hasMore, err := store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, goIDs, messageIDs(messages))
+
+	messages, _, err = store.Messages("freenode", "#go-nuts", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, goNutsIDs, messageIDs(messages))
+
+	messages, _, err = store.Messages("freenode-2", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, otherIDs, messageIDs(messages))
+
+	messages, _, err = store.Messages("freenode", "#rust", 10, "")
+	require.Nil(t, err)
+	assert.Len(t, messages, 0)
+}
+
+func testUpdateMessage(t *testing.T, store storage.MessageStore) {
+	// Events get added to the last message and it gets logged again
+	msg := &storage.Message{
+		ID:      betterguid.New(),
+		Network: "freenode",
+		To:      "#go",
+		Time:    100,
+		Events: []storage.Event{
+			{Type: "join", Params: []string{"bob"}, Time: 100},
+		},
+	}
+	require.Nil(t, store.LogMessage(msg))
+
+	msg.Events = append(msg.Events, storage.Event{Type: "quit", Params: []string{"bob", "bye"}, Time: 200})
+	require.Nil(t, store.LogMessage(msg))
+
+	messages, hasMore, err := store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, messages, 1)
+	assert.Equal(t, msg.ID, messages[0].ID)
+	assert.Equal(t, msg.Events, messages[0].Events)
+
+	// The store does not share memory with the messages passed to it
+	msg.Events[0].Params[0] = "changed"
+	messages, _, err = store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, "bob", messages[0].Events[0].Params[0])
+}
+
+func testMessagesByID(t *testing.T, store storage.MessageStore) {
+	ids := logMessages(t, store, "freenode", "#go", 5)
+	logMessages(t, store, "freenode", "#go-nuts", 2)
+
+	messages, err := store.MessagesByID("freenode", "#go", []string{ids[3], "missing", ids[1]})
+	require.Nil(t, err)
+	assert.Equal(t, []string{ids[3], ids[1]}, messageIDs(messages))
+	assert.Equal(t, "#go message3", messages[0].Content)
+
+	messages, err = store.MessagesByID("freenode", "#go-nuts", []string{ids[0]})
+	require.Nil(t, err)
+	assert.Len(t, messages, 0)
+
+	messages, err = store.MessagesByID("freenode", "#missing", ids)
+	require.Nil(t, err)
+	assert.Len(t, messages, 0)
+}
+
+func testForEachMessage(t *testing.T, store storage.MessageStore) {
+	logged := map[storage.Tab][]string{
+		{Network: "freenode", Name: "#go"}:      logMessages(t, store, "freenode", "#go", 3),
+		{Network: "freenode", Name: "#go-nuts"}: logMessages(t, store, "freenode", "#go-nuts", 2),
+		{Network: "oftc", Name: "#go"}:          logMessages(t, store, "oftc", "#go", 1),
+	}
+
+	var messages []storage.Message
+	err := store.ForEachMessage(func(msg *storage.Message) error {
+		messages = append(messages, *msg)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, messages, 6)
+
+	// The messages of a channel come in a row, in the order they were logged
+	seen := map[storage.Tab][]string{}
+	var prev storage.Tab
+	for i, msg := range messages {
+		tab := storage.Tab{Network: msg.Network, Name: msg.To}
+		if i > 0 && tab != prev {
+			assert.NotContains(t, seen, tab, "the messages of %v are split up", tab)
+		}
+		seen[tab] = append(seen[tab], msg.ID)
+		prev = tab
+	}
+	assert.Equal(t, logged, seen)
+
+	count := 0
+	err = store.ForEachMessage(func(msg *storage.Message) error {
+		count++
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, 1, count)
+}
+
+func testPruneMessages(t *testing.T, store storage.MessageStore) {
+	goIDs := logMessages(t, store, "freenode", "#go", 10)
+	rustIDs := logMessages(t, store, "freenode", "#rust", 10)
+	otherIDs := logMessages(t, store, "oftc", "#go", 3)
+
+	deleted, err := store.PruneMessages(func(network, channel string) (int64, int) {
+		return 0, 0
+	})
+	require.Nil(t, err)
+	assert.Len(t, deleted, 0)
+
+	deleted, err = store.PruneMessages(func(network, channel string) (int64, int) {
+		if network == "oftc" {
+			return 0, 0
+		}
+		if channel == "#go" {
+			return 0, 4
+		}
+		// The messages were logged at 100, 200...
+		return 350, 0
+	})
+	require.Nil(t, err)
+	assert.ElementsMatch(t, append(append([]string{}, goIDs[:6]...), rustIDs[:3]...), deleted)
+
+	messages, hasMore, err := store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, goIDs[6:], messageIDs(messages))
+
+	messages, _, err = store.Messages("freenode", "#rust", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, rustIDs[3:], messageIDs(messages))
+
+	messages, _, err = store.Messages("oftc", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, otherIDs, messageIDs(messages))
+
+	// Both limits at once, whichever deletes more wins
+	deleted, err = store.PruneMessages(func(network, channel string) (int64, int) {
+		if channel == "#rust" {
+			return 550, 6
+		}
+		return 0, 0
+	})
+	require.Nil(t, err)
+	assert.ElementsMatch(t, rustIDs[3:5], deleted)
+
+	deleted, err = store.PruneMessages(func(network, channel string) (int64, int) {
+		if channel == "#rust" {
+			return 0, 2
+		}
+		return 0, 0
+	})
+	require.Nil(t, err)
+	assert.ElementsMatch(t, rustIDs[5:8], deleted)
+
+	// Everything can go
+	deleted, err = store.PruneMessages(func(network, channel string) (int64, int) {
+		return 10000, 0
+	})
+	require.Nil(t, err)
+	assert.Len(t, deleted, 4+2+3)
+
+	messages, hasMore, err = store.Messages("freenode", "#rust", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 0)
+}
+
+func idBytes(id uint64) []byte {
+	b := make([]byte, 8)
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(id)
+		id >>= 8
+	}
+	return b
+}
+
+func testCountUnread(t *testing.T, store storage.MessageStore) {
+	count, err := store.CountUnread("freenode", "#go", nil, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	ids := logMessages(t, store, "freenode", "#go", 5)
+	logMessages(t, store, "freenode", "#go-nuts", 3)
+
+	// Messages without content are events, they are never unread
+	require.Nil(t, store.LogMessage(&storage.Message{
+		ID:      betterguid.New(),
+		Network: "freenode",
+		To:      "#go",
+		Time:    600,
+		Events:  []storage.Event{{Type: "join", Params: []string{"bob"}, Time: 600}},
+	}))
+
+	count, err = store.CountUnread("freenode", "#go", nil, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	count, err = store.CountUnread("freenode", "#go", nil, 3)
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	// The message the marker is at has been read
+	count, err = store.CountUnread("freenode", "#go", &storage.ReadMarker{ID: ids[2]}, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = store.CountUnread("freenode", "#go", &storage.ReadMarker{ID: ids[2]}, 1)
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = store.CountUnread("freenode", "#go", &storage.ReadMarker{ID: ids[4]}, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	// Markers without an ID go by time, the messages were logged at 100,
+	// 200...
+	count, err = store.CountUnread("freenode", "#go", &storage.ReadMarker{Time: 300}, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = store.CountUnread("freenode", "#go", &storage.ReadMarker{Time: 600}, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = store.CountUnread("freenode", "#rust", nil, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func testRenameNetwork(t *testing.T, store storage.MessageStore) {
+	// The ID of the second network starts with the ID of the first one,
+	// renaming the first one can not touch the second one
+	goIDs := logMessages(t, store, "freenode", "#go", 3)
+	goNutsIDs := logMessages(t, store, "freenode", "#go-nuts", 2)
+	otherIDs := logMessages(t, store, "freenode-2", "#go", 2)
+
+	require.Nil(t, store.RenameNetwork("freenode", "new"))
+
+	messages, hasMore, err := store.Messages("new", "#go", 10, "")
+	require.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, goIDs, messageIDs(messages))
+	assert.Equal(t, "#go message0", messages[0].Content)
+
+	messages, _, err = store.Messages("new", "#go-nuts", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, goNutsIDs, messageIDs(messages))
+
+	messages, _, err = store.Messages("freenode", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Len(t, messages, 0)
+
+	messages, _, err = store.Messages("freenode-2", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, otherIDs, messageIDs(messages))
+
+	// Messages logged after the rename come after the moved ones
+	newIDs := logMessages(t, store, "new", "#go", 2)
+	messages, _, err = store.Messages("new", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Equal(t, append(goIDs, newIDs...), messageIDs(messages))
+
+	networks := map[string]int{}
+	err = store.ForEachMessage(func(msg *storage.Message) error {
+		networks[msg.Network]++
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]int{"new": 7, "freenode-2": 2}, networks)
+
+	require.Nil(t, store.RenameNetwork("missing", "other"))
+	messages, _, err = store.Messages("other", "#go", 10, "")
+	require.Nil(t, err)
+	assert.Len(t, messages, 0)
+}
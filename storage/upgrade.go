@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errInvalidUser = errors.New("invalid user data")
+
+// AddLinkPreviewsSetting upgrades the data of a user stored before
+// ClientSettings.LinkPreviews got added, link previews get enabled
+func AddLinkPreviewsSetting(data []byte) ([]byte, error) {
+	// ID, then the length of the username and the username
+	i := 8
+	if len(data) < i {
+		return nil, errInvalidUser
+	}
+	l, n := binary.Uvarint(data[i:])
+	if n <= 0 {
+		return nil, errInvalidUser
+	}
+	i += n + int(l)
+
+	// clientSettings is a pointer, a zero byte means it is nil and there
+	// is nothing to upgrade
+	if len(data) < i+1 {
+		return nil, errInvalidUser
+	}
+	if data[i] == 0 {
+		return data, nil
+	}
+
+	// The byte after ColoredNicks
+	i += 2
+	if len(data) < i {
+		return nil, errInvalidUser
+	}
+
+	upgraded := make([]byte, 0, len(data)+1)
+	upgraded = append(upgraded, data[:i]...)
+	upgraded = append(upgraded, 1)
+	return append(upgraded, data[i:]...), nil
+}
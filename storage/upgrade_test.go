@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLinkPreviewsSetting(t *testing.T) {
+	user := &User{
+		ID:       1,
+		Username: "1",
+		clientSettings: &ClientSettings{
+			ColoredNicks: true,
+			LinkPreviews: true,
+		},
+		lastIP: []byte{127, 0, 0, 1},
+	}
+	data, err := user.Marshal(nil)
+	require.Nil(t, err)
+
+	// The byte after ColoredNicks is LinkPreviews
+	old := append([]byte{}, data[:11]...)
+	old = append(old, data[12:]...)
+
+	upgraded, err := AddLinkPreviewsSetting(old)
+	require.Nil(t, err)
+	assert.Equal(t, data, upgraded)
+
+	stored := &User{}
+	_, err = stored.Unmarshal(upgraded)
+	require.Nil(t, err)
+	assert.Equal(t, user.clientSettings, stored.clientSettings)
+	assert.Equal(t, user.lastIP, stored.lastIP)
+
+	user.clientSettings = nil
+	data, err = user.Marshal(nil)
+	require.Nil(t, err)
+
+	upgraded, err = AddLinkPreviewsSetting(data)
+	require.Nil(t, err)
+	assert.Equal(t, data, upgraded)
+
+	_, err = AddLinkPreviewsSetting([]byte{1, 0, 0})
+	assert.NotNil(t, err)
+}
@@ -20,9 +20,14 @@ type User struct {
 	messageIndex   MessageSearchProvider
 	lastMessages   map[string]map[string]*Message
 	clientSettings *ClientSettings
+	highlights     *Highlighter
+	ignores        *Ignorer
 	lastIP         []byte
 	certificate    *tls.Certificate
 	lock           sync.Mutex
+	// readMarkerLock keeps read markers from moving backwards when they
+	// get set at the same time
+	readMarkerLock sync.Mutex
 }
 
 func NewUser(store Store) (*User, error) {
@@ -37,13 +42,15 @@ func NewUser(store Store) (*User, error) {
 		return nil, err
 	}
 
-	err = os.MkdirAll(Path.User(user.Username), 0700)
-	if err != nil {
-		return nil, err
-	}
-	err = os.Mkdir(Path.Downloads(user.Username), 0700)
-	if err != nil {
-		return nil, err
+	if !InMemory {
+		err = os.MkdirAll(Path.User(user.Username), 0700)
+		if err != nil {
+			return nil, err
+		}
+		err = os.Mkdir(Path.Downloads(user.Username), 0700)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	user.messageLog, err = GetMessageStore(user)
@@ -77,6 +84,11 @@ func LoadUsers(store Store) ([]*User, error) {
 		user.lastMessages = map[string]map[string]*Message{}
 		user.loadCertificate()
 
+		err = user.migrateNetworkIDs()
+		if err != nil {
+			return nil, err
+		}
+
 		channels, err := user.Channels()
 		if err != nil {
 			return nil, err
@@ -105,7 +117,9 @@ func (u *User) Remove() {
 	if u.messageIndex != nil {
 		u.messageIndex.Close()
 	}
-	os.RemoveAll(Path.User(u.Username))
+	if !InMemory {
+		os.RemoveAll(Path.User(u.Username))
+	}
 }
 
 func (u *User) GetLastIP() []byte {
@@ -126,11 +140,15 @@ func (u *User) SetLastIP(ip []byte) error {
 //easyjson:json
 type ClientSettings struct {
 	ColoredNicks bool
+	// LinkPreviews enables fetching the title, description and image of
+	// links in messages
+	LinkPreviews bool
 }
 
 func DefaultClientSettings() *ClientSettings {
 	return &ClientSettings{
 		ColoredNicks: true,
+		LinkPreviews: true,
 	}
 }
 
@@ -174,8 +192,8 @@ func (u *User) NewNetwork(template *Network, client *irc.Client) *Network {
 	return template
 }
 
-func (u *User) Network(address string) (*Network, error) {
-	return u.store.Network(u, address)
+func (u *User) Network(id string) (*Network, error) {
+	return u.store.Network(u, id)
 }
 
 func (u *User) Networks() ([]*Network, error) {
@@ -186,12 +204,12 @@ func (u *User) SaveNetwork(network *Network) error {
 	return u.store.SaveNetwork(u, network)
 }
 
-func (u *User) RemoveNetwork(address string) error {
-	return u.store.RemoveNetwork(u, address)
+func (u *User) RemoveNetwork(id string) error {
+	return u.store.RemoveNetwork(u, id)
 }
 
-func (u *User) SetNick(nick, address string) error {
-	network, err := u.Network(address)
+func (u *User) SetNick(nick, id string) error {
+	network, err := u.Network(id)
 	if err != nil {
 		return err
 	}
@@ -199,8 +217,8 @@ func (u *User) SetNick(nick, address string) error {
 	return u.SaveNetwork(network)
 }
 
-func (u *User) SetNetworkName(name, address string) error {
-	network, err := u.Network(address)
+func (u *User) SetNetworkName(name, id string) error {
+	network, err := u.Network(id)
 	if err != nil {
 		return err
 	}
@@ -242,17 +260,13 @@ func (u *User) RemoveOpenDM(network, nick string) error {
 }
 
 type Message struct {
-	ID      string  `json:"-" bleve:"-"`
-	Network string  `json:"-" bleve:"server"`
-	From    string  `bleve:"-"`
-	To      string  `json:"-" bleve:"to"`
-	Content string  `bleve:"content"`
-	Time    int64   `bleve:"-"`
-	Events  []Event `bleve:"-"`
-}
-
-func (m Message) Type() string {
-	return "message"
+	ID      string `json:"-"`
+	Network string `json:"-"`
+	From    string
+	To      string `json:"-"`
+	Content string
+	Time    int64
+	Events  []Event
 }
 
 func (u *User) LogMessage(msg *Message) error {
@@ -283,6 +297,35 @@ type Event struct {
 	Time   int64
 }
 
+// Nicks returns the users involved in the event, the params are in the
+// order the server passes them to LogEvent
+func (e Event) Nicks() []string {
+	return e.Params[:e.nickCount()]
+}
+
+// Text returns the free form part of the event, like a topic or a reason
+func (e Event) Text() string {
+	if n := e.nickCount(); len(e.Params) > n {
+		return e.Params[n]
+	}
+	return ""
+}
+
+func (e Event) nickCount() int {
+	n := 0
+	switch e.Type {
+	case "join", "part", "quit", "topic":
+		n = 1
+	case "nick", "kick":
+		n = 2
+	}
+
+	if n > len(e.Params) {
+		n = len(e.Params)
+	}
+	return n
+}
+
 func (u *User) LogEvent(network, name string, params []string, channels ...string) error {
 	now := time.Now().Unix()
 	event := Event{
@@ -294,7 +337,7 @@ func (u *User) LogEvent(network, name string, params []string, channels ...strin
 	for _, channel := range channels {
 		lastMessage := u.getLastMessage(network, channel)
 
-		if lastMessage != nil && shouldCollapse(lastMessage, event) {
+		if lastMessage != nil && ShouldCollapse(lastMessage, event) {
 			lastMessage.Events = append(lastMessage.Events, event)
 			u.setLastMessage(network, channel, lastMessage)
 
@@ -302,6 +345,11 @@ func (u *User) LogEvent(network, name string, params []string, channels ...strin
 			if err != nil {
 				return err
 			}
+
+			err = u.messageIndex.Index(lastMessage.ID, lastMessage)
+			if err != nil {
+				return err
+			}
 		} else {
 			msg := &Message{
 				ID:      betterguid.New(),
@@ -316,6 +364,11 @@ func (u *User) LogEvent(network, name string, params []string, channels ...strin
 			if err != nil {
 				return err
 			}
+
+			err = u.messageIndex.Index(msg.ID, msg)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -324,7 +377,9 @@ func (u *User) LogEvent(network, name string, params []string, channels ...strin
 
 var collapsed = []string{"join", "part", "quit", "nick"}
 
-func shouldCollapse(msg *Message, event Event) bool {
+// ShouldCollapse reports whether event gets added to msg instead of being
+// logged as a new message
+func ShouldCollapse(msg *Message, event Event) bool {
 	matches := 0
 	if len(msg.Events) > 0 {
 		for _, collapseType := range collapsed {
@@ -366,14 +421,60 @@ func (u *User) setLastMessage(network, channel string, msg *Message) {
 	u.lock.Unlock()
 }
 
+// PruneMessages deletes the oldest messages in each channel from the message
+// log and the search index, it returns the number of messages deleted
+func (u *User) PruneMessages(limits func(network, channel string) (before int64, keep int)) (int, error) {
+	ids, err := u.messageLog.PruneMessages(limits)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	err = u.messageIndex.DeleteMessages(ids)
+	if err != nil {
+		return len(ids), err
+	}
+
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	// Events get added to the last message in a channel, a deleted one
+	// would get logged again
+	u.lock.Lock()
+	for _, channels := range u.lastMessages {
+		for channel, msg := range channels {
+			if deleted[msg.ID] {
+				delete(channels, channel)
+			}
+		}
+	}
+	u.lock.Unlock()
+
+	if compacter, ok := u.messageLog.(MessageStoreCompacter); ok {
+		err = compacter.Compact()
+	}
+
+	return len(ids), err
+}
+
 func (u *User) Messages(network, channel string, count int, fromID string) ([]Message, bool, error) {
 	return u.messageLog.Messages(network, channel, count, fromID)
 }
 
+// ForEachMessage calls fn with every message the user has logged
+func (u *User) ForEachMessage(fn func(*Message) error) error {
+	return u.messageLog.ForEachMessage(fn)
+}
+
 func (u *User) LastMessages(network, channel string, count int) ([]Message, bool, error) {
 	return u.Messages(network, channel, count, "")
 }
 
+func (u *User) MessagesByID(network, channel string, ids []string) ([]Message, error) {
+	return u.messageLog.MessagesByID(network, channel, ids)
+}
+
 func (u *User) SearchMessages(network, channel, q string) ([]Message, error) {
 	ids, err := u.messageIndex.SearchMessages(network, channel, q)
 	if err != nil {
@@ -28,6 +28,10 @@ func (u *User) SetCertificate(certPEM, keyPEM []byte) error {
 	u.certificate = &cert
 	u.lock.Unlock()
 
+	if InMemory {
+		return nil
+	}
+
 	err = ioutil.WriteFile(Path.Certificate(u.Username), certPEM, 0600)
 	if err != nil {
 		return ErrCouldNotSaveCert
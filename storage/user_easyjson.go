@@ -38,6 +38,8 @@ func easyjson9e1087fdDecodeGithubComKhliengDispatchStorage(in *jlexer.Lexer, out
 		switch key {
 		case "coloredNicks":
 			out.ColoredNicks = bool(in.Bool())
+		case "linkPreviews":
+			out.LinkPreviews = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -58,6 +60,16 @@ func easyjson9e1087fdEncodeGithubComKhliengDispatchStorage(out *jwriter.Writer,
 		out.RawString(prefix[1:])
 		out.Bool(bool(in.ColoredNicks))
 	}
+	if in.LinkPreviews {
+		const prefix string = ",\"linkPreviews\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.LinkPreviews))
+	}
 	out.RawByte('}')
 }
 
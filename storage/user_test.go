@@ -1,16 +1,28 @@
 package storage_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/khlieng/dispatch/storage"
 	"github.com/khlieng/dispatch/storage/bleve"
 	"github.com/khlieng/dispatch/storage/boltdb"
+	"github.com/khlieng/dispatch/storage/memory"
+	"github.com/khlieng/dispatch/storage/sqlite"
 	"github.com/kjk/betterguid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func tempdir() string {
@@ -18,15 +30,59 @@ func tempdir() string {
 	return f
 }
 
-func TestUser(t *testing.T) {
-	storage.Initialize(tempdir(), "", "")
+type testStore interface {
+	storage.Store
+	Close()
+}
 
-	db, err := boltdb.New(storage.Path.Database())
-	assert.Nil(t, err)
+// backends open the stores the tests run against
+var backends = []struct {
+	name string
+	open func() (testStore, storage.MessageStoreCreator, error)
+}{
+	{"bolt", func() (testStore, storage.MessageStoreCreator, error) {
+		db, err := boltdb.New(storage.Path.Database())
+		return db, func(_ *storage.User) (storage.MessageStore, error) {
+			return db, nil
+		}, err
+	}},
+	{"sqlite", func() (testStore, storage.MessageStoreCreator, error) {
+		db, err := sqlite.New(storage.Path.SQLite())
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, func(user *storage.User) (storage.MessageStore, error) {
+			return db.MessageStore(user), nil
+		}, nil
+	}},
+	{"memory", func() (testStore, storage.MessageStoreCreator, error) {
+		db := memory.New()
+		return db, func(user *storage.User) (storage.MessageStore, error) {
+			return db.MessageStore(user), nil
+		}, nil
+	}},
+}
+
+func forEachBackend(t *testing.T, test func(*testing.T, testStore)) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storage.Initialize(tempdir(), "", "")
 
-	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
-		return db, nil
+			db, messageStore, err := backend.open()
+			require.Nil(t, err)
+			defer db.Close()
+
+			storage.GetMessageStore = messageStore
+			test(t, db)
+		})
 	}
+}
+
+func TestUser(t *testing.T) {
+	forEachBackend(t, testUser)
+}
+
+func testUser(t *testing.T, db testStore) {
 	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
 		return nil, nil
 	}
@@ -35,16 +91,17 @@ func TestUser(t *testing.T) {
 	assert.Nil(t, err)
 
 	srv := &storage.Network{
+		ID:   "freenode",
 		Name: "freenode",
 		Host: "irc.freenode.net",
 		Nick: "test",
 	}
 	chan1 := &storage.Channel{
-		Network: srv.Host,
+		Network: srv.ID,
 		Name:    "#test",
 	}
 	chan2 := &storage.Channel{
-		Network: srv.Host,
+		Network: srv.ID,
 		Name:    "#testing",
 	}
 
@@ -68,30 +125,48 @@ func TestUser(t *testing.T) {
 	assert.Equal(t, chan1, channels[0])
 	assert.Equal(t, chan2, channels[1])
 
-	user.SetNick("bob", srv.Host)
+	user.SetNick("bob", srv.ID)
 	servers, err = user.Networks()
 	assert.Equal(t, "bob", servers[0].Nick)
 
-	user.SetNetworkName("cake", srv.Host)
+	user.SetNetworkName("cake", srv.ID)
 	servers, err = user.Networks()
 	assert.Equal(t, "cake", servers[0].Name)
 
-	user.RemoveChannel(srv.Host, chan1.Name)
+	user.RemoveChannel(srv.ID, chan1.Name)
 	channels, err = user.Channels()
 	assert.Len(t, channels, 1)
 	assert.Equal(t, chan2, channels[0])
 
-	user.RemoveNetwork(srv.Host)
+	srv2 := &storage.Network{
+		ID:   srv.ID + "-2",
+		Host: srv.Host,
+	}
+	chan3 := &storage.Channel{
+		Network: srv2.ID,
+		Name:    "#test",
+	}
+	user.SaveNetwork(srv2)
+	user.SaveChannel(chan3)
+
+	user.RemoveNetwork(srv.ID)
+	servers, err = user.Networks()
+	assert.Len(t, servers, 1)
+	channels, err = user.Channels()
+	assert.Len(t, channels, 1)
+	assert.Equal(t, chan3, channels[0])
+
+	user.RemoveNetwork(srv2.ID)
 	servers, err = user.Networks()
 	assert.Len(t, servers, 0)
 	channels, err = user.Channels()
 	assert.Len(t, channels, 0)
 
-	user.AddOpenDM(srv.Host, "cake")
+	user.AddOpenDM(srv.ID, "cake")
 	openDMs, err := user.OpenDMs()
 	assert.Nil(t, err)
 	assert.Len(t, openDMs, 1)
-	err = user.RemoveOpenDM(srv.Host, "cake")
+	err = user.RemoveOpenDM(srv.ID, "cake")
 	assert.Nil(t, err)
 	openDMs, err = user.OpenDMs()
 	assert.Nil(t, err)
@@ -107,7 +182,7 @@ func TestUser(t *testing.T) {
 	assert.Equal(t, settings, user.ClientSettings())
 	assert.NotEqual(t, settings, storage.DefaultClientSettings())
 
-	user.AddOpenDM(srv.Host, "cake")
+	user.AddOpenDM(srv.ID, "cake")
 
 	user.Remove()
 	_, err = os.Stat(storage.Path.User(user.Username))
@@ -125,15 +200,136 @@ func TestUser(t *testing.T) {
 	}
 }
 
-func TestMessages(t *testing.T) {
-	storage.Initialize(tempdir(), "", "")
+func TestNewNetworkID(t *testing.T) {
+	id := storage.NewNetworkID()
+	assert.NotEmpty(t, id)
+	assert.NotContains(t, id, "freenode")
+	assert.NotEqual(t, id, storage.NewNetworkID())
+}
 
-	db, err := boltdb.New(storage.Path.Database())
-	assert.Nil(t, err)
+func TestMigrateNetworkIDs(t *testing.T) {
+	forEachBackend(t, testMigrateNetworkIDs)
+}
 
-	storage.GetMessageStore = func(_ *storage.User) (storage.MessageStore, error) {
-		return db, nil
+func testMigrateNetworkIDs(t *testing.T, db testStore) {
+	index := memory.NewIndex()
+	storage.GetMessageSearchProvider = func(_ *storage.User) (storage.MessageSearchProvider, error) {
+		return index, nil
 	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	// Networks used to get their host as ID, the store plans new IDs for
+	// them when it gets upgraded
+	planned := map[string]string{}
+	for _, id := range []string{"irc.freenode.net", "irc.freenode.net-2"} {
+		require.Nil(t, user.SaveNetwork(&storage.Network{ID: id, Host: "irc.freenode.net"}))
+		require.Nil(t, user.SaveChannel(&storage.Channel{Network: id, Name: "#go-nuts"}))
+		planned[id] = storage.NewNetworkID()
+		require.Nil(t, db.SaveNetworkRename(user, id, planned[id]))
+	}
+	// Networks added since then keep their ID even if it looks like a host
+	require.Nil(t, user.SaveNetwork(&storage.Network{ID: "irc.libera.chat", Host: "irc.libera.chat"}))
+
+	// A move that failed halfway gets finished with the same ID
+	require.Nil(t, user.SaveChannel(&storage.Channel{Network: planned["irc.freenode.net"], Name: "#go-nuts"}))
+	require.Nil(t, user.AddOpenDM("irc.freenode.net", "bob"))
+
+	msg := &storage.Message{ID: betterguid.New(), Network: "irc.freenode.net", From: "bob", To: "#go-nuts", Content: "hello"}
+	require.Nil(t, user.LogMessage(msg))
+	require.Nil(t, user.AddMention(msg))
+	_, err = user.SetReadMarker(&storage.ReadMarker{Network: "irc.freenode.net", Channel: "#go-nuts", ID: msg.ID})
+	require.Nil(t, err)
+	require.Nil(t, user.AddIgnoreRule(&storage.IgnoreRule{Nick: "spam", Network: "irc.freenode.net"}))
+	rules := storage.DefaultHighlightRules()
+	rules.Muted = []storage.Tab{{Network: "irc.freenode.net-2", Name: "#go-nuts"}}
+	require.Nil(t, user.SetHighlightRules(rules))
+
+	users, err := storage.LoadUsers(db)
+	require.Nil(t, err)
+	require.Len(t, users, 1)
+	user = users[0]
+
+	networks, err := user.Networks()
+	require.Nil(t, err)
+	require.Len(t, networks, 3)
+
+	ids := map[string]bool{}
+	for _, network := range networks {
+		if network.Host == "irc.libera.chat" {
+			assert.Equal(t, "irc.libera.chat", network.ID)
+			continue
+		}
+		ids[network.ID] = true
+	}
+	assert.Equal(t, map[string]bool{
+		planned["irc.freenode.net"]:   true,
+		planned["irc.freenode.net-2"]: true,
+	}, ids)
+
+	renames, err := db.NetworkRenames(user)
+	require.Nil(t, err)
+	assert.Len(t, renames, 0)
+
+	channels, err := user.Channels()
+	require.Nil(t, err)
+	require.Len(t, channels, 2)
+	for _, channel := range channels {
+		assert.Contains(t, ids, channel.Network)
+	}
+
+	openDMs, err := user.OpenDMs()
+	require.Nil(t, err)
+	require.Len(t, openDMs, 1)
+	freenode := openDMs[0].Network
+	assert.Equal(t, planned["irc.freenode.net"], freenode)
+
+	messages, _, err := user.LastMessages(freenode, "#go-nuts", 10)
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "hello", messages[0].Content)
+
+	res, err := user.Search(storage.SearchQuery{Network: freenode, Q: "hello"})
+	require.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+
+	mentions, err := user.Mentions(false)
+	require.Nil(t, err)
+	require.Len(t, mentions, 1)
+	assert.Equal(t, freenode, mentions[0].Network)
+
+	// The markers under the old ID are gone
+	markers, err := user.ReadMarkers()
+	require.Nil(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, freenode, markers[0].Network)
+
+	ignoreRules, err := user.IgnoreRules()
+	require.Nil(t, err)
+	require.Len(t, ignoreRules, 1)
+	assert.Equal(t, freenode, ignoreRules[0].Network)
+
+	rules, err = user.HighlightRules()
+	require.Nil(t, err)
+	require.Len(t, rules.Muted, 1)
+	assert.Equal(t, planned["irc.freenode.net-2"], rules.Muted[0].Network)
+
+	// Loading the users again changes nothing
+	users, err = storage.LoadUsers(db)
+	require.Nil(t, err)
+	networks, err = users[0].Networks()
+	require.Nil(t, err)
+	assert.Len(t, networks, 3)
+	_, err = users[0].Network(planned["irc.freenode.net"])
+	assert.Nil(t, err)
+}
+
+func TestMessages(t *testing.T) {
+	forEachBackend(t, testMessages)
+}
+
+func testMessages(t *testing.T, db testStore) {
 	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
 		return bleve.New(storage.Path.Index(user.Username))
 	}
@@ -247,5 +443,282 @@ func TestMessages(t *testing.T) {
 	assert.Equal(t, []string{"rob", "bored"}, messages[0].Events[3].Params)
 	assert.NotZero(t, messages[0].Events[3].Time)
 
-	db.Close()
+}
+
+func TestSearch(t *testing.T) {
+	forEachBackend(t, testSearch)
+}
+
+func testSearch(t *testing.T, db testStore) {
+	var index *bleve.Bleve
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		var err error
+		index, err = bleve.New(storage.Path.Index(user.Username))
+		return index, err
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	messages := []*storage.Message{
+		{Network: "freenode", From: "alice", To: "#go-nuts", Content: "gophers <3 channels", Time: 100},
+		{Network: "freenode", From: "Bob", To: "#go-nuts", Content: "channels are great", Time: 200},
+		{Network: "freenode", From: "bob", To: "#rust", Content: "no channels here", Time: 300},
+		{Network: "oftc", From: "alice", To: "#go-nuts", Content: "more channels", Time: 400},
+	}
+	for _, msg := range messages {
+		err = user.LogMessage(msg)
+		assert.Nil(t, err)
+	}
+	user.LogEvent("freenode", "kick", []string{"op", "bob", "spam"}, "#rust")
+
+	res, err := user.Search(storage.SearchQuery{Q: "channels"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), res.Total)
+	assert.Len(t, res.Hits, 4)
+
+	res, err = user.Search(storage.SearchQuery{Q: "channels", Network: "freenode", Channel: "#go-nuts"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+	for _, hit := range res.Hits {
+		assert.Equal(t, "freenode", hit.Network)
+		assert.Equal(t, "#go-nuts", hit.Channel)
+		assert.Equal(t, hit.ID, hit.Message.ID)
+	}
+
+	res, err = user.Search(storage.SearchQuery{Q: "channels", From: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+
+	res, err = user.Search(storage.SearchQuery{Q: "channels", Start: 200, End: 300})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+
+	res, err = user.Search(storage.SearchQuery{Q: "gophers"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "<mark>gophers</mark> &lt;3 channels", res.Hits[0].Fragments[0])
+	assert.Equal(t, "gophers <3 channels", res.Hits[0].Message.Content)
+	assert.Equal(t, "alice", res.Hits[0].Message.From)
+
+	res, err = user.Search(storage.SearchQuery{Q: "+content:channels -content:gophers"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), res.Total)
+
+	res, err = user.Search(storage.SearchQuery{Event: "kick"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "#rust", res.Hits[0].Channel)
+
+	user.LogEvent("oftc", "topic", []string{"alice", "Talk about gophers here"}, "#go-nuts")
+
+	res, err = user.Search(storage.SearchQuery{Q: "spam"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "kick", res.Hits[0].Message.Events[0].Type)
+	assert.Equal(t, []string{"<mark>spam</mark>"}, res.Hits[0].Fragments)
+
+	res, err = user.Search(storage.SearchQuery{Q: "gophers", Event: "topic"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+	assert.Equal(t, "oftc", res.Hits[0].Network)
+
+	res, err = user.Search(storage.SearchQuery{Q: "events.nicks:op"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Hits, 1)
+
+	res, err = user.Search(storage.SearchQuery{Nick: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), res.Total)
+
+	res, err = user.Search(storage.SearchQuery{From: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), res.Total)
+
+	res, err = user.Search(storage.SearchQuery{Q: "channels", Offset: 3, Limit: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), res.Total)
+	assert.Len(t, res.Hits, 1)
+
+	// Hits for messages missing from the log do not count
+	index.Index("missing", &storage.Message{ID: "missing", Network: "freenode", To: "#go-nuts", Content: "channels"})
+
+	res, err = user.Search(storage.SearchQuery{Q: "channels"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), res.Total)
+	assert.Len(t, res.Hits, 4)
+}
+
+func TestSearchWithoutContent(t *testing.T) {
+	forEachBackend(t, testSearchWithoutContent)
+}
+
+func testSearchWithoutContent(t *testing.T, db testStore) {
+	var index *bleve.Bleve
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		var err error
+		index, err = bleve.NewWithOptions(storage.Path.Index(user.Username), bleve.Options{NoContent: true})
+		return index, err
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+	assert.False(t, index.StoresContent())
+
+	content := "the gophers <3 channels and more gophers"
+	require.Nil(t, user.LogMessage(&storage.Message{Network: "freenode", From: "alice", To: "#go-nuts", Content: content}))
+
+	// The fragments come from the message in the log
+	res, err := user.Search(storage.SearchQuery{Q: "gophers"})
+	require.Nil(t, err)
+	require.Len(t, res.Hits, 1)
+	assert.Equal(t, []string{"the <mark>gophers</mark> &lt;3 channels and more <mark>gophers</mark>"}, res.Hits[0].Fragments)
+
+	index.Close()
+	err = filepath.Walk(storage.Path.Index(user.Username), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		assert.False(t, bytes.Contains(data, []byte(content)), path)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+func TestPruneMessages(t *testing.T) {
+	forEachBackend(t, testPruneMessages)
+}
+
+func testPruneMessages(t *testing.T, db testStore) {
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return bleve.New(storage.Path.Index(user.Username))
+	}
+
+	user, err := storage.NewUser(db)
+	assert.Nil(t, err)
+
+	os.MkdirAll(storage.Path.User(user.Username), 0700)
+
+	for i := 0; i < 10; i++ {
+		for _, channel := range []string{"#go-nuts", "#rust"} {
+			err = user.LogMessage(&storage.Message{
+				Network: "freenode",
+				From:    "nick",
+				To:      channel,
+				Content: "message" + strconv.Itoa(i),
+				Time:    int64(i+1) * 100,
+			})
+			assert.Nil(t, err)
+		}
+	}
+
+	n, err := user.PruneMessages(func(network, channel string) (int64, int) {
+		if channel == "#go-nuts" {
+			return 0, 4
+		}
+		return 350, 0
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 9, n)
+
+	messages, hasMore, err := user.LastMessages("freenode", "#go-nuts", 10)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 4)
+	assert.Equal(t, "message6", messages[0].Content)
+
+	messages, hasMore, err = user.LastMessages("freenode", "#rust", 10)
+	assert.Nil(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, messages, 7)
+	assert.Equal(t, "message3", messages[0].Content)
+
+	res, err := user.Search(storage.SearchQuery{Q: "message0"})
+	assert.Nil(t, err)
+	assert.Zero(t, res.Total)
+
+	n, err = user.PruneMessages(func(network, channel string) (int64, int) {
+		return 0, 0
+	})
+	assert.Nil(t, err)
+	assert.Zero(t, n)
+
+	err = user.LogMessage(&storage.Message{
+		Network: "freenode",
+		From:    "nick",
+		To:      "#rust",
+		Content: "after compaction",
+	})
+	assert.Nil(t, err)
+
+	users, err := db.Users()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+
+	// The user ID sequence has to survive the compaction
+	user, err = storage.NewUser(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), user.ID)
+
+}
+
+func TestInMemory(t *testing.T) {
+	// Nothing can be created below a file, not even by root
+	file, err := ioutil.TempFile("", "")
+	require.Nil(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	storage.Initialize(filepath.Join(file.Name(), "data"), "", "")
+	storage.InMemory = true
+	defer func() { storage.InMemory = false }()
+
+	db := memory.New()
+	storage.GetMessageStore = func(user *storage.User) (storage.MessageStore, error) {
+		return db.MessageStore(user), nil
+	}
+	storage.GetMessageSearchProvider = func(user *storage.User) (storage.MessageSearchProvider, error) {
+		return db.MessageSearchProvider(user), nil
+	}
+
+	user, err := storage.NewUser(db)
+	require.Nil(t, err)
+
+	certPEM, keyPEM := selfSignedCert(t)
+	assert.Nil(t, user.SetCertificate(certPEM, keyPEM))
+	assert.NotNil(t, user.GetCertificate())
+
+	require.Nil(t, user.LogMessage(&storage.Message{Network: "net", From: "nick", To: "#chan", Content: "hi"}))
+	messages, _, err := user.LastMessages("net", "#chan", 10)
+	require.Nil(t, err)
+	assert.Len(t, messages, 1)
+
+	user.Remove()
+	_, err = os.Stat(storage.Path.DataRoot())
+	assert.NotNil(t, err)
+}
+
+func selfSignedCert(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
 }